@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/textwidth"
+)
+
+var amountsCommand = &command{
+	name:    "amounts",
+	usage:   "[-format text|json] [path ...]",
+	summary: "report amounts that do not parse as numbers",
+	run:     runAmounts,
+}
+
+// unparsed is an amount text without a numeric factor, with the number of
+// times and the files it occurs in.
+type unparsed struct {
+	Amount string   `json:"amount"`
+	Count  int      `json:"count"`
+	Files  []string `json:"files"`
+}
+
+func runAmounts(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	format := fs.String("format", "text", "output `format`: text or json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	files, err := recipeFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	var total, parsed int
+	index := make(map[string]*unparsed)
+	var list []*unparsed
+	add := func(file string, a recipemd.Amount) {
+		total++
+		if a.Factor != nil {
+			parsed++
+			return
+		}
+		u := index[a.Unit]
+		if u == nil {
+			u = &unparsed{Amount: a.Unit}
+			index[a.Unit] = u
+			list = append(list, u)
+		}
+		u.Count++
+		if !slices.Contains(u.Files, file) {
+			u.Files = append(u.Files, file)
+		}
+	}
+	for _, f := range files {
+		r, err := parseFile(f)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", f, err)
+			continue
+		}
+		for _, y := range r.Yields {
+			add(f, y)
+		}
+		for _, i := range r.AllIngredients() {
+			if i.Amount != nil {
+				add(f, *i.Amount)
+			}
+		}
+	}
+	// most frequent first
+	slices.SortStableFunc(list, func(a, b *unparsed) int { return b.Count - a.Count })
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Total    int         `json:"total"`
+			Parsed   int         `json:"parsed"`
+			Unparsed []*unparsed `json:"unparsed"`
+		}{total, parsed, append([]*unparsed{}, list...)})
+	}
+	rows := [][3]string{{"COUNT", "AMOUNT", "FILES"}}
+	for _, u := range list {
+		rows = append(rows, [3]string{fmt.Sprint(u.Count), strconv.Quote(u.Amount), filesSummary(u.Files)})
+	}
+	// pad by display width so wide characters keep the columns aligned
+	var widths [2]int
+	for _, row := range rows {
+		for i := range widths {
+			widths[i] = max(widths[i], textwidth.Width(row[i]))
+		}
+	}
+	for _, row := range rows {
+		fmt.Fprintf(stdout, "%s  %s  %s\n", textwidth.Pad(row[0], widths[0]), textwidth.Pad(row[1], widths[1]), row[2])
+	}
+	coverage := 100.0
+	if total > 0 {
+		coverage = float64(parsed) / float64(total) * 100
+	}
+	_, err = fmt.Fprintf(stdout, "\n%d of %d amounts parsed (%.1f%%)\n", parsed, total, coverage)
+	return err
+}
+
+// filesSummary lists the first files and the number of the others.
+func filesSummary(files []string) string {
+	const shown = 3
+	if len(files) <= shown {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:shown], ", "), len(files)-shown)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+	"github.com/xcapaldi/recipemd-go/pkg/site"
+)
+
+var buildCommand = &command{
+	name:    "build",
+	usage:   "[-o dir] [-lang tag] [-watch] [dir]",
+	summary: "generate a static website from a directory of recipes",
+	run:     runBuild,
+}
+
+func runBuild(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	out := fs.String("o", "public", "write the site to `dir`")
+	watch := fs.Bool("watch", false, "keep running and update the site when recipes change")
+	lang := fs.String("lang", "", "head the sections of the recipes in the language `tag`, e.g. de")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	var opts []site.Option
+	if *lang != "" {
+		opts = append(opts, site.WithHTMLOptions(extension.WithLang(*lang), extension.WithHeadings()))
+	}
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	recipes, err := collection.Load(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	for p, err := range recipes.Errors() {
+		fmt.Fprintf(stderr, "%s: skipped: %v\n", p, err)
+	}
+	for _, r := range recipes.Incomplete() {
+		fmt.Fprintf(stderr, "%s: incomplete: %v\n", r.Path, r.Report.Err())
+	}
+	aliases := recipes.Aliases()
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		fmt.Fprintf(stderr, "%s: alias of %s\n", alias, aliases[alias])
+	}
+	if err := site.Build(recipes, *out, opts...); err != nil || !*watch {
+		return err
+	}
+	fmt.Fprintf(stderr, "watching %s for changes\n", dir)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var rebuildErr error
+	err = recipes.Watch(ctx, watchInterval, func(changed []string) {
+		errs, aliases := recipes.Errors(), recipes.Aliases()
+		for _, p := range changed {
+			r, ok := recipes.Get(strings.TrimSuffix(p, path.Ext(p)))
+			switch {
+			case errs[p] != nil:
+				fmt.Fprintf(stderr, "%s: skipped: %v\n", p, errs[p])
+			case aliases[p] != "":
+				fmt.Fprintf(stderr, "%s: alias of %s\n", p, aliases[p])
+			case ok && r.Path == p && !r.Report.Complete():
+				fmt.Fprintf(stderr, "%s: updated, incomplete: %v\n", p, r.Report.Err())
+			case ok && r.Path == p:
+				fmt.Fprintf(stderr, "%s: updated\n", p)
+			default:
+				fmt.Fprintf(stderr, "%s: removed\n", p)
+			}
+		}
+		if rebuildErr = site.Rebuild(recipes, *out, changed, opts...); rebuildErr != nil {
+			cancel()
+		}
+	})
+	if rebuildErr != nil {
+		return rebuildErr
+	}
+	return err
+}
+
+// watchInterval is how often watching commands look for changed files.
+const watchInterval = 500 * time.Millisecond
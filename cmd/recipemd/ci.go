@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/remote"
+)
+
+var ciCommand = &command{
+	name:    "ci",
+	usage:   "[-git ref] [-remote] [-format text|json|sarif|summary] [path ... | -]",
+	summary: "check changed recipes for merge gating",
+	run:     runCI,
+}
+
+// Rules reported by the ci command.
+const (
+	ruleValidate = "validate" // RecipeMD specification and warnings
+	ruleFormat   = "format"   // file is not in canonical format
+	ruleLink     = "link"     // link to a local file that does not exist
+	ruleRemote   = "remote"   // link to a URL without a recipe, with -remote
+)
+
+// finding is a problem the ci command reports for a file.
+type finding struct {
+	File string `json:"file"`
+	Rule string `json:"rule"`
+	recipemd.Diagnostic
+}
+
+func runCI(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	ref := fs.String("git", "", "check the markdown files changed since git `ref`")
+	checkRemote := fs.Bool("remote", false, "also fetch recipes linked by http and https URLs and check that they parse")
+	format := fs.String("format", "text", "output `format`: text, json, sarif or summary (totals as JSON)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" && *format != "sarif" && *format != "summary" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	var files []string
+	var err error
+	switch {
+	case *ref != "" && fs.NArg() > 0:
+		return errors.New("-git and paths are mutually exclusive")
+	case *ref != "":
+		files, err = gitChangedFiles(*ref)
+	case fs.NArg() == 1 && fs.Arg(0) == "-":
+		files, err = readFileList(os.Stdin)
+	default:
+		files, err = recipeFiles(fs.Args())
+	}
+	if err != nil {
+		return err
+	}
+
+	var fetcher *remote.Fetcher
+	if *checkRemote {
+		fetcher = &remote.Fetcher{}
+	}
+	findings := []finding{}
+	failed := false
+	var checked []string
+	for _, f := range files {
+		source, err := os.ReadFile(f)
+		if errors.Is(err, os.ErrNotExist) {
+			// deleted in the change
+			continue
+		} else if err != nil {
+			return err
+		}
+		checked = append(checked, f)
+		ff := checkFile(f, source, fetcher)
+		for _, x := range ff {
+			failed = failed || x.Severity == recipemd.SeverityError
+		}
+		findings = append(findings, ff...)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(findings)
+	case "sarif":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(sarifLog(findings))
+	case "summary":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(summarize(checked, findings))
+	default:
+		for _, x := range findings {
+			fmt.Fprintf(stdout, "%s:%s: %s: %s [%s]\n", x.File, x.Pos, x.Severity, x.Message, x.Rule)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if failed {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// checkFile runs all checks on the recipe in source. Links to URLs are
+// only checked if fetcher is not nil.
+func checkFile(file string, source []byte, fetcher *remote.Fetcher) []finding {
+	var findings []finding
+	add := func(rule string, d recipemd.Diagnostic) {
+		findings = append(findings, finding{File: file, Rule: rule, Diagnostic: d})
+	}
+	diags := recipemd.Validate(source)
+	for _, d := range diags {
+		add(ruleValidate, d)
+	}
+	r, err := recipemd.Parse(source)
+	if err != nil {
+		// the validation errors already cover it
+		return findings
+	}
+	if out, err := recipemd.Format(source); err == nil && !bytes.Equal(out, source) {
+		add(ruleFormat, recipemd.Diagnostic{
+			Pos:      recipemd.PositionAt(source, 0),
+			Severity: recipemd.SeverityError,
+			Message:  "file is not formatted, run recipemd fmt -w",
+		})
+	}
+	for _, in := range r.AllIngredients() {
+		if in.Link == "" || !isLocalLink(in.Link) {
+			continue
+		}
+		u, err := url.Parse(in.Link)
+		if err != nil || u.Path == "" {
+			continue
+		}
+		target := filepath.Join(filepath.Dir(file), filepath.FromSlash(u.Path))
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		if filepath.Ext(target) == "" {
+			// a link without an extension may point to a recipe
+			if _, err := os.Stat(target + ".md"); err == nil {
+				continue
+			}
+		}
+		add(ruleLink, recipemd.Diagnostic{
+			Pos:      recipemd.PositionAt(source, max(0, bytes.Index(source, []byte(in.Link)))),
+			Severity: recipemd.SeverityError,
+			Message:  fmt.Sprintf("ingredient %q links to missing file %s", in.Name, u.Path),
+		})
+	}
+	if fetcher != nil {
+		linked := fetcher.Linked(context.Background(), r)
+		for _, in := range r.AllIngredients() {
+			res, ok := linked[in.Link]
+			if !ok || res.Err == nil {
+				continue
+			}
+			delete(linked, in.Link) // report each link once
+			add(ruleRemote, recipemd.Diagnostic{
+				Pos:      recipemd.PositionAt(source, max(0, bytes.Index(source, []byte(in.Link)))),
+				Severity: recipemd.SeverityError,
+				Message:  fmt.Sprintf("ingredient %q links to %s: %v", in.Name, in.Link, res.Err),
+			})
+		}
+	}
+	return findings
+}
+
+// isLocalLink reports whether link refers to a file relative to the recipe
+// rather than to a URL.
+func isLocalLink(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && u.Scheme == "" && u.Host == "" && !strings.HasPrefix(u.Path, "/")
+}
+
+// gitChangedFiles returns the markdown files added, copied, modified or
+// renamed since ref.
+func gitChangedFiles(ref string) ([]string, error) {
+	out, err := exec.Command("git", "diff", "--name-only", "--diff-filter=ACMR", ref, "--").Output()
+	if err != nil {
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return nil, fmt.Errorf("git diff: %s", bytes.TrimSpace(exit.Stderr))
+		}
+		return nil, err
+	}
+	return readFileList(bytes.NewReader(out))
+}
+
+// readFileList reads file names, one per line, keeping markdown files.
+func readFileList(r io.Reader) ([]string, error) {
+	var files []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		f := strings.TrimSpace(s.Text())
+		if f != "" && strings.EqualFold(filepath.Ext(f), ".md") {
+			files = append(files, f)
+		}
+	}
+	return files, s.Err()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+)
+
+var dedupeCommand = &command{
+	name:    "dedupe",
+	usage:   "[-min score] [-format text|json] [dir]",
+	summary: "list groups of near-duplicate recipes",
+	run:     runDedupe,
+}
+
+// dedupeFile is a file of a group in the JSON output of dedupe.
+type dedupeFile struct {
+	Path  string  `json:"path"`
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+func runDedupe(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	threshold := fs.Float64("min", 0.7, "list recipes at least `score` similar, from 0 to 1, by ingredients and title words")
+	format := fs.String("format", "text", "output `format`: text or json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	recipes, err := collection.Load(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	groups := recipes.NearDuplicates(*threshold)
+
+	if *format == "json" {
+		out := [][]dedupeFile{}
+		for _, g := range groups {
+			var files []dedupeFile
+			for _, m := range g {
+				files = append(files, dedupeFile{Path: m.Path, Title: m.Title, Score: m.Score})
+			}
+			out = append(out, files)
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintf(stdout, "%s (%s)\n", g[0].Path, g[0].Title)
+		for _, m := range g[1:] {
+			fmt.Fprintf(stdout, "  %3.0f%% %s (%s)\n", m.Score*100, m.Path, m.Title)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var diffCommand = &command{
+	name:    "diff",
+	usage:   "old new",
+	summary: "show the semantic differences between two versions of a recipe",
+	run:     runDiff,
+}
+
+func runDiff(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	old, err := parseFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cur, err := parseFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	d := recipemd.Diff(old, cur)
+	if d.Factor != nil {
+		fmt.Fprintf(stdout, "rescaled by %s\n", amount.Format(d.Factor))
+	}
+	for _, ch := range d.Changes {
+		fmt.Fprintln(stdout, ch)
+	}
+	if len(d.Changes) > 0 {
+		return &exitError{code: 1}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var exportCommand = &command{
+	name:    "export",
+	usage:   "[-format jsonl|name] [-o dir] [dir]",
+	summary: "write all recipes of a directory as JSON, one recipe per line, or in another format",
+	run:     runExport,
+}
+
+func runExport(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	format := fs.String("format", "jsonl", "output `format`: jsonl (JSON Lines) or one of "+strings.Join(recipemd.Formats(), ", "))
+	out := fs.String("o", "", "write a file per recipe to `dir` instead of all recipes to standard output; not with jsonl, required by toml and xml")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	var (
+		render recipemd.Renderer
+		ext    string
+	)
+	if *format != "jsonl" {
+		var ok bool
+		if render, ext, ok = recipemd.LookupFormat(*format); !ok {
+			return fmt.Errorf("unknown format %q", *format)
+		}
+		if *out == "" && !recipemd.Streamable(*format) {
+			return fmt.Errorf("-format %s writes a document per recipe and needs -o", *format)
+		}
+	} else if *out != "" {
+		return errors.New("-o needs a -format other than jsonl")
+	}
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	recipes, err := collection.Load(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	errs := recipes.Errors()
+	for _, p := range slices.Sorted(maps.Keys(errs)) {
+		fmt.Fprintf(stderr, "%s: skipped: %v\n", p, errs[p])
+	}
+	for _, r := range recipes.Incomplete() {
+		fmt.Fprintf(stderr, "%s: incomplete: %v\n", r.Path, r.Report.Err())
+	}
+	if render == nil {
+		return recipes.WriteJSONLines(stdout)
+	}
+	for _, r := range recipes.Recipes() {
+		var b bytes.Buffer
+		if err := render.Render(&b, r.Recipe); err != nil {
+			return fmt.Errorf("%s: %w", r.Path, err)
+		}
+		if *out == "" {
+			if _, err := stdout.Write(b.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		path := filepath.Join(*out, filepath.FromSlash(r.Slug)+ext)
+		if same, _ := sameFile(path, filepath.Join(dir, filepath.FromSlash(r.Path))); same {
+			return errors.New(path + " would overwrite the recipe; choose another -o directory")
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
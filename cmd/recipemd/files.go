@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// recipeFiles expands paths into the markdown files they denote.
+// Directories, and paths ending in "/..." as in the go tool, are walked
+// recursively; other paths are used as given.
+func recipeFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	var files []string
+	for _, p := range paths {
+		p = strings.TrimSuffix(p, "/...")
+		if p == "..." {
+			p = "."
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && path != p && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// sameFiles splits off the files that are the same file as one before
+// them, through a symbolic or hard link. It returns the other files and
+// the split off ones mapped to the file they are the same as.
+func sameFiles(files []string) ([]string, map[string]string, error) {
+	var unique []string
+	var infos []os.FileInfo
+	same := make(map[string]string)
+outer:
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, u := range infos {
+			if os.SameFile(info, u) {
+				same[f] = unique[i]
+				continue outer
+			}
+		}
+		unique = append(unique, f)
+		infos = append(infos, info)
+	}
+	return unique, same, nil
+}
+
+// parseFile reads and parses the recipe at path. Files with the extension
+// .json are decoded from the recipe JSON format instead, ignoring opts.
+func parseFile(path string, opts ...recipemd.ParseOption) (*recipemd.Recipe, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var r recipemd.Recipe
+		if err := json.Unmarshal(source, &r); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return &r, nil
+	}
+	return recipemd.Parse(source, opts...)
+}
+
+// fileResolver resolves links between recipe files: a relative link to a
+// markdown or JSON file in the recipe at base is the recipe parsed from
+// that file with opts. Other links, such as URLs, are not recipes.
+func fileResolver(opts ...recipemd.ParseOption) recipemd.Resolver {
+	return func(base, link string) (*recipemd.Recipe, string, error) {
+		u, err := url.Parse(link)
+		if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || path.IsAbs(u.Path) {
+			return nil, "", nil
+		}
+		if ext := strings.ToLower(path.Ext(u.Path)); ext != ".md" && ext != ".json" {
+			return nil, "", nil
+		}
+		file := filepath.Join(filepath.Dir(base), filepath.FromSlash(u.Path))
+		r, err := parseFile(file, opts...)
+		if err != nil {
+			return nil, "", err
+		}
+		return r, file, nil
+	}
+}
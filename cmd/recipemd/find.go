@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/filter"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var findCommand = &command{
+	name:    "find",
+	usage:   "[-without-allergen name] <expression> [path ...]",
+	summary: "list recipes matching a filter expression",
+	run:     runFind,
+}
+
+func runFind(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	var without stringsFlag
+	fs.Var(&without, "without-allergen", "only list recipes without the allergen `name`, e.g. peanut or \"tree nut\" (repeatable)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	expr, err := filter.Parse(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, name := range without {
+		if !slices.ContainsFunc(recipemd.DefaultAllergens, func(a recipemd.Allergen) bool {
+			return strings.EqualFold(a.Name, name)
+		}) {
+			return fmt.Errorf("unknown allergen %q", name)
+		}
+		expr = filter.And{Left: expr, Right: filter.Not{Expr: filter.Term{Field: filter.FieldAllergen, Value: name}}}
+	}
+	query := collection.NewQuery(expr)
+	files, err := recipeFiles(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, f := range files {
+		r, err := parseFile(f)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", f, err)
+			failed++
+			continue
+		}
+		if query.Match(r) {
+			fmt.Fprintln(stdout, f)
+		}
+	}
+	if failed > 0 {
+		return errors.New(fmt.Sprint(failed, " file(s) could not be parsed"))
+	}
+	return nil
+}
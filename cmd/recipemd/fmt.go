@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var fmtCommand = &command{
+	name:    "fmt",
+	usage:   "[-l | -n] [-w] [-title-from-filename] [path ...]",
+	summary: "rewrite recipes in canonical format",
+	run:     runFmt,
+}
+
+func runFmt(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	list := fs.Bool("l", false, "list files whose formatting differs and exit with status 1 if there are any")
+	write := fs.Bool("w", false, "write the result to the source file instead of standard output")
+	dryRun := fs.Bool("n", false, "write nothing; report for every file whether formatting changes it, having checked that the recipe stays the same")
+	inferTitle := fs.Bool("title-from-filename", false, "give files without a title one made from their file name, e.g. \"Chocolate chip cookies\" for chocolate-chip-cookies.md")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		if *inferTitle {
+			return errors.New("-title-from-filename needs file arguments")
+		}
+		source, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		out, err := recipemd.Format(source)
+		if err != nil {
+			return fmt.Errorf("<stdin>: %w", err)
+		}
+		_, err = stdout.Write(out)
+		return err
+	}
+	files, err := recipeFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	status := 0
+	for _, f := range files {
+		source, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		input, title := source, ""
+		if *inferTitle {
+			var ok bool
+			if input, ok = recipemd.InsertTitle(source, recipemd.TitleFromFilename(f)); ok {
+				title = recipemd.TitleFromFilename(f)
+			}
+		}
+		out, err := recipemd.Format(input)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", f, err)
+			status = 2
+			continue
+		}
+		changed := !bytes.Equal(source, out)
+		if *dryRun {
+			// Format has already checked the recipe stays the same
+			if title != "" {
+				fmt.Fprintf(stdout, "%s: would insert title %q from the file name\n", f, title)
+				status = max(status, 1)
+			} else if changed {
+				fmt.Fprintf(stdout, "%s: would reformat, recipe unchanged\n", f)
+				status = max(status, 1)
+			} else {
+				fmt.Fprintf(stdout, "%s: already formatted\n", f)
+			}
+			continue
+		}
+		if title != "" {
+			fmt.Fprintf(stderr, "%s: inserted title %q from the file name\n", f, title)
+		}
+		if *list && changed {
+			fmt.Fprintln(stdout, f)
+			status = max(status, 1)
+		}
+		if *write {
+			if changed {
+				if err := os.WriteFile(f, out, 0o644); err != nil {
+					return err
+				}
+			}
+
+		} else if !*list {
+			if _, err := stdout.Write(out); err != nil {
+				return err
+			}
+		}
+	}
+	if status != 0 {
+		return &exitError{code: status}
+	}
+	return nil
+}
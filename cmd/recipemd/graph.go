@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+)
+
+var graphCommand = &command{
+	name:    "graph",
+	usage:   "[-format dot|text] [-all] [dir]",
+	summary: "show how recipes use each other through ingredient links",
+	run:     runGraph,
+}
+
+func runGraph(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	format := fs.String("format", "dot", "output `format`: dot (Graphviz) or text, the recipes in an order that puts sub-recipes first")
+	all := fs.Bool("all", false, "include recipes without links in the dot output")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "dot" && *format != "text" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	recipes, err := collection.Load(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	g := recipes.Graph()
+
+	if *format == "dot" {
+		if err := g.WriteDOT(stdout, *all); err != nil {
+			return err
+		}
+	} else if order, err := g.Topological(); err == nil {
+		for _, r := range order {
+			fmt.Fprintf(stdout, "%s (%s)", r.Path, r.Title)
+			if uses := g.Uses(r); len(uses) > 0 {
+				paths := make([]string, len(uses))
+				for i, u := range uses {
+					paths[i] = u.Path
+				}
+				fmt.Fprintf(stdout, " uses %s", strings.Join(paths, ", "))
+			}
+			fmt.Fprintln(stdout)
+		}
+	}
+	cycles := g.Cycles()
+	for _, cycle := range cycles {
+		paths := make([]string, len(cycle))
+		for i, r := range cycle {
+			paths[i] = r.Path
+		}
+		fmt.Fprintf(stderr, "cycle: %s\n", strings.Join(paths, ", "))
+	}
+	if len(cycles) > 0 {
+		return &exitError{code: 1}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Command recipemd works with RecipeMD files.
+//
+// Usage:
+//
+//	recipemd <command> [arguments]
+//
+// Run "recipemd help" for the list of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// command is a recipemd subcommand.
+type command struct {
+	name    string
+	usage   string // arguments after the command name
+	summary string
+	run     func(c *command, args []string, stdout, stderr io.Writer) error
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		amountsCommand,
+		buildCommand,
+		ciCommand,
+		dedupeCommand,
+		diffCommand,
+		exportCommand,
+		findCommand,
+		fmtCommand,
+		graphCommand,
+		nutritionCommand,
+		planCommand,
+		qrCommand,
+		renderCommand,
+		serveCommand,
+		schemaCommand,
+		shareCommand,
+		shoppingCommand,
+		showCommand,
+		splitCommand,
+		translateCommand,
+		validateCommand,
+	}
+}
+
+// exitError makes recipemd exit with code without printing a message.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(c, args[1:], stdout, stderr)
+		var exit *exitError
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.As(err, &exit):
+			return exit.code
+		}
+		fmt.Fprintf(stderr, "recipemd %s: %v\n", c.name, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "recipemd: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: recipemd <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+}
+
+// newFlagSet returns a flag set for c that writes its usage to stderr.
+func newFlagSet(c *command, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: recipemd %s %s\n", c.name, c.usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args with fs. Flags may follow positional arguments,
+// as in "recipemd build ./recipes -o ./public", up to a "--". The flag
+// package already reports parse errors, so they are turned into a plain
+// exit status.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(interspersed(fs, args))
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return &exitError{code: 2}
+	}
+	return err
+}
+
+// interspersed reorders args so the flags come before the positional
+// arguments, which are separated from them by "--".
+func interspersed(fs *flag.FlagSet, args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(a) < 2 || a[0] != '-' {
+			positional = append(positional, a)
+			continue
+		}
+		flags = append(flags, a)
+		name := strings.TrimLeft(a, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			// reported by fs.Parse
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		if i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	return append(append(flags, "--"), positional...)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	formatted   = "# Tea\n\n*hot*\n\n---\n\n- *1* tea bag\n\n---\n\nSteep.\n"
+	unformatted = "Tea\n===\n\n*hot*\n\n***\n\n* *1* tea bag\n"
+	invalid     = "# Tea\n\n---\n\n- *1 cup*\n"
+	misordered  = "# Tea\n\n**1 cup**\n\n*hot*\n"
+)
+
+func TestRunExitCode(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"tea.md":     formatted,
+		"messy.md":   unformatted,
+		"invalid.md": invalid,
+		"yields.md":  misordered,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := func(name string) string { return filepath.Join(dir, name) }
+	recipes := filepath.Join(dir, "recipes")
+	if err := os.Mkdir(recipes, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(recipes, "tea.md"), []byte(formatted), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args   []string
+		code   int
+		stdout string // contained in the output
+		stderr string
+	}{
+		{nil, 2, "", "usage: recipemd"},
+		{[]string{"help"}, 0, "", "commands:"},
+		{[]string{"frobnicate"}, 2, "", `unknown command "frobnicate"`},
+		{[]string{"find", "-nope"}, 2, "", "flag provided but not defined"},
+		{[]string{"find", "-h"}, 0, "", "usage: recipemd find"},
+		{[]string{"find"}, 2, "", "usage: recipemd find"},
+		{[]string{"find", "tag:", file("tea.md")}, 1, "", "missing value for field tag"},
+		{[]string{"find", "hot", file("tea.md")}, 0, file("tea.md"), ""},
+		{[]string{"find", "hot", file("tea.md"), file("invalid.md")}, 1, file("tea.md"), "1 file(s) could not be parsed"},
+		{[]string{"find", "hot", file("missing.md")}, 1, "", "no such file"},
+		{[]string{"validate", file("tea.md")}, 0, "", ""},
+		{[]string{"validate", file("invalid.md")}, 1, "error", ""},
+		{[]string{"validate", "-format", "xml", file("tea.md")}, 1, "", `unknown format "xml"`},
+		{[]string{"fmt", "-l", file("tea.md")}, 0, "", ""},
+		{[]string{"fmt", "-l", file("tea.md"), file("messy.md")}, 1, file("messy.md"), ""},
+		{[]string{"fmt", file("invalid.md")}, 2, "", file("invalid.md")},
+		{[]string{"fmt", file("yields.md")}, 0, "*hot*\n\n**1 cup**", ""},
+		{[]string{"show", file("tea.md")}, 0, "# Tea", ""},
+		{[]string{"export", "-format", "json", recipes}, 0, `"title": "Tea"`, ""},
+		{[]string{"export", "-format", "toml", recipes}, 1, "", "-format toml writes a document per recipe and needs -o"},
+		{[]string{"export", "-format", "xml", recipes}, 1, "", "needs -o"},
+		{[]string{"export", "-format", "xml", "-o", file("out"), recipes}, 0, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code %d, want %d\nstdout: %s\nstderr: %s", code, tt.code, &stdout, &stderr)
+			}
+			if !strings.Contains(stdout.String(), tt.stdout) {
+				t.Errorf("stdout %q does not contain %q", &stdout, tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr %q does not contain %q", &stderr, tt.stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/nutrition"
+)
+
+var nutritionCommand = &command{
+	name:    "nutrition",
+	usage:   "[-foods file | -key key] [-format text|json|html] file",
+	summary: "estimate the calories and nutrients of a recipe",
+	run:     runNutrition,
+}
+
+func runNutrition(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	foods := fs.String("foods", "", "JSON `file` of foods by name to use instead of FoodData Central")
+	key := fs.String("key", os.Getenv("FDC_API_KEY"), "FoodData Central API `key`, by default $FDC_API_KEY")
+	format := fs.String("format", "text", "output `format`: text, json or html (schema.org microdata)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	if *format != "text" && *format != "json" && *format != "html" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	var db nutrition.Database
+	switch {
+	case *foods != "":
+		data, err := os.ReadFile(*foods)
+		if err != nil {
+			return err
+		}
+		var t nutrition.Table
+		if err := json.Unmarshal(data, &t); err != nil {
+			return fmt.Errorf("%s: %w", *foods, err)
+		}
+		db = t
+	case *key != "":
+		db = &nutrition.FoodDataCentral{APIKey: *key}
+	default:
+		return errors.New("no food database: use -foods or a FoodData Central API key")
+	}
+	r, err := parseFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	rep, err := nutrition.Estimate(context.Background(), db, r)
+	if err != nil {
+		return err
+	}
+	for _, name := range rep.Missing {
+		fmt.Fprintf(stderr, "%s: not counted\n", name)
+	}
+	switch *format {
+	case "text":
+		writeNutrients(stdout, "recipe", rep.Total)
+		for _, y := range rep.PerYield {
+			writeNutrients(stdout, y.Unit, y.Nutrients)
+		}
+		return nil
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	default:
+		_, err := io.WriteString(stdout, rep.HTML())
+		return err
+	}
+}
+
+// writeNutrients writes a line of the nutrients per unit.
+func writeNutrients(w io.Writer, unit string, n nutrition.Nutrients) {
+	parts := []string{
+		fmt.Sprintf("%.0f kcal", n.Calories),
+		fmt.Sprintf("fat %.1f g", n.Fat),
+		fmt.Sprintf("carbohydrates %.1f g", n.Carbohydrates),
+		fmt.Sprintf("protein %.1f g", n.Protein),
+		fmt.Sprintf("fiber %.1f g", n.Fiber),
+		fmt.Sprintf("sodium %.0f mg", n.Sodium),
+	}
+	fmt.Fprintf(w, "per %s: %s\n", unit, strings.Join(parts, ", "))
+}
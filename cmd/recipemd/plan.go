@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xcapaldi/recipemd-go/pkg/plan"
+)
+
+var planCommand = &command{
+	name:    "plan",
+	usage:   "[-from date] [-days n] [-shopping] [-format text|json|ics] [-site url] [-tz zone] [-lang tag] file",
+	summary: "list the meals of a meal plan or print their combined shopping list",
+	run:     runPlan,
+}
+
+func runPlan(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	from := fs.String("from", "", "first `date` of the meals, as YYYY-MM-DD; by default the first of the plan")
+	days := fs.Int("days", 0, "number of `days` from -from to include; 0 for all")
+	shopping := fs.Bool("shopping", false, "print the combined shopping list of the recipes of the meals, scaled to their amounts, instead of the meals")
+	format := fs.String("format", "text", "output `format`: text (markdown for -shopping), json or ics, an iCalendar file with an event per meal")
+	site := fs.String("site", "", "link the events of -format ics to the recipe pages of the site built from the plan's directory at `url`")
+	tz := fs.String("tz", "", "place the events of -format ics on the days of the plan in the time `zone`, e.g. Europe/Berlin")
+	lang := fs.String("lang", "", "label the calendar of -format ics in the language `tag`, e.g. de")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	if *format != "text" && *format != "json" && *format != "ics" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if *format == "ics" && *shopping {
+		return errors.New("-format ics is for meals, not -shopping")
+	}
+	var icsOpts []plan.ICSOption
+	if *site != "" {
+		u, err := url.Parse(strings.TrimSuffix(*site, "/") + "/")
+		if err != nil || !u.IsAbs() {
+			return fmt.Errorf("invalid -site %q, want an absolute URL", *site)
+		}
+		icsOpts = append(icsOpts, plan.WithSite(u))
+	}
+	if *tz != "" {
+		loc, err := time.LoadLocation(*tz)
+		if err != nil {
+			return fmt.Errorf("invalid -tz %q: %w", *tz, err)
+		}
+		icsOpts = append(icsOpts, plan.WithLocation(loc))
+	}
+	if *lang != "" {
+		icsOpts = append(icsOpts, plan.WithLocale(*lang))
+	}
+	if *days < 0 {
+		return fmt.Errorf("invalid -days %d", *days)
+	}
+	file := fs.Arg(0)
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	p, err := plan.Parse(source)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	var start, end time.Time
+	if *from != "" {
+		if start, err = time.Parse(time.DateOnly, *from); err != nil {
+			return fmt.Errorf("invalid -from %q, want YYYY-MM-DD", *from)
+		}
+	} else if len(p.Meals) > 0 {
+		start = p.Meals[0].Date
+	}
+	if *days > 0 {
+		end = start.AddDate(0, 0, *days)
+	}
+	meals := p.Between(start, end)
+
+	if *shopping {
+		list, err := plan.ShoppingList(meals, filepath.Clean(file), fileResolver())
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if *format == "json" {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(list)
+		}
+		return list.WriteMarkdown(stdout)
+	}
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan.Plan{Title: p.Title, Meals: meals})
+	case "ics":
+		return p.WriteICS(stdout, meals, icsOpts...)
+	}
+	for _, m := range meals {
+		fmt.Fprint(stdout, m.Date.Format(time.DateOnly))
+		if m.Name != "" {
+			fmt.Fprintf(stdout, " %s:", m.Name)
+		}
+		if m.Amount != nil {
+			fmt.Fprintf(stdout, " %s", m.Amount)
+		}
+		fmt.Fprintf(stdout, " %s", m.Title)
+		if m.Link != "" {
+			fmt.Fprintf(stdout, " (%s)", m.Link)
+		}
+		fmt.Fprintln(stdout)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/qr"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var qrCommand = &command{
+	name:    "qr",
+	usage:   "[-base url] [-level L|M|Q|H] [-format png|svg] [-scale n] [-o file] file | url | -",
+	summary: "print a QR code of a recipe link, a URL or text",
+	run:     runQR,
+}
+
+func runQR(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	base := fs.String("base", "", "encode a link to the /decode page of the server at `url` that carries the recipe")
+	level := fs.String("level", "M", "error correction `level`: L, M, Q or H")
+	format := fs.String("format", "png", "image `format`: png or svg")
+	scale := fs.Int("scale", 8, "`pixels` per module of PNG images")
+	out := fs.String("o", "", "write the image to `file` instead of standard output")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	l := strings.Index("LMQH", strings.ToUpper(*level))
+	if len(*level) != 1 || l < 0 {
+		return fmt.Errorf("invalid level %q", *level)
+	}
+	if *format != "png" && *format != "svg" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	var data []byte
+	switch arg := fs.Arg(0); {
+	case arg == "-":
+		// text such as a shopping list, encoded as it is
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		data = b
+	case strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://"):
+		data = []byte(arg)
+	default:
+		if *base == "" {
+			return errors.New("a recipe needs -base, the URL of the server that decodes its link")
+		}
+		r, err := parseFile(arg)
+		if err != nil {
+			return err
+		}
+		frag, err := recipemd.EncodeFragment(r)
+		if err != nil {
+			return err
+		}
+		data = []byte(strings.TrimSuffix(*base, "/") + "/decode#" + frag)
+	}
+	code, err := qr.Encode(data, qr.Level(l))
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if *format == "svg" {
+		err = code.WriteSVG(&b)
+	} else {
+		err = code.WritePNG(&b, *scale)
+	}
+	if err != nil {
+		return err
+	}
+	if *out != "" {
+		return os.WriteFile(*out, b.Bytes(), 0o644)
+	}
+	_, err = stdout.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gext "github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var renderCommand = &command{
+	name:    "render",
+	usage:   "[-format html|json|markdown|text|jsonld|toml|xml | -all-formats] [-lang tag] [-url url] [-o path] file",
+	summary: "render a recipe as HTML, JSON, markdown, plain text, JSON-LD, TOML or XML",
+	run:     runRender,
+}
+
+// A renderFormat is an output format of render by name, with the
+// extension of the file -all-formats writes it to.
+type renderFormat struct {
+	name, ext string
+}
+
+// renderFormats are the output formats render writes itself.
+var renderFormats = []renderFormat{
+	{"html", ".html"},
+	{"json", ".json"},
+	{"markdown", ".md"},
+	{"text", ".txt"},
+	{"jsonld", ".jsonld"},
+}
+
+// allRenderFormats returns renderFormats followed by the other formats
+// registered with recipemd.RegisterFormat.
+func allRenderFormats() []renderFormat {
+	formats := slices.Clone(renderFormats)
+	for _, name := range recipemd.Formats() {
+		if slices.ContainsFunc(renderFormats, func(f renderFormat) bool { return f.name == name }) {
+			continue
+		}
+		_, ext, _ := recipemd.LookupFormat(name)
+		formats = append(formats, renderFormat{name, ext})
+	}
+	return formats
+}
+
+// renderPage is a standalone HTML page around a rendered recipe.
+var renderPage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html{{with .Lang}} lang="{{.}}"{{end}}>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+{{.Meta}}</head>
+<body>
+{{.HTML}}</body>
+</html>
+`))
+
+func runRender(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	format := fs.String("format", "html", "output `format`: "+strings.Join(recipemd.Formats(), ", "))
+	all := fs.Bool("all-formats", false, "write every format to a file named after the recipe in the -o directory")
+	out := fs.String("o", "", "write to `path` instead of standard output; with -all-formats the directory, by default the current one")
+	lang := fs.String("lang", "", "head the sections of html and text in the language `tag`, e.g. de")
+	pageURL := fs.String("url", "", "the page is published at `url`, for the og:url and absolute og:image of html")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	if *all && flagSet(fs, "format") {
+		return errors.New("-format and -all-formats are mutually exclusive")
+	}
+	file := fs.Arg(0)
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	// All formats share one parse: the recipe is extracted from the
+	// document the HTML is rendered from.
+	var recipeMD goldmark.Extender = extension.RecipeMD
+	if *lang != "" {
+		recipeMD = extension.New(extension.WithHTMLOptions(extension.WithLang(*lang), extension.WithHeadings()))
+	}
+	md := goldmark.New(goldmark.WithExtensions(gext.Table, gext.Footnote, recipeMD))
+	doc := md.Parser().Parse(text.NewReader(source))
+	r, err := recipemd.ExtractRecipe(doc, source)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	render := func(w io.Writer, format string) error {
+		switch format {
+		case "html":
+			var b, meta bytes.Buffer
+			if err := md.Renderer().Render(&b, source, doc); err != nil {
+				return err
+			}
+			if err := recipemd.WriteSocialMeta(&meta, r, *pageURL); err != nil {
+				return err
+			}
+			return renderPage.Execute(w, struct {
+				Title string
+				Lang  string
+				Meta  template.HTML
+				HTML  template.HTML
+			}{r.Title, *lang, template.HTML(meta.String()), template.HTML(b.String())})
+		case "json":
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(r)
+		case "markdown":
+			return recipemd.WriteMarkdown(w, r)
+		case "text":
+			return recipemd.WritePlainText(w, r, recipemd.WithLang(*lang))
+		case "jsonld":
+			return recipemd.WriteJSONLD(w, r)
+		}
+		if rr, _, ok := recipemd.LookupFormat(format); ok {
+			return rr.Render(w, r)
+		}
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	if !*all {
+		var b bytes.Buffer
+		if err := render(&b, *format); err != nil {
+			return err
+		}
+		if *out != "" {
+			return os.WriteFile(*out, b.Bytes(), 0o644)
+		}
+		_, err := stdout.Write(b.Bytes())
+		return err
+	}
+	dir := *out
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	formats := allRenderFormats()
+	for _, f := range formats {
+		path := filepath.Join(dir, base+f.ext)
+		if same, _ := sameFile(path, file); same {
+			return errors.New(path + " would overwrite the recipe; choose another -o directory")
+		}
+	}
+	for _, f := range formats {
+		path := filepath.Join(dir, base+f.ext)
+		var b bytes.Buffer
+		if err := render(&b, f.name); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flagSet reports whether the flag name was given on the command line.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
+// sameFile reports whether a and b are the same existing file.
+func sameFile(a, b string) (bool, error) {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(ia, ib), nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import "path/filepath"
+
+// sarifLog converts findings to a SARIF 2.1.0 log as understood by code
+// scanning services, which show each finding on the line of the markdown
+// file it was found on. Paths are relative to the source root.
+func sarifLog(findings []finding) any {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+	}
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI       string `json:"uri"`
+				URIBaseID string `json:"uriBaseId"`
+			} `json:"artifactLocation"`
+			Region region `json:"region"`
+		} `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+	results := []result{}
+	for _, x := range findings {
+		var loc location
+		loc.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(filepath.Clean(x.File))
+		loc.PhysicalLocation.ArtifactLocation.URIBaseID = "%SRCROOT%"
+		loc.PhysicalLocation.Region = region{x.Pos.Line, x.Pos.Column}
+		results = append(results, result{
+			RuleID:    x.Rule,
+			Level:     x.Severity.String(),
+			Message:   message{x.Message},
+			Locations: []location{loc},
+		})
+	}
+	type driver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri"`
+		Rules          []rule `json:"rules"`
+	}
+	type run struct {
+		Tool struct {
+			Driver driver `json:"driver"`
+		} `json:"tool"`
+		Results    []result `json:"results"`
+		ColumnKind string   `json:"columnKind"`
+	}
+	var rn run
+	rn.Tool.Driver = driver{
+		Name:           "recipemd",
+		InformationURI: "https://github.com/xcapaldi/recipemd-go",
+		Rules: []rule{
+			{ruleValidate, message{"Recipe does not follow the RecipeMD specification"}},
+			{ruleFormat, message{"Recipe is not in canonical format"}},
+			{ruleLink, message{"Ingredient links to a missing recipe"}},
+			{ruleRemote, message{"Ingredient links to a remote recipe that cannot be fetched"}},
+		},
+	}
+	rn.Results = results
+	// Diagnostic columns count characters, not UTF-16 code units.
+	rn.ColumnKind = "unicodeCodePoints"
+	return struct {
+		Version string `json:"version"`
+		Schema  string `json:"$schema"`
+		Runs    []run  `json:"runs"`
+	}{"2.1.0", "https://json.schemastore.org/sarif-2.1.0.json", []run{rn}}
+}
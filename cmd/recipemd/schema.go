@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var schemaCommand = &command{
+	name:    "schema",
+	usage:   "[-validate file ...]",
+	summary: "print the JSON Schema of recipes, or validate JSON files against it",
+	run:     runSchema,
+}
+
+func runSchema(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	validate := fs.Bool("validate", false, "validate the JSON files given as arguments instead of printing the schema")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if !*validate {
+		if fs.NArg() != 0 {
+			fs.Usage()
+			return &exitError{code: 2}
+		}
+		_, err := stdout.Write(recipemd.Schema())
+		return err
+	}
+	status := 0
+	for _, f := range fs.Args() {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if err := recipemd.ValidateJSON(data); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", f, err)
+			status = 1
+		}
+	}
+	if status != 0 {
+		return &exitError{code: status}
+	}
+	return nil
+}
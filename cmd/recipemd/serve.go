@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/server"
+)
+
+var serveCommand = &command{
+	name:    "serve",
+	usage:   "[-addr address] [dir]",
+	summary: "serve a directory of recipes as a website",
+	run:     runServe,
+}
+
+func runServe(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	addr := fs.String("addr", "localhost:8080", "listen on `address`")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	recipes, err := collection.Load(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchCollection(ctx, recipes, stderr)
+	fmt.Fprintf(stderr, "serving %s on http://%s/\n", dir, *addr)
+	opts := []server.Option{server.Watched()}
+	if inGitRepository(dir) {
+		opts = append(opts, server.WithRevisions(gitRevisions(dir)))
+	}
+	return http.ListenAndServe(*addr, server.NewHandler(recipes, opts...))
+}
+
+// inGitRepository reports whether dir is in the work tree of a git
+// repository.
+func inGitRepository(dir string) bool {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// gitRevisions returns server.Revisions reading files of dir from the git
+// repository it is in.
+func gitRevisions(dir string) server.Revisions {
+	return func(rev, p string) ([]byte, error) {
+		if strings.HasPrefix(rev, "-") || strings.ContainsAny(rev, ": \t\n") {
+			return nil, fmt.Errorf("invalid revision %q", rev)
+		}
+		out, err := exec.Command("git", "-C", dir, "show", rev+":./"+p).Output()
+		if err != nil {
+			var exit *exec.ExitError
+			if errors.As(err, &exit) {
+				return nil, fmt.Errorf("git show: %s", bytes.TrimSpace(exit.Stderr))
+			}
+			return nil, err
+		}
+		return out, nil
+	}
+}
+
+// watchCollection keeps c up to date until ctx is done. Errors are
+// reported and watching resumes after the next interval, while the server
+// goes on serving the last good snapshot.
+func watchCollection(ctx context.Context, c *collection.Collection, stderr io.Writer) {
+	for {
+		err := c.Watch(ctx, watchInterval, func(changed []string) {
+			fmt.Fprintf(stderr, "reloaded %d changed file(s), generation %d\n", len(changed), c.Generation())
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		fmt.Fprintf(stderr, "watching: %v\n", err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var shareCommand = &command{
+	name:    "share",
+	usage:   "[-base url | -data-uri] file",
+	summary: "print a link that carries a whole recipe",
+	run:     runShare,
+}
+
+func runShare(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	base := fs.String("base", "", "print a link to the /decode page of the server at `url` instead of the bare fragment")
+	dataURI := fs.Bool("data-uri", false, "print the recipe as a data: URI of its markdown")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	if *base != "" && *dataURI {
+		return errors.New("-base and -data-uri are mutually exclusive")
+	}
+	r, err := parseFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *dataURI {
+		uri, err := recipemd.DataURI(r)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, uri)
+		return nil
+	}
+	frag, err := recipemd.EncodeFragment(r)
+	if err != nil {
+		return err
+	}
+	if *base != "" {
+		frag = strings.TrimSuffix(*base, "/") + "/decode#" + frag
+	}
+	fmt.Fprintln(stdout, frag)
+	return nil
+}
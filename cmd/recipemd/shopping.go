@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var shoppingCommand = &command{
+	name:    "shopping",
+	usage:   "[-scale file=factor] [-linked] [-case preserve|lower|sentence] [-sort appearance|name|category] [-layout categories] [-format markdown|json] path ...",
+	summary: "print the merged ingredients of recipes as a shopping list",
+	run:     runShopping,
+}
+
+func runShopping(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	var scales stringsFlag
+	fs.Var(&scales, "scale", "multiply the amounts of `file=factor`, e.g. dinner.md=2 (repeatable)")
+	linked := fs.Bool("linked", false, "buy the ingredients of the recipes that ingredients link to, scaled to the amounts needed, instead of the linked ingredients")
+	nameCase := fs.String("case", "preserve", "`casing` of ingredient names: preserve, lower or sentence")
+	sortBy := fs.String("sort", "appearance", "`order` of the items: appearance, name or category")
+	layout := fs.String("layout", "", "sort by category with the `categories` in store order, comma separated, e.g. \"produce,bakery,dairy and eggs\"; the others follow")
+	format := fs.String("format", "markdown", "output `format`: markdown or json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	cases := map[string]recipemd.NameCase{
+		"preserve": recipemd.PreserveCase,
+		"lower":    recipemd.LowerCase,
+		"sentence": recipemd.SentenceCase,
+	}
+	casing, ok := cases[*nameCase]
+	if !ok {
+		return fmt.Errorf("unknown casing %q", *nameCase)
+	}
+	categories := recipemd.DefaultCategories
+	if *layout != "" {
+		if *sortBy == "name" {
+			return errors.New("-layout sorts by category, not by name")
+		}
+		*sortBy = "category"
+		var err error
+		if categories, err = recipemd.StoreLayout(categories, strings.Split(*layout, ",")...); err != nil {
+			return err
+		}
+	}
+	sorts := map[string]func(*recipemd.ShoppingList){
+		"appearance": (*recipemd.ShoppingList).SortByAppearance,
+		"name":       (*recipemd.ShoppingList).SortByName,
+		"category": func(l *recipemd.ShoppingList) {
+			l.SortByCategory(categories)
+		},
+	}
+	sortList, ok := sorts[*sortBy]
+	if !ok {
+		return fmt.Errorf("unknown order %q", *sortBy)
+	}
+	factors := make(map[string]*big.Rat)
+	for _, s := range scales {
+		file, f, ok := strings.Cut(s, "=")
+		a := amount.Parse(f)
+		if !ok || a.Factor == nil || a.Unit != "" {
+			return fmt.Errorf("invalid -scale %q, want file=factor", s)
+		}
+		factors[filepath.Clean(file)] = a.Factor
+	}
+	files, err := recipeFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	files, same, err := sameFiles(files)
+	if err != nil {
+		return err
+	}
+	for _, f := range slices.Sorted(maps.Keys(same)) {
+		first := same[f]
+		fmt.Fprintf(stderr, "%s: same file as %s, counted once\n", f, first)
+		if factor, ok := factors[filepath.Clean(f)]; ok {
+			if _, ok := factors[filepath.Clean(first)]; !ok {
+				factors[filepath.Clean(first)] = factor
+			}
+			delete(factors, filepath.Clean(f))
+		}
+	}
+
+	var list recipemd.ShoppingList
+	for _, f := range files {
+		r, err := parseFile(f, recipemd.WithNameCase(casing))
+		if err != nil {
+			return fmt.Errorf("%s: %w", f, err)
+		}
+		if factor, ok := factors[filepath.Clean(f)]; ok {
+			r = r.Scale(factor)
+			delete(factors, filepath.Clean(f))
+		}
+		if !*linked {
+			list.Add(r)
+			continue
+		}
+		subs, err := r.SubRecipes(filepath.Clean(f), fileResolver(recipemd.WithNameCase(casing)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", f, err)
+		}
+		for _, in := range r.FullIngredients(subs) {
+			list.AddIngredient(in)
+		}
+	}
+	for file := range factors {
+		return fmt.Errorf("-scale %s: not one of the recipes", file)
+	}
+	sortList(&list)
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	return list.WriteMarkdown(stdout)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+var showCommand = &command{
+	name:    "show",
+	usage:   "[-m factor | -y yield] [-pin name] [-rules] [-instructions] [-annotate classes] [-unicode] [-appendices] [-steps] [-linked] [-format markdown|json|env] file",
+	summary: "print a recipe, optionally scaled",
+	run:     runShow,
+}
+
+// stringsFlag collects the values of a repeatable flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+func runShow(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	multiply := fs.String("m", "", "multiply all amounts by `factor`, e.g. 2 or 1/2")
+	yield := fs.String("y", "", "scale the recipe to `yield`, e.g. \"4 servings\"")
+	var pinned stringsFlag
+	fs.Var(&pinned, "pin", "keep the amount of ingredient `name` when scaling (repeatable)")
+	rules := fs.Bool("rules", false, "scale spices, leavening and salt less than other ingredients")
+	instructions := fs.Bool("instructions", false, "also scale quantities of ingredients mentioned in the instructions, e.g. \"add 100 g of the flour\"")
+	annotate := fs.String("annotate", "", "follow amounts of the unit `classes` (volume, mass or all) with their conversion, comma separated")
+	unicode := fs.Bool("unicode", false, "write fractions such as 1/2 as unicode characters like ½")
+	appendices := fs.Bool("appendices", false, "parse first-level headings after the instructions, like \"# Notes\", as appendices")
+	linked := fs.Bool("linked", false, "also print the recipes that ingredients link to, scaled to the amounts they ask for, and report the factors on standard error")
+	steps := fs.Bool("steps", false, "add the steps of the instructions, with their ingredients and timers, to the json output")
+	format := fs.String("format", "markdown", "output `format`: markdown, json or env (shell variable assignments)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	classes, err := unitClasses(*annotate)
+	if err != nil {
+		return err
+	}
+	var opts []recipemd.ParseOption
+	if *appendices {
+		opts = append(opts, recipemd.WithAppendices())
+	}
+	if *steps {
+		opts = append(opts, recipemd.WithInstructionSteps())
+	}
+	r, err := parseFile(fs.Arg(0), opts...)
+	if err != nil {
+		return err
+	}
+
+	var factor *big.Rat
+	switch {
+	case *multiply != "" && *yield != "":
+		return errors.New("-m and -y are mutually exclusive")
+	case *multiply != "":
+		a := amount.Parse(*multiply)
+		if a.Factor == nil || a.Unit != "" {
+			return fmt.Errorf("invalid factor %q", *multiply)
+		}
+		factor = a.Factor
+	case *yield != "":
+		if factor, err = r.YieldFactor(amount.Parse(*yield)); err != nil {
+			return err
+		}
+	}
+	if factor != nil || len(pinned) > 0 {
+		if factor == nil {
+			factor = big.NewRat(1, 1)
+		}
+		opts := []recipemd.ScaleOption{recipemd.Pin(pinned...)}
+		if *rules {
+			opts = append(opts, recipemd.WithRules(recipemd.DefaultScalingRules...))
+		}
+		if *instructions {
+			opts = append(opts, recipemd.ScaleInstructions())
+		}
+		r = r.Scale(factor, opts...)
+	}
+	if *annotate != "" {
+		r = r.Annotate(classes...)
+	}
+	var subs []recipemd.SubRecipe
+	if *linked {
+		if *format != "markdown" {
+			return errors.New("-linked prints markdown only")
+		}
+		if subs, err = r.SubRecipes(filepath.Clean(fs.Arg(0)), fileResolver(opts...)); err != nil {
+			return err
+		}
+	}
+
+	switch *format {
+	case "markdown":
+		var opts []recipemd.WriteOption
+		if *unicode {
+			opts = append(opts, recipemd.UnicodeFractions())
+		}
+		if err := recipemd.WriteMarkdown(stdout, r, opts...); err != nil {
+			return err
+		}
+		return writeSubRecipes(stdout, stderr, r, subs, opts)
+	case "json":
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "env":
+		return writeEnv(stdout, r)
+	}
+	return fmt.Errorf("unknown format %q", *format)
+}
+
+// writeSubRecipes writes the sub-recipes of r after it, each preceded by
+// a blank line, and reports by what factor each is scaled on stderr.
+func writeSubRecipes(stdout, stderr io.Writer, r *recipemd.Recipe, subs []recipemd.SubRecipe, opts []recipemd.WriteOption) error {
+	ingredients := r.AllIngredients()
+	for _, s := range subs {
+		in := ingredients[s.Index]
+		what := in.Name
+		if in.Amount != nil {
+			what = in.Amount.String() + " " + what
+		}
+		fmt.Fprintf(stderr, "%s: scaled by %s for %s in %s\n", s.Location, amount.Format(s.Factor), what, r.Title)
+		fmt.Fprintln(stdout)
+		if err := recipemd.WriteMarkdown(stdout, s.Recipe, opts...); err != nil {
+			return err
+		}
+		if err := writeSubRecipes(stdout, stderr, s.Recipe, s.SubRecipes, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envSummaryLength is the maximum length of the SUMMARY variable.
+const envSummaryLength = 160
+
+// writeEnv writes metadata of r as shell variable assignments, one per
+// line, which a shell script can eval or source:
+//
+//	TITLE='Guacamole'
+//	YIELD='4 servings'
+//	INGREDIENT_COUNT=7
+//
+// YIELD is the first yield and YIELDS all of them, comma separated.
+func writeEnv(w io.Writer, r *recipemd.Recipe) error {
+	var yield string
+	yields := make([]string, len(r.Yields))
+	for i, y := range r.Yields {
+		yields[i] = y.String()
+	}
+	if len(yields) > 0 {
+		yield = yields[0]
+	}
+	vars := []struct{ name, value string }{
+		{"TITLE", r.Title},
+		{"SUMMARY", r.Summary(envSummaryLength)},
+		{"TAGS", strings.Join(r.Tags, ",")},
+		{"YIELD", yield},
+		{"YIELDS", strings.Join(yields, ",")},
+		{"INGREDIENT_COUNT", strconv.Itoa(len(r.AllIngredients()))},
+		{"DIFFICULTY", r.Difficulty(recipemd.DefaultComplexity)},
+		{"FINGERPRINT", r.Fingerprint()},
+		{"HASH", r.Hash()},
+	}
+	for _, v := range vars {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", v.name, shellQuote(v.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shellQuote quotes s for a POSIX shell. Strings of only safe characters
+// are left as they are.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-.,/+:@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// unitClasses parses a comma separated list of unit classes. "all" and
+// the empty string stand for no restriction.
+func unitClasses(s string) ([]units.Class, error) {
+	var classes []units.Class
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "all" {
+			continue
+		}
+		if !slices.Contains(units.Classes, units.Class(name)) {
+			return nil, fmt.Errorf("unknown unit class %q", name)
+		}
+		classes = append(classes, units.Class(name))
+	}
+	return classes, nil
+}
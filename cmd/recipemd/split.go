@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/slug"
+)
+
+var splitCommand = &command{
+	name:    "split",
+	usage:   "[-o dir] [-n] file",
+	summary: "split a file of several recipes into a file per recipe",
+	run:     runSplit,
+}
+
+func runSplit(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	dir := fs.String("o", ".", "write the recipes to `dir`")
+	dryRun := fs.Bool("n", false, "print the names of the files without writing them")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	source, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	parts := recipemd.Split(source)
+	names := make([]string, len(parts))
+	used := make(map[string]bool)
+	for i, part := range parts {
+		r, _ := recipemd.ParsePartial(part)
+		base := slug.Make(r.Title)
+		if base == "" {
+			base = "recipe"
+		}
+		name := base
+		for n := 2; used[name]; n++ {
+			name = base + "-" + strconv.Itoa(n)
+		}
+		used[name] = true
+		names[i] = filepath.Join(*dir, name+".md")
+		if _, err := os.Stat(names[i]); err == nil {
+			return fmt.Errorf("%s already exists", names[i])
+		}
+	}
+	if !*dryRun {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return err
+		}
+	}
+	for i, part := range parts {
+		fmt.Fprintln(stdout, names[i])
+		if *dryRun {
+			continue
+		}
+		// parts share the array of source, so the newline must not be
+		// appended in place
+		out := append(bytes.Clone(bytes.TrimRight(part, " \t\r\n")), '\n')
+		if err := os.WriteFile(names[i], out, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
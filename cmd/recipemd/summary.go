@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// batchSummary is the machine-readable result of checking a batch of
+// files, for dashboards that track the health of a recipe repository
+// over time. Rules counts the findings per rule, where the rule of a
+// validation finding is its diagnostic code, and Severity gives the worst
+// severity of each file checked: "error", "warning" or "ok".
+type batchSummary struct {
+	Pass        bool              `json:"pass"`
+	Files       int               `json:"files"`
+	FailedFiles int               `json:"failed_files"`
+	Errors      int               `json:"errors"`
+	Warnings    int               `json:"warnings"`
+	Rules       map[string]int    `json:"rules"`
+	Severity    map[string]string `json:"severity"`
+}
+
+// summarize returns the summary of the findings of checking files.
+func summarize(files []string, findings []finding) batchSummary {
+	s := batchSummary{
+		Files:    len(files),
+		Rules:    make(map[string]int),
+		Severity: make(map[string]string),
+	}
+	for _, f := range files {
+		s.Severity[f] = "ok"
+	}
+	for _, x := range findings {
+		rule := x.Rule
+		if rule == ruleValidate && x.Code != "" {
+			rule = string(x.Code)
+		}
+		s.Rules[rule]++
+		if x.Severity == recipemd.SeverityError {
+			s.Errors++
+			s.Severity[x.File] = "error"
+		} else {
+			s.Warnings++
+			if s.Severity[x.File] != "error" {
+				s.Severity[x.File] = "warning"
+			}
+		}
+	}
+	for _, sev := range s.Severity {
+		if sev == "error" {
+			s.FailedFiles++
+		}
+	}
+	s.Pass = s.Errors == 0
+	return s
+}
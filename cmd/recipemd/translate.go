@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var translateCommand = &command{
+	name:    "translate",
+	usage:   "[-import] file",
+	summary: "export a recipe's text for translation, or rebuild a translated recipe",
+	run:     runTranslate,
+}
+
+func runTranslate(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	imp := fs.Bool("import", false, "read a translation file written by translate and print the translated recipe")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return &exitError{code: 2}
+	}
+	if *imp {
+		data, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		var t recipemd.Translation
+		if err := json.Unmarshal(data, &t); err != nil {
+			return fmt.Errorf("%s: %w", fs.Arg(0), err)
+		}
+		r, err := t.Recipe()
+		if err != nil {
+			return fmt.Errorf("%s: %w", fs.Arg(0), err)
+		}
+		return recipemd.WriteMarkdown(stdout, r)
+	}
+	r, err := parseFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	t, err := r.Translation()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+var validateCommand = &command{
+	name:    "validate",
+	usage:   "[-format text|json|sarif|summary] [path ...]",
+	summary: "check recipes against the RecipeMD specification",
+	run:     runValidate,
+}
+
+// fileDiagnostic is a diagnostic together with the file it was found in.
+type fileDiagnostic struct {
+	File string `json:"file"`
+	recipemd.Diagnostic
+}
+
+func runValidate(c *command, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet(c, stderr)
+	format := fs.String("format", "text", "output `format`: text, json, sarif or summary (totals as JSON)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" && *format != "sarif" && *format != "summary" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	files, err := recipeFiles(fs.Args())
+	if err != nil {
+		return err
+	}
+	diags := []fileDiagnostic{}
+	failed := false
+	for _, f := range files {
+		source, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		for _, d := range recipemd.Validate(source) {
+			diags = append(diags, fileDiagnostic{File: f, Diagnostic: d})
+			failed = failed || d.Severity == recipemd.SeverityError
+		}
+	}
+	switch *format {
+	case "json", "sarif", "summary":
+		var v any = diags
+		if *format != "json" {
+			findings := make([]finding, len(diags))
+			for i, d := range diags {
+				findings[i] = finding{File: d.File, Rule: ruleValidate, Diagnostic: d.Diagnostic}
+			}
+			if *format == "sarif" {
+				v = sarifLog(findings)
+			} else {
+				v = summarize(files, findings)
+			}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	default:
+		for _, d := range diags {
+			fmt.Fprintf(stdout, "%s:%s: %s: %s\n", d.File, d.Pos, d.Severity, d.Message)
+		}
+	}
+	if failed {
+		return &exitError{code: 1}
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package recipemdpb
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// FromRecipe returns the message of r. The sections of the document r was
+// parsed from are not part of it, as they are not part of the JSON.
+func FromRecipe(r *recipemd.Recipe) *Recipe {
+	m := &Recipe{
+		Title:            r.Title,
+		Description:      r.Description,
+		PrepTime:         fromDuration(r.PrepTime),
+		CookTime:         fromDuration(r.CookTime),
+		TotalTime:        fromDuration(r.TotalTime),
+		Tags:             r.Tags,
+		Ingredients:      fromIngredients(r.Ingredients),
+		IngredientNotes:  fromNotes(r.IngredientNotes),
+		IngredientGroups: fromGroups(r.IngredientGroups),
+		Instructions:     r.Instructions,
+	}
+	for _, img := range r.Images {
+		m.Images = append(m.Images, fromImage(&img))
+	}
+	for _, y := range r.Yields {
+		m.Yields = append(m.Yields, FromAmount(&y))
+	}
+	for _, a := range r.Appendices {
+		m.Appendices = append(m.Appendices, &Appendix{Title: a.Title, Text: a.Text})
+	}
+	for _, s := range r.InstructionSteps {
+		step := &Step{Number: int32(s.Number), Text: s.Text, Ingredients: fromIngredients(s.Ingredients)}
+		for _, t := range s.Timers {
+			step.Timers = append(step.Timers, &Timer{Text: t.Text, Duration: fromDuration(t.Duration), Max: fromDuration(t.Max)})
+		}
+		m.InstructionSteps = append(m.InstructionSteps, step)
+	}
+	return m
+}
+
+// ToRecipe returns the recipe of m. The error reports a factor that is not
+// a number.
+func (m *Recipe) ToRecipe() (*recipemd.Recipe, error) {
+	r := &recipemd.Recipe{
+		Title:        m.GetTitle(),
+		Description:  m.GetDescription(),
+		PrepTime:     m.GetPrepTime().AsDuration(),
+		CookTime:     m.GetCookTime().AsDuration(),
+		TotalTime:    m.GetTotalTime().AsDuration(),
+		Tags:         m.GetTags(),
+		Instructions: m.GetInstructions(),
+	}
+	var err error
+	if r.Ingredients, err = toIngredients(m.GetIngredients()); err != nil {
+		return nil, err
+	}
+	r.IngredientNotes = toNotes(m.GetIngredientNotes())
+	if r.IngredientGroups, err = toGroups(m.GetIngredientGroups()); err != nil {
+		return nil, err
+	}
+	for _, img := range m.GetImages() {
+		r.Images = append(r.Images, *toImage(img))
+	}
+	for _, y := range m.GetYields() {
+		a, err := y.ToAmount()
+		if err != nil {
+			return nil, fmt.Errorf("yield: %w", err)
+		}
+		r.Yields = append(r.Yields, *a)
+	}
+	for _, a := range m.GetAppendices() {
+		r.Appendices = append(r.Appendices, recipemd.Appendix{Title: a.GetTitle(), Text: a.GetText()})
+	}
+	for _, s := range m.GetInstructionSteps() {
+		step := recipemd.Step{Number: int(s.GetNumber()), Text: s.GetText()}
+		if step.Ingredients, err = toIngredients(s.GetIngredients()); err != nil {
+			return nil, err
+		}
+		for _, t := range s.GetTimers() {
+			step.Timers = append(step.Timers, recipemd.Timer{Text: t.GetText(), Duration: t.GetDuration().AsDuration(), Max: t.GetMax().AsDuration()})
+		}
+		r.InstructionSteps = append(r.InstructionSteps, step)
+	}
+	return r, nil
+}
+
+// FromAmount returns the message of a.
+func FromAmount(a *recipemd.Amount) *Amount {
+	m := &Amount{Unit: a.Unit, Approximate: a.Approx}
+	if a.Factor != nil {
+		m.Factor = a.Factor.RatString()
+	}
+	if a.Max != nil {
+		m.Max = a.Max.RatString()
+	}
+	if a.Size != nil {
+		m.Size = FromAmount(a.Size)
+	}
+	return m
+}
+
+// ToAmount returns the amount of m. The error reports a factor or maximum
+// that is not a number.
+func (m *Amount) ToAmount() (*recipemd.Amount, error) {
+	a := &recipemd.Amount{Unit: m.GetUnit(), Approx: m.GetApproximate()}
+	var err error
+	if a.Factor, err = toRat(m.GetFactor()); err != nil {
+		return nil, err
+	}
+	if a.Max, err = toRat(m.GetMax()); err != nil {
+		return nil, err
+	}
+	if m.GetSize() != nil {
+		if a.Size, err = m.GetSize().ToAmount(); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func toRat(s string) (*big.Rat, error) {
+	if s == "" {
+		return nil, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("recipemdpb: invalid number %q", s)
+	}
+	return r, nil
+}
+
+func fromDuration(d time.Duration) *durationpb.Duration {
+	if d == 0 {
+		return nil
+	}
+	return durationpb.New(d)
+}
+
+func fromImage(img *recipemd.Image) *Image {
+	if img == nil {
+		return nil
+	}
+	return &Image{Url: img.URL, Alt: img.Alt, Title: img.Title}
+}
+
+func toImage(m *Image) *recipemd.Image {
+	if m == nil {
+		return nil
+	}
+	return &recipemd.Image{URL: m.GetUrl(), Alt: m.GetAlt(), Title: m.GetTitle()}
+}
+
+func fromNotes(notes []recipemd.Note) []*Note {
+	var ms []*Note
+	for _, n := range notes {
+		ms = append(ms, &Note{Text: n.Text, Index: int32(n.Index)})
+	}
+	return ms
+}
+
+func toNotes(ms []*Note) []recipemd.Note {
+	var notes []recipemd.Note
+	for _, m := range ms {
+		notes = append(notes, recipemd.Note{Text: m.GetText(), Index: int(m.GetIndex())})
+	}
+	return notes
+}
+
+func fromIngredients(ingredients []recipemd.Ingredient) []*Ingredient {
+	var ms []*Ingredient
+	for _, in := range ingredients {
+		m := &Ingredient{
+			Name:        in.Name,
+			Link:        in.Link,
+			Pinned:      in.Pinned,
+			Note:        in.Note,
+			Preparation: in.Preparation,
+			Optional:    in.Optional,
+			Text:        in.Text,
+			Markdown:    in.Markdown,
+			Image:       fromImage(in.Image),
+		}
+		if in.Amount != nil {
+			m.Amount = FromAmount(in.Amount)
+		}
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+func toIngredients(ms []*Ingredient) ([]recipemd.Ingredient, error) {
+	var ingredients []recipemd.Ingredient
+	for _, m := range ms {
+		in := recipemd.Ingredient{
+			Name:        m.GetName(),
+			Link:        m.GetLink(),
+			Pinned:      m.GetPinned(),
+			Note:        m.GetNote(),
+			Preparation: m.GetPreparation(),
+			Optional:    m.GetOptional(),
+			Text:        m.GetText(),
+			Markdown:    m.GetMarkdown(),
+			Image:       toImage(m.GetImage()),
+		}
+		if m.GetAmount() != nil {
+			a, err := m.GetAmount().ToAmount()
+			if err != nil {
+				return nil, fmt.Errorf("ingredient %q: %w", in.Name, err)
+			}
+			in.Amount = a
+		}
+		ingredients = append(ingredients, in)
+	}
+	return ingredients, nil
+}
+
+func fromGroups(groups []recipemd.IngredientGroup) []*IngredientGroup {
+	var ms []*IngredientGroup
+	for _, g := range groups {
+		ms = append(ms, &IngredientGroup{
+			Title:            g.Title,
+			Ingredients:      fromIngredients(g.Ingredients),
+			Notes:            fromNotes(g.Notes),
+			IngredientGroups: fromGroups(g.IngredientGroups),
+			Image:            fromImage(g.Image),
+		})
+	}
+	return ms
+}
+
+func toGroups(ms []*IngredientGroup) ([]recipemd.IngredientGroup, error) {
+	var groups []recipemd.IngredientGroup
+	for _, m := range ms {
+		g := recipemd.IngredientGroup{
+			Title: m.GetTitle(),
+			Notes: toNotes(m.GetNotes()),
+			Image: toImage(m.GetImage()),
+		}
+		var err error
+		if g.Ingredients, err = toIngredients(m.GetIngredients()); err != nil {
+			return nil, err
+		}
+		if g.IngredientGroups, err = toGroups(m.GetIngredientGroups()); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
@@ -0,0 +1,70 @@
+package recipemdpb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/conformance"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// TestRoundTrip converts the recipes of the conformance corpus to messages
+// and back through their wire format.
+func TestRoundTrip(t *testing.T) {
+	for _, c := range conformance.Cases() {
+		t.Run(c.Name, func(t *testing.T) {
+			r, err := recipemd.Parse(c.Source, recipemd.WithInstructionSteps())
+			if err != nil {
+				t.Fatal(err)
+			}
+			wire, err := proto.Marshal(FromRecipe(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var m Recipe
+			if err := proto.Unmarshal(wire, &m); err != nil {
+				t.Fatal(err)
+			}
+			got, err := m.ToRecipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := json.Marshal(r)
+			if g, _ := json.Marshal(got); string(g) != string(want) {
+				t.Errorf("round trip gives\n%s\nwant\n%s", g, want)
+			}
+		})
+	}
+}
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want *Amount
+	}{
+		{"1 1/2 cups", &Amount{Factor: "3/2", Unit: "cups"}},
+		{"~2-3 EL", &Amount{Factor: "2", Max: "3", Unit: "EL", Approximate: true}},
+		{"2 x 400 g cans", &Amount{Factor: "2", Unit: "cans", Size: &Amount{Factor: "400", Unit: "g"}}},
+		{"salt to taste", &Amount{Unit: "salt to taste"}},
+	}
+	for _, tt := range tests {
+		a := amount.Parse(tt.in)
+		m := FromAmount(&a)
+		if !proto.Equal(m, tt.want) {
+			t.Errorf("FromAmount(%q) = %v, want %v", tt.in, m, tt.want)
+		}
+		back, err := m.ToAmount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.String() != a.String() {
+			t.Errorf("ToAmount of %q = %q", tt.in, back)
+		}
+	}
+	if _, err := (&Amount{Factor: "lots"}).ToAmount(); err == nil {
+		t.Error("ToAmount with the factor \"lots\" succeeded")
+	}
+}
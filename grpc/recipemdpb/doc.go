@@ -0,0 +1,11 @@
+// Package recipemdpb holds the protocol buffer messages of recipe.proto,
+// the RecipeMD recipe model for programs in other languages, with their
+// conversions to and from recipemd.Recipe, and the RecipeService that
+// package server implements.
+//
+// The messages carry what the JSON of a recipe carries; amounts keep
+// their factors as exact fractions such as "3/2", so a recipe converted to
+// a message and back is the recipe it was.
+package recipemdpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative recipe.proto
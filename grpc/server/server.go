@@ -0,0 +1,139 @@
+// Package server implements the RecipeService of package recipemdpb for a
+// collection of RecipeMD files:
+//
+//	s := grpc.NewServer()
+//	recipemdpb.RegisterRecipeServiceServer(s, server.New(c))
+//	err := s.Serve(lis)
+//
+// Like the website of pkg/server, it refreshes the collection on every
+// call, or relies on Collection.Watch if created with Watched, and answers
+// every call from a single snapshot.
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xcapaldi/recipemd-go/grpc/recipemdpb"
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/filter"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// Server serves the recipes of a collection.
+type Server struct {
+	recipemdpb.UnimplementedRecipeServiceServer
+
+	c       *collection.Collection
+	watched bool
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// Watched tells the server that the collection is refreshed by
+// Collection.Watch, so calls do not refresh it.
+func Watched() Option {
+	return func(s *Server) {
+		s.watched = true
+	}
+}
+
+// New returns a Server for c.
+func New(c *collection.Collection, opts ...Option) *Server {
+	s := &Server{c: c}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Server) snapshot() (*collection.Snapshot, error) {
+	if !s.watched {
+		if _, err := s.c.Refresh(); err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+	return s.c.Snapshot(), nil
+}
+
+// Get returns the recipe with the slug of req.
+func (s *Server) Get(ctx context.Context, req *recipemdpb.GetRequest) (*recipemdpb.Entry, error) {
+	snap, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	r, ok := snap.Get(req.GetSlug())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no recipe %q", req.GetSlug())
+	}
+	return entry(r), nil
+}
+
+// List returns the recipes, or those with the tag of req, sorted by title.
+func (s *Server) List(ctx context.Context, req *recipemdpb.ListRequest) (*recipemdpb.ListResponse, error) {
+	snap, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	recipes := snap.Recipes()
+	if req.GetTag() != "" {
+		recipes = snap.Tagged(req.GetTag())
+	}
+	return list(recipes), nil
+}
+
+// Search returns the recipes matching the filter expression of req,
+// sorted by title. A malformed expression is an InvalidArgument error.
+func (s *Server) Search(ctx context.Context, req *recipemdpb.SearchRequest) (*recipemdpb.ListResponse, error) {
+	q, err := collection.ParseQuery(req.GetQuery())
+	if err != nil {
+		var syntax *filter.SyntaxError
+		if errors.As(err, &syntax) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+	snap, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return list(snap.Query(q)), nil
+}
+
+// Render writes the recipe with the slug of req in its format.
+func (s *Server) Render(ctx context.Context, req *recipemdpb.RenderRequest) (*recipemdpb.RenderResponse, error) {
+	render, ext, ok := recipemd.LookupFormat(req.GetFormat())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown format %q", req.GetFormat())
+	}
+	snap, err := s.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	r, ok := snap.Get(req.GetSlug())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no recipe %q", req.GetSlug())
+	}
+	var b bytes.Buffer
+	if err := render.Render(&b, r.Recipe); err != nil {
+		return nil, status.Errorf(codes.Internal, "%s: %v", r.Path, err)
+	}
+	return &recipemdpb.RenderResponse{Data: b.Bytes(), Extension: ext}, nil
+}
+
+func entry(r *collection.Recipe) *recipemdpb.Entry {
+	return &recipemdpb.Entry{Slug: r.Slug, Path: r.Path, Recipe: recipemdpb.FromRecipe(r.Recipe)}
+}
+
+func list(recipes []*collection.Recipe) *recipemdpb.ListResponse {
+	resp := &recipemdpb.ListResponse{}
+	for _, r := range recipes {
+		resp.Entries = append(resp.Entries, entry(r))
+	}
+	return resp
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/xcapaldi/recipemd-go/grpc/recipemdpb"
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+)
+
+func newTestClient(t *testing.T) recipemdpb.RecipeServiceClient {
+	t.Helper()
+	c, err := collection.Load(fstest.MapFS{
+		"tea.md":       {Data: []byte("# Tea\n\n*hot*\n\n---\n\n- *1* tea bag\n")},
+		"soup/leek.md": {Data: []byte("# Leek soup\n\n*hot, soup*\n\n---\n\n- *2* leeks\n")},
+		"lemonade.md":  {Data: []byte("# Lemonade\n\n*cold*\n\n---\n\n- *3* lemons\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	recipemdpb.RegisterRecipeServiceServer(s, New(c))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return recipemdpb.NewRecipeServiceClient(conn)
+}
+
+func slugs(resp *recipemdpb.ListResponse) string {
+	var s []string
+	for _, e := range resp.GetEntries() {
+		s = append(s, e.GetSlug())
+	}
+	return strings.Join(s, " ")
+}
+
+func TestServer(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	e, err := client.Get(ctx, &recipemdpb.GetRequest{Slug: "soup/leek"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.GetPath() != "soup/leek.md" || e.GetRecipe().GetTitle() != "Leek soup" || e.GetRecipe().GetIngredients()[0].GetAmount().GetFactor() != "2" {
+		t.Errorf("Get = %v", e)
+	}
+	if _, err := client.Get(ctx, &recipemdpb.GetRequest{Slug: "cake"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get of a missing recipe: %v, want NotFound", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() (*recipemdpb.ListResponse, error)
+		want string
+	}{
+		{"list", func() (*recipemdpb.ListResponse, error) { return client.List(ctx, &recipemdpb.ListRequest{}) }, "soup/leek lemonade tea"},
+		{"list tag", func() (*recipemdpb.ListResponse, error) { return client.List(ctx, &recipemdpb.ListRequest{Tag: "hot"}) }, "soup/leek tea"},
+		{"search", func() (*recipemdpb.ListResponse, error) {
+			return client.Search(ctx, &recipemdpb.SearchRequest{Query: "hot and not soup"})
+		}, "tea"},
+	}
+	for _, tt := range tests {
+		resp, err := tt.call()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := slugs(resp); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if _, err := client.Search(ctx, &recipemdpb.SearchRequest{Query: "tag:"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Search with a malformed query: %v, want InvalidArgument", err)
+	}
+
+	r, err := client.Render(ctx, &recipemdpb.RenderRequest{Slug: "tea", Format: "markdown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.GetExtension() != ".md" || !strings.HasPrefix(string(r.GetData()), "# Tea\n") {
+		t.Errorf("Render = %q %q", r.GetData(), r.GetExtension())
+	}
+	if _, err := client.Render(ctx, &recipemdpb.RenderRequest{Slug: "tea", Format: "pdf"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Render in an unknown format: %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,374 @@
+// Package amount parses, scales and formats RecipeMD amounts such as
+// "1 1/2 cups" or "200 g".
+package amount
+
+import (
+	"math/big"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Amount is a quantity consisting of an optional numeric factor and an
+// optional unit. An amount without a factor keeps its whole text in Unit.
+// A range such as "2-3 cups" has its lower end in Factor and its upper end
+// in Max, which is nil for single values. Approx marks amounts given as
+// approximate, such as "ca. 200 g".
+//
+// A package amount such as "2 x 400 g cans" or "1 can (400 g)" counts
+// packages: Factor is the count, Unit the container, if any, and Size the
+// amount in each package.
+type Amount struct {
+	Factor *big.Rat
+	Max    *big.Rat
+	Unit   string
+	Approx bool
+	Size   *Amount
+}
+
+var (
+	mixedRe    = regexp.MustCompile(`^(\d+)\s+(\d+)\s*[/⁄]\s*(\d+)`)
+	fractionRe = regexp.MustCompile(`^(\d+)\s*[/⁄]\s*(\d+)`)
+	vulgarRe   = regexp.MustCompile(`^(?:(\d+)\s*)?([½⅓⅔¼¾⅕⅖⅗⅘⅙⅚⅐⅛⅜⅝⅞⅑⅒])`)
+	decimalRe  = regexp.MustCompile(`^(\d*)[.,](\d+)`)
+	rangeRe    = regexp.MustCompile(`^\s*(?:[-–—]|to\s)\s*`)
+	integerRe  = regexp.MustCompile(`^\d+`)
+	timesRe    = regexp.MustCompile(`^\s*[x×*]\s*`)
+	packageRe  = regexp.MustCompile(`^(.*?)\s*\((.+)\)$|^\((.+?)\)\s*(.*)$`)
+	approxRe   = regexp.MustCompile(`^(?i)(?:[~≈]|ca\.|approx\.|(?:ca|circa|approx|approximately|about|around)\s)\s*`)
+	wordRe     = regexp.MustCompile(`^(?i)(an?|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve|half(?:\s+an?)?)(\s+and\s+a\s+half)?(\s+dozen)?(?:\s+|$)`)
+)
+
+// wordNumbers are the values of the number words Parse recognizes.
+var wordNumbers = map[string]int64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11,
+	"twelve": 12,
+}
+
+// vagueWords follow "a" in quantities that are not a count, such as
+// "a few".
+var vagueWords = []string{"few", "little", "bit", "lot", "couple"}
+
+// vulgarFractions are the values of the unicode vulgar fraction
+// characters.
+var vulgarFractions = map[rune]string{
+	'½': "1/2", '⅓': "1/3", '⅔': "2/3", '¼': "1/4", '¾': "3/4",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5", '⅙': "1/6",
+	'⅚': "5/6", '⅐': "1/7", '⅛': "1/8", '⅜': "3/8", '⅝': "5/8",
+	'⅞': "7/8", '⅑': "1/9", '⅒': "1/10",
+}
+
+// Parse splits s into a factor and a unit. Improper fractions ("1 1/2"),
+// fractions ("1/2", also with the fraction slash "1⁄2"), unicode vulgar
+// fractions with an optional whole number ("½", "1½", "1 ½"), decimals
+// with either a point or a comma ("1.5", "1,5") and integers are
+// recognized. Two numbers joined by a hyphen, an en or em dash or "to"
+// form a range ("2-3", "2–3", "2 to 3"). Number words from "one" to
+// "twelve", "a" and "half" count as well, optionally followed by "and a
+// half" or "dozen" ("a pinch", "two dozen", "half a cup"). A leading "~",
+// "≈", "ca.", "circa", "approx.", "about" or "around" marks the amount
+// as approximate. A count followed by "x" or "×" and a size ("2 x 400 g
+// cans"), or by a container and a parenthesized size ("1 can (400 g)",
+// "1 (400 g) can"), is a package amount; see RegisterMeasure for what
+// counts as a container. If s does not start with a number the whole
+// trimmed text becomes the unit.
+func Parse(s string) Amount {
+	s = strings.TrimSpace(s)
+	rest := s
+	m := approxRe.FindString(rest)
+	rest = rest[len(m):]
+	factor, rest, ok := parseFactor(rest)
+	if !ok {
+		return Amount{Unit: s}
+	}
+	a := Amount{Factor: factor, Approx: m != ""}
+	if m := rangeRe.FindString(rest); m != "" {
+		if max, r, ok := parseFactor(rest[len(m):]); ok {
+			a.Max, rest = max, r
+		}
+	}
+	a.Unit = strings.TrimSpace(rest)
+	parsePackage(&a)
+	return a
+}
+
+// isMeasure reports whether a unit spelling is a unit of measurement
+// rather than a container. It is set by RegisterMeasure.
+var isMeasure = func(string) bool { return false }
+
+// RegisterMeasure makes Parse use f to tell units of measurement, such as
+// "cup", from containers, such as "can". A parenthesized amount after a
+// unit of measurement is the same amount in another unit ("1 cup (240
+// ml)"), not the size of a package. Package units registers its built-in
+// units when it is imported.
+func RegisterMeasure(f func(unit string) bool) {
+	isMeasure = f
+}
+
+// parsePackage turns a into a package amount if its unit holds the size
+// of the packages.
+func parsePackage(a *Amount) {
+	if m := timesRe.FindString(a.Unit); m != "" {
+		size, container, ok := strings.Cut(strings.TrimSpace(a.Unit[len(m):]), " ")
+		if f, unit, ok2 := parseFactor(size); ok2 {
+			// the unit of the size may follow the number with or without
+			// a space
+			if unit == "" && ok {
+				unit, container, _ = strings.Cut(container, " ")
+			}
+			a.Size = &Amount{Factor: f, Unit: strings.TrimSpace(unit)}
+			a.Unit = strings.TrimSpace(container)
+		}
+		return
+	}
+	if m := packageRe.FindStringSubmatch(a.Unit); m != nil {
+		container, size := m[1], m[2]
+		if m[3] != "" {
+			// "1 (400 g) can"
+			container, size = m[4], m[3]
+		}
+		if isMeasure(container) {
+			// "1 cup (240 ml)" gives the amount in another unit
+			return
+		}
+		if size := Parse(size); size.Factor != nil && size.Size == nil {
+			a.Size = &size
+			a.Unit = container
+		}
+	}
+}
+
+func parseFactor(s string) (*big.Rat, string, bool) {
+	if m := vulgarRe.FindStringSubmatch(s); m != nil {
+		r, _ := new(big.Rat).SetString(vulgarFractions[[]rune(m[2])[0]])
+		if m[1] != "" {
+			whole, _ := new(big.Rat).SetString(m[1])
+			r.Add(r, whole)
+		}
+		return r, s[len(m[0]):], true
+	}
+	if m := mixedRe.FindStringSubmatch(s); m != nil {
+		whole, _ := new(big.Rat).SetString(m[1])
+		frac, ok := new(big.Rat).SetString(m[2] + "/" + m[3])
+		if ok {
+			return whole.Add(whole, frac), s[len(m[0]):], true
+		}
+	}
+	if m := fractionRe.FindStringSubmatch(s); m != nil {
+		if r, ok := new(big.Rat).SetString(m[1] + "/" + m[2]); ok {
+			return r, s[len(m[0]):], true
+		}
+	}
+	if m := decimalRe.FindStringSubmatch(s); m != nil {
+		r, _ := new(big.Rat).SetString("0" + m[1] + "." + m[2])
+		return r, s[len(m[0]):], true
+	}
+	if m := integerRe.FindString(s); m != "" {
+		r, _ := new(big.Rat).SetString(m)
+		return r, s[len(m):], true
+	}
+	return parseWords(s)
+}
+
+// parseWords parses a quantity written in words.
+func parseWords(s string) (*big.Rat, string, bool) {
+	m := wordRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, s, false
+	}
+	rest := s[len(m[0]):]
+	word := strings.ToLower(m[1])
+	var r *big.Rat
+	switch {
+	case strings.HasPrefix(word, "half"):
+		r = big.NewRat(1, 2)
+	case word == "a" || word == "an":
+		next, _, _ := strings.Cut(strings.ToLower(rest), " ")
+		if slices.Contains(vagueWords, next) {
+			return nil, s, false
+		}
+		fallthrough
+	default:
+		r = big.NewRat(wordNumbers[word], 1)
+	}
+	if m[2] != "" {
+		r.Add(r, big.NewRat(1, 2))
+	}
+	if m[3] != "" {
+		r.Mul(r, big.NewRat(12, 1))
+	}
+	return r, rest, true
+}
+
+// IsZero reports whether a has neither a factor nor a unit.
+func (a Amount) IsZero() bool {
+	return a.Factor == nil && a.Unit == ""
+}
+
+// IsRange reports whether a is a range of values.
+func (a Amount) IsRange() bool {
+	return a.Factor != nil && a.Max != nil
+}
+
+// Upper returns the upper end of a range and the factor of other amounts.
+func (a Amount) Upper() *big.Rat {
+	if a.Max != nil {
+		return a.Max
+	}
+	return a.Factor
+}
+
+// Conversion splits a unit of measurement followed by the same amount in
+// another unit, such as "cup (240 ml)", into the unit and the converted
+// amount. ok is false if unit holds no conversion.
+func Conversion(unit string) (u string, c Amount, ok bool) {
+	m := packageRe.FindStringSubmatch(unit)
+	if m == nil || m[1] == "" || !isMeasure(m[1]) {
+		return unit, Amount{}, false
+	}
+	c = Parse(m[2])
+	if c.Factor == nil || c.Size != nil {
+		return unit, Amount{}, false
+	}
+	return m[1], c, true
+}
+
+// Scale returns a copy of a with its factor, and the upper end of a range,
+// multiplied by f. Package amounts scale the count, not the size; a
+// conversion in the unit (see Conversion) is scaled as well. Amounts
+// without a factor are returned unchanged.
+func (a Amount) Scale(f *big.Rat) Amount {
+	if a.Factor == nil {
+		return a
+	}
+	s := Amount{Factor: new(big.Rat).Mul(a.Factor, f), Unit: a.Unit, Approx: a.Approx, Size: a.Size}
+	if a.Max != nil {
+		s.Max = new(big.Rat).Mul(a.Max, f)
+	}
+	if a.Size == nil {
+		if u, c, ok := Conversion(a.Unit); ok {
+			s.Unit = u + " (" + c.Scale(f).String() + ")"
+		}
+	}
+	return s
+}
+
+// String formats a as RecipeMD amount text, e.g. "1 1/2 cups", "2-3 cups",
+// "~200 g", "2 x 400 g" or "2 cans (400 g)". Number words are written as
+// digits.
+func (a Amount) String() string {
+	return a.format(Format)
+}
+
+func (a Amount) format(f func(*big.Rat) string) string {
+	if a.Factor == nil {
+		return a.Unit
+	}
+	s := f(a.Factor)
+	if a.Approx {
+		s = "~" + s
+	}
+	if a.Max != nil {
+		s += "-" + f(a.Max)
+	}
+	if a.Size != nil {
+		if a.Unit == "" {
+			return s + " x " + a.Size.format(f)
+		}
+		return s + " " + a.Unit + " (" + a.Size.format(f) + ")"
+	}
+	if a.Unit == "" {
+		return s
+	}
+	return s + " " + a.Unit
+}
+
+// Format renders r as a decimal if it has a finite decimal expansion and as
+// an (improper) fraction otherwise, so the result always parses back to r.
+func Format(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	if prec, exact := r.FloatPrec(); exact {
+		return r.FloatString(prec)
+	}
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	sign := ""
+	if num.Sign() < 0 {
+		sign = "-"
+		num.Neg(num)
+	}
+	whole, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if whole.Sign() == 0 {
+		return sign + rem.String() + "/" + den.String()
+	}
+	return sign + whole.String() + " " + rem.String() + "/" + den.String()
+}
+
+// FormatUnicode renders r like Format, but writes a fractional part of
+// halves, thirds, quarters, fifths, sixths or eighths with a unicode vulgar
+// fraction character, e.g. "1½" or "¾".
+func FormatUnicode(r *big.Rat) string {
+	if r.Sign() <= 0 || r.IsInt() {
+		return Format(r)
+	}
+	whole := new(big.Int).Quo(r.Num(), r.Denom())
+	frac := new(big.Rat).Sub(r, new(big.Rat).SetInt(whole))
+	for c, v := range vulgarFractions {
+		if frac.RatString() == v && !strings.ContainsRune("⅐⅑⅒", c) {
+			if whole.Sign() == 0 {
+				return string(c)
+			}
+			return whole.String() + string(c)
+		}
+	}
+	return Format(r)
+}
+
+// UnicodeString formats a like String, using FormatUnicode for the
+// factor.
+func (a Amount) UnicodeString() string {
+	return a.format(FormatUnicode)
+}
+
+// Decimal renders r as a decimal string, rounding values without a finite
+// decimal expansion to ten fractional digits.
+func Decimal(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	if prec, exact := r.FloatPrec(); exact {
+		return r.FloatString(prec)
+	}
+	s := strings.TrimRight(r.FloatString(10), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// SplitList splits a comma separated list of amounts, dropping empty
+// entries. Commas between two digits are decimal separators and do not
+// split.
+func SplitList(s string) []string {
+	var parts []string
+	rs := []rune(s)
+	start := 0
+	for i, r := range rs {
+		if r != ',' {
+			continue
+		}
+		if i > 0 && i < len(rs)-1 && unicode.IsDigit(rs[i-1]) && unicode.IsDigit(rs[i+1]) {
+			continue
+		}
+		parts = append(parts, string(rs[start:i]))
+		start = i + 1
+	}
+	parts = append(parts, string(rs[start:]))
+	var list []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
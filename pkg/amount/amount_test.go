@@ -0,0 +1,166 @@
+package amount
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func rat(s string) *big.Rat {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		panic("bad rational " + s)
+	}
+	return r
+}
+
+func equalRat(a, b *big.Rat) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+func equalAmount(a, b Amount) bool {
+	if !equalRat(a.Factor, b.Factor) || !equalRat(a.Max, b.Max) || a.Unit != b.Unit || a.Approx != b.Approx {
+		return false
+	}
+	if a.Size == nil || b.Size == nil {
+		return a.Size == b.Size
+	}
+	return equalAmount(*a.Size, *b.Size)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"", Amount{}},
+		{"salt to taste", Amount{Unit: "salt to taste"}},
+		{"2", Amount{Factor: rat("2")}},
+		{"200 g", Amount{Factor: rat("200"), Unit: "g"}},
+		{"200g", Amount{Factor: rat("200"), Unit: "g"}},
+		{"  3 cups  ", Amount{Factor: rat("3"), Unit: "cups"}},
+		{"1/2 cup", Amount{Factor: rat("1/2"), Unit: "cup"}},
+		{"1⁄2 cup", Amount{Factor: rat("1/2"), Unit: "cup"}},
+		{"1 1/2 cups", Amount{Factor: rat("3/2"), Unit: "cups"}},
+		{"½ tsp", Amount{Factor: rat("1/2"), Unit: "tsp"}},
+		{"1½ tsp", Amount{Factor: rat("3/2"), Unit: "tsp"}},
+		{"1 ¾ cups", Amount{Factor: rat("7/4"), Unit: "cups"}},
+		{"1.5 l", Amount{Factor: rat("3/2"), Unit: "l"}},
+		{"1,5 l", Amount{Factor: rat("3/2"), Unit: "l"}},
+		{".5 l", Amount{Factor: rat("1/2"), Unit: "l"}},
+		{"2-3 cups", Amount{Factor: rat("2"), Max: rat("3"), Unit: "cups"}},
+		{"2–3 EL", Amount{Factor: rat("2"), Max: rat("3"), Unit: "EL"}},
+		{"2 to 3 cups", Amount{Factor: rat("2"), Max: rat("3"), Unit: "cups"}},
+		{"~200 g", Amount{Factor: rat("200"), Unit: "g", Approx: true}},
+		{"ca. 200 g", Amount{Factor: rat("200"), Unit: "g", Approx: true}},
+		{"about 2 cups", Amount{Factor: rat("2"), Unit: "cups", Approx: true}},
+		{"a pinch", Amount{Factor: rat("1"), Unit: "pinch"}},
+		{"two dozen", Amount{Factor: rat("24")}},
+		{"half a cup", Amount{Factor: rat("1/2"), Unit: "cup"}},
+		{"one and a half cups", Amount{Factor: rat("3/2"), Unit: "cups"}},
+		{"a few", Amount{Unit: "a few"}},
+		{"2 x 400 g cans", Amount{Factor: rat("2"), Unit: "cans", Size: &Amount{Factor: rat("400"), Unit: "g"}}},
+		{"2 × 400g", Amount{Factor: rat("2"), Size: &Amount{Factor: rat("400"), Unit: "g"}}},
+		{"1 can (400 g)", Amount{Factor: rat("1"), Unit: "can", Size: &Amount{Factor: rat("400"), Unit: "g"}}},
+		{"1 (400 g) can", Amount{Factor: rat("1"), Unit: "can", Size: &Amount{Factor: rat("400"), Unit: "g"}}},
+		{"1 can (drained)", Amount{Factor: rat("1"), Unit: "can (drained)"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.in); !equalAmount(got, tt.want) {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in, want, unicode string
+	}{
+		{"salt to taste", "salt to taste", "salt to taste"},
+		{"200 g", "200 g", "200 g"},
+		{"1 1/2 cups", "1.5 cups", "1½ cups"},
+		{"1/3 cup", "1/3 cup", "⅓ cup"},
+		{"4/3 cup", "1 1/3 cup", "1⅓ cup"},
+		{"1/7", "1/7", "1/7"},
+		{"2–3 EL", "2-3 EL", "2-3 EL"},
+		{"ca. 200 g", "~200 g", "~200 g"},
+		{"two dozen", "24", "24"},
+		{"2 x 400 g", "2 x 400 g", "2 x 400 g"},
+		{"1 (400 g) can", "1 can (400 g)", "1 can (400 g)"},
+	}
+	for _, tt := range tests {
+		a := Parse(tt.in)
+		if got := a.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := a.UnicodeString(); got != tt.unicode {
+			t.Errorf("Parse(%q).UnicodeString() = %q, want %q", tt.in, got, tt.unicode)
+		}
+		if again := Parse(a.String()); !equalAmount(again, a) && !a.Approx {
+			t.Errorf("Parse(%q) = %#v, want %#v", a.String(), again, a)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		in     string
+		factor string
+		want   string
+	}{
+		{"200 g", "2", "400 g"},
+		{"1/2 cup", "3", "1.5 cup"},
+		{"2-3 cups", "2", "4-6 cups"},
+		{"~200 g", "1/2", "~100 g"},
+		{"salt to taste", "2", "salt to taste"},
+		{"2 x 400 g", "2", "4 x 400 g"},
+		{"1 can (400 g)", "3", "3 can (400 g)"},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.in).Scale(rat(tt.factor)).String(); got != tt.want {
+			t.Errorf("Parse(%q).Scale(%s) = %q, want %q", tt.in, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in, want, decimal string
+	}{
+		{"3", "3", "3"},
+		{"1/2", "0.5", "0.5"},
+		{"1/3", "1/3", "0.3333333333"},
+		{"7/3", "2 1/3", "2.3333333333"},
+		{"-7/3", "-2 1/3", "-2.3333333333"},
+		{"2/3", "2/3", "0.6666666667"},
+	}
+	for _, tt := range tests {
+		if got := Format(rat(tt.in)); got != tt.want {
+			t.Errorf("Format(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := Decimal(rat(tt.in)); got != tt.decimal {
+			t.Errorf("Decimal(%s) = %q, want %q", tt.in, got, tt.decimal)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"4 servings", []string{"4 servings"}},
+		{"4 servings, 1 loaf", []string{"4 servings", "1 loaf"}},
+		{"1,5 l, 2 cups,", []string{"1,5 l", "2 cups"}},
+		{" , a,,b ", []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		if got := SplitList(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitList(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package amount
+
+import (
+	"math/big"
+	"testing"
+)
+
+// FuzzParse checks that every string parses to an amount that prints,
+// scales and parses again. Its seed corpus in testdata/fuzz holds the
+// amounts of the conformance corpus of pkg/conformance.
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"", "1 1/2 cups", "2-3 EL", "2 x 400 g cans", "1 can (400 g)", "one and a half cups"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		a := Parse(s)
+		if a.Max != nil && a.Factor == nil {
+			t.Fatalf("Parse(%q) has a maximum but no factor: %#v", s, a)
+		}
+		_ = a.UnicodeString()
+		_ = a.Scale(big.NewRat(3, 2)).String()
+		if a.Factor == nil || a.Approx {
+			return
+		}
+		if again := Parse(a.String()); !equalRat(again.Factor, a.Factor) {
+			t.Errorf("Parse(%q) = %s, which parses to %#v", s, a, again)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package amount_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	// registers the units of measurement
+	_ "github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+func TestParseMeasure(t *testing.T) {
+	tests := []struct {
+		in, unit, size string
+	}{
+		{"1 cup (240 ml)", "cup (240 ml)", ""},
+		{"2 tbsp (30 g)", "tbsp (30 g)", ""},
+		{"1 Cup (240 ml)", "Cup (240 ml)", ""},
+		{"1 can (400 g)", "can", "400 g"},
+		{"1 stick (113 g)", "stick", "113 g"},
+		{"1 (400 g) can", "can", "400 g"},
+		{"1 jar (350 g)", "jar", "350 g"},
+	}
+	for _, tt := range tests {
+		a := amount.Parse(tt.in)
+		size := ""
+		if a.Size != nil {
+			size = a.Size.String()
+		}
+		if a.Unit != tt.unit || size != tt.size {
+			t.Errorf("Parse(%q) has unit %q and size %q, want %q and %q", tt.in, a.Unit, size, tt.unit, tt.size)
+		}
+	}
+}
+
+func TestConversion(t *testing.T) {
+	tests := []struct {
+		in, unit, conversion string
+		ok                   bool
+	}{
+		{"cup (240 ml)", "cup", "240 ml", true},
+		{"Tbsp. (15 ml)", "Tbsp.", "15 ml", true},
+		{"cup", "cup", "", false},
+		{"can (400 g)", "can (400 g)", "", false},
+		{"cup (heaped)", "cup (heaped)", "", false},
+		{"(240 ml) cup", "(240 ml) cup", "", false},
+	}
+	for _, tt := range tests {
+		unit, c, ok := amount.Conversion(tt.in)
+		conversion := ""
+		if ok {
+			conversion = c.String()
+		}
+		if unit != tt.unit || conversion != tt.conversion || ok != tt.ok {
+			t.Errorf("Conversion(%q) = %q, %q, %v, want %q, %q, %v", tt.in, unit, conversion, ok, tt.unit, tt.conversion, tt.ok)
+		}
+	}
+}
+
+func TestScaleConversion(t *testing.T) {
+	tests := []struct {
+		in     string
+		factor int64
+		want   string
+	}{
+		{"1 cup (240 ml)", 2, "2 cup (480 ml)"},
+		{"2-3 cups (500-750 ml)", 2, "4-6 cups (1000-1500 ml)"},
+		{"1 can (400 g)", 2, "2 can (400 g)"},
+	}
+	for _, tt := range tests {
+		if got := amount.Parse(tt.in).Scale(big.NewRat(tt.factor, 1)).String(); got != tt.want {
+			t.Errorf("Parse(%q).Scale(%d) = %q, want %q", tt.in, tt.factor, got, tt.want)
+		}
+	}
+}
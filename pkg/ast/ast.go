@@ -0,0 +1,297 @@
+// Package ast defines the goldmark AST nodes that make up a RecipeMD
+// document.
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+)
+
+// KindRecipe is a NodeKind of the Recipe node.
+var KindRecipe = gast.NewNodeKind("Recipe")
+
+// Recipe is a block node wrapping every section of a RecipeMD document.
+type Recipe struct {
+	gast.BaseBlock
+}
+
+// Kind implements Node.Kind.
+func (n *Recipe) Kind() gast.NodeKind {
+	return KindRecipe
+}
+
+// Dump implements Node.Dump.
+func (n *Recipe) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// NewRecipe returns a new Recipe node.
+func NewRecipe() *Recipe {
+	return &Recipe{}
+}
+
+// KindTitle is a NodeKind of the Title node.
+var KindTitle = gast.NewNodeKind("Title")
+
+// Title is a block node holding the inline content of the recipe's
+// first-level heading.
+type Title struct {
+	gast.BaseBlock
+	Title string
+}
+
+// Kind implements Node.Kind.
+func (n *Title) Kind() gast.NodeKind {
+	return KindTitle
+}
+
+// Dump implements Node.Dump.
+func (n *Title) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{"Title": n.Title}, nil)
+}
+
+// NewTitle returns a new Title node.
+func NewTitle(title string) *Title {
+	return &Title{Title: title}
+}
+
+// KindDescription is a NodeKind of the Description node.
+var KindDescription = gast.NewNodeKind("Description")
+
+// Description is a block node containing the description blocks. Its lines
+// hold the raw markdown source of the section.
+type Description struct {
+	gast.BaseBlock
+}
+
+// Kind implements Node.Kind.
+func (n *Description) Kind() gast.NodeKind {
+	return KindDescription
+}
+
+// Dump implements Node.Dump.
+func (n *Description) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// NewDescription returns a new Description node.
+func NewDescription() *Description {
+	return &Description{}
+}
+
+// KindTags is a NodeKind of the Tags node.
+var KindTags = gast.NewNodeKind("Tags")
+
+// Tags is a block node holding the tags parsed from a paragraph written
+// completely in italics.
+type Tags struct {
+	gast.BaseBlock
+	Tags []string
+}
+
+// Kind implements Node.Kind.
+func (n *Tags) Kind() gast.NodeKind {
+	return KindTags
+}
+
+// Dump implements Node.Dump.
+func (n *Tags) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{"Tags": strings.Join(n.Tags, ", ")}, nil)
+}
+
+// NewTags returns a new Tags node.
+func NewTags(tags []string) *Tags {
+	return &Tags{Tags: tags}
+}
+
+// KindYields is a NodeKind of the Yields node.
+var KindYields = gast.NewNodeKind("Yields")
+
+// Yields is a block node holding the yields parsed from a paragraph written
+// completely in bold.
+type Yields struct {
+	gast.BaseBlock
+	Yields []amount.Amount
+}
+
+// Kind implements Node.Kind.
+func (n *Yields) Kind() gast.NodeKind {
+	return KindYields
+}
+
+// Dump implements Node.Dump.
+func (n *Yields) Dump(source []byte, level int) {
+	yields := make([]string, len(n.Yields))
+	for i, y := range n.Yields {
+		yields[i] = y.String()
+	}
+	gast.DumpHelper(n, source, level, map[string]string{"Yields": strings.Join(yields, ", ")}, nil)
+}
+
+// NewYields returns a new Yields node.
+func NewYields(yields []amount.Amount) *Yields {
+	return &Yields{Yields: yields}
+}
+
+// KindIngredients is a NodeKind of the Ingredients node.
+var KindIngredients = gast.NewNodeKind("Ingredients")
+
+// Ingredients is a block node containing the ingredient section between the
+// first and second divider.
+type Ingredients struct {
+	gast.BaseBlock
+}
+
+// Kind implements Node.Kind.
+func (n *Ingredients) Kind() gast.NodeKind {
+	return KindIngredients
+}
+
+// Dump implements Node.Dump.
+func (n *Ingredients) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// NewIngredients returns a new Ingredients node.
+func NewIngredients() *Ingredients {
+	return &Ingredients{}
+}
+
+// KindIngredientGroup is a NodeKind of the IngredientGroup node.
+var KindIngredientGroup = gast.NewNodeKind("IngredientGroup")
+
+// IngredientGroup is a block node started by a heading inside the
+// ingredient section. Its first child is that heading; groups introduced by
+// deeper headings are nested inside it.
+type IngredientGroup struct {
+	gast.BaseBlock
+	Title string
+	Level int
+}
+
+// Kind implements Node.Kind.
+func (n *IngredientGroup) Kind() gast.NodeKind {
+	return KindIngredientGroup
+}
+
+// Dump implements Node.Dump.
+func (n *IngredientGroup) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{
+		"Title": n.Title,
+		"Level": fmt.Sprint(n.Level),
+	}, nil)
+}
+
+// NewIngredientGroup returns a new IngredientGroup node.
+func NewIngredientGroup(title string, level int) *IngredientGroup {
+	return &IngredientGroup{Title: title, Level: level}
+}
+
+// KindIngredient is a NodeKind of the Ingredient node.
+var KindIngredient = gast.NewNodeKind("Ingredient")
+
+// Ingredient is a block node replacing a list item of an ingredient list.
+// It keeps the children of the list item; a leading amount is wrapped in an
+// Amount node. Pinned is set for amounts marked with a leading "=", which
+// are not changed by scaling.
+type Ingredient struct {
+	gast.BaseBlock
+	Amount *amount.Amount
+	Name   string
+	Link   string
+	Pinned bool
+}
+
+// Kind implements Node.Kind.
+func (n *Ingredient) Kind() gast.NodeKind {
+	return KindIngredient
+}
+
+// Dump implements Node.Dump.
+func (n *Ingredient) Dump(source []byte, level int) {
+	kv := map[string]string{"Name": n.Name}
+	if n.Amount != nil {
+		kv["Amount"] = n.Amount.String()
+	}
+	if n.Link != "" {
+		kv["Link"] = n.Link
+	}
+	if n.Pinned {
+		kv["Pinned"] = "true"
+	}
+	gast.DumpHelper(n, source, level, kv, nil)
+}
+
+// NewIngredient returns a new Ingredient node.
+func NewIngredient() *Ingredient {
+	return &Ingredient{}
+}
+
+// KindAmount is a NodeKind of the Amount node.
+var KindAmount = gast.NewNodeKind("Amount")
+
+// Amount is an inline node holding the content of an ingredient's
+// emphasized amount.
+type Amount struct {
+	gast.BaseInline
+	Amount amount.Amount
+}
+
+// Kind implements Node.Kind.
+func (n *Amount) Kind() gast.NodeKind {
+	return KindAmount
+}
+
+// Dump implements Node.Dump.
+func (n *Amount) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{"Amount": n.Amount.String()}, nil)
+}
+
+// NewAmount returns a new Amount node.
+func NewAmount(a amount.Amount) *Amount {
+	return &Amount{Amount: a}
+}
+
+// KindInstructions is a NodeKind of the Instructions node.
+var KindInstructions = gast.NewNodeKind("Instructions")
+
+// Instructions is a block node containing every block after the second
+// divider. Its lines hold the raw markdown source of the section.
+type Instructions struct {
+	gast.BaseBlock
+}
+
+// Kind implements Node.Kind.
+func (n *Instructions) Kind() gast.NodeKind {
+	return KindInstructions
+}
+
+// Dump implements Node.Dump.
+func (n *Instructions) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// NewInstructions returns a new Instructions node.
+func NewInstructions() *Instructions {
+	return &Instructions{}
+}
+
+// Divider is a thematic break that keeps its source line in its lines. It
+// has the kind of goldmark's ThematicBreak and renders like it.
+type Divider struct {
+	gast.ThematicBreak
+}
+
+// IsRaw implements Node.IsRaw. The line of a divider is not inline content.
+func (n *Divider) IsRaw() bool {
+	return true
+}
+
+// NewDivider returns a new Divider node.
+func NewDivider() *Divider {
+	return &Divider{}
+}
@@ -0,0 +1,497 @@
+// Package collection indexes a tree of RecipeMD files.
+//
+// A Collection is loaded from an fs.FS and can be refreshed to pick up
+// added, changed and removed files; only files whose modification time or
+// size changed are parsed again.
+//
+// Files that are symbolic links to other recipe files, hard links and
+// other byte-for-byte copies are aliases: each recipe is indexed once,
+// under the path of one of its files, and Aliases reports the others.
+//
+// Each refresh that finds changes publishes a new immutable Snapshot of
+// the index, so readers never wait for a refresh and never see it half
+// applied. Code that makes several queries which must agree with each
+// other, such as a request handler, takes one Snapshot and queries it.
+package collection
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// Recipe is a recipe of a collection together with the file it was parsed
+// from.
+type Recipe struct {
+	*recipemd.Recipe
+
+	Path    string // slash-separated path in the file system
+	Slug    string // Path without the extension
+	Source  []byte
+	ModTime time.Time
+	Size    int64
+
+	// Report tells how completely the file was parsed. Files with errors
+	// are indexed as long as they have a title; Report.Complete is false
+	// for them.
+	Report *recipemd.Completeness
+
+	fingerprint string
+	hash        string // of Source
+	symlink     bool
+}
+
+// Fingerprint returns the fingerprint of the recipe, computed once when
+// the file is parsed.
+func (r *Recipe) Fingerprint() string {
+	return r.fingerprint
+}
+
+// Collection is an in-memory index of the recipes in a file system. It is
+// safe for concurrent use. Its query methods use the current Snapshot.
+type Collection struct {
+	fsys    fs.FS
+	snap    atomic.Pointer[Snapshot]
+	refresh sync.Mutex // serializes refreshes
+}
+
+// Snapshot is the index of a collection as of one refresh. It is never
+// modified, so it is safe for concurrent use and all its queries agree
+// with each other.
+type Snapshot struct {
+	generation uint64
+	stats      map[string]stat    // of all markdown files, by path
+	files      map[string]*Recipe // by path
+	errs       map[string]error   // files that could not be indexed, by path
+	sorted     []*Recipe          // by title
+	bySlug     map[string]*Recipe
+	byTag      map[string][]*Recipe
+	byIngr     map[string][]*Recipe
+	linked     map[string][]*Recipe // recipes linking to a path
+	byPrint    map[string][]*Recipe // by fingerprint
+	aliases    map[string]string    // canonical paths, by alias path
+	aliasSlugs map[string]*Recipe   // by alias slug
+	tags       []string
+}
+
+// stat is the file data used to detect changes.
+type stat struct {
+	modTime time.Time
+	size    int64
+}
+
+// Load walks fsys, parses every markdown file and indexes the recipes.
+// Files with errors are indexed with what could be extracted from them,
+// see Incomplete; only files without a title are left out and reported by
+// Errors. Hidden directories are skipped.
+func Load(fsys fs.FS) (*Collection, error) {
+	c := &Collection{fsys: fsys}
+	if _, err := c.Refresh(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FS returns the file system the collection was loaded from.
+func (c *Collection) FS() fs.FS {
+	return c.fsys
+}
+
+// Snapshot returns the current index. It stays valid, and unchanged,
+// while later refreshes publish new snapshots.
+func (c *Collection) Snapshot() *Snapshot {
+	return c.snap.Load()
+}
+
+// Generation returns the generation of the current snapshot, which starts
+// at 1 when the collection is loaded and grows by one with every refresh
+// that finds changes.
+func (c *Collection) Generation() uint64 {
+	return c.Snapshot().Generation()
+}
+
+// Refresh brings the index up to date with the file system, parsing only
+// files that are new or changed since the last refresh. It returns the
+// paths of the markdown files that were added, changed or removed. The
+// changes are published as a new Snapshot; readers keep using the
+// previous one until the refresh is complete. If Refresh fails, the
+// current snapshot stays in place.
+func (c *Collection) Refresh() ([]string, error) {
+	c.refresh.Lock()
+	defer c.refresh.Unlock()
+
+	old := c.snap.Load()
+	if old == nil {
+		old = &Snapshot{}
+	}
+	stats := maps.Clone(old.stats)
+	files := maps.Clone(old.files)
+	errs := maps.Clone(old.errs)
+	if stats == nil {
+		stats = make(map[string]stat)
+		files = make(map[string]*Recipe)
+		errs = make(map[string]error)
+	}
+
+	seen := make(map[string]bool)
+	var changed []string
+	err := fs.WalkDir(c.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(path.Ext(p), ".md") {
+			return nil
+		}
+		seen[p] = true
+		symlink := d.Type()&fs.ModeSymlink != 0
+		var info fs.FileInfo
+		if symlink {
+			// the target, whose changes are the ones that matter
+			info, err = fs.Stat(c.fsys, p)
+		} else {
+			info, err = d.Info()
+		}
+		var st stat
+		if err == nil {
+			st = stat{info.ModTime(), info.Size()}
+		} else if !symlink {
+			return err
+		}
+		if old, ok := stats[p]; ok && old.modTime.Equal(st.modTime) && old.size == st.size {
+			return nil
+		}
+		changed = append(changed, p)
+		stats[p] = st
+		delete(files, p)
+		delete(errs, p)
+		if err != nil {
+			// a broken link, reported with the file until it changes
+			errs[p] = err
+			return nil
+		}
+		source, err := fs.ReadFile(c.fsys, p)
+		if err != nil {
+			return err
+		}
+		parsed, report := recipemd.ParsePartial(source)
+		if !report.Usable() {
+			errs[p] = report.Err()
+			return nil
+		}
+		files[p] = &Recipe{
+			Recipe:      parsed,
+			Path:        p,
+			Slug:        strings.TrimSuffix(p, path.Ext(p)),
+			Source:      source,
+			ModTime:     info.ModTime(),
+			Size:        info.Size(),
+			Report:      report,
+			fingerprint: parsed.Fingerprint(),
+			hash:        contentHash(source),
+			symlink:     symlink,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for p := range stats {
+		if !seen[p] {
+			delete(stats, p)
+			delete(files, p)
+			delete(errs, p)
+			changed = append(changed, p)
+		}
+	}
+	if len(changed) == 0 && old.bySlug != nil {
+		return nil, nil
+	}
+	slices.Sort(changed)
+
+	next := &Snapshot{generation: old.generation + 1, stats: stats, files: files, errs: errs}
+	next.index()
+	c.snap.Store(next)
+	return changed, nil
+}
+
+func contentHash(source []byte) string {
+	sum := sha256.Sum256(source)
+	return hex.EncodeToString(sum[:])
+}
+
+// index builds the lookup tables from s.files.
+func (s *Snapshot) index() {
+	s.sorted = make([]*Recipe, 0, len(s.files))
+	s.bySlug = make(map[string]*Recipe, len(s.files))
+	s.byTag = make(map[string][]*Recipe)
+	s.byIngr = make(map[string][]*Recipe)
+	s.linked = make(map[string][]*Recipe)
+	s.byPrint = make(map[string][]*Recipe)
+	s.aliases = make(map[string]string)
+	s.aliasSlugs = make(map[string]*Recipe)
+	byHash := make(map[string][]*Recipe)
+	for _, r := range s.files {
+		byHash[r.hash] = append(byHash[r.hash], r)
+	}
+	for _, same := range byHash {
+		// the canonical file is a regular file if there is one, else the
+		// first by path
+		slices.SortFunc(same, func(a, b *Recipe) int {
+			if a.symlink != b.symlink {
+				if a.symlink {
+					return 1
+				}
+				return -1
+			}
+			return strings.Compare(a.Path, b.Path)
+		})
+		s.sorted = append(s.sorted, same[0])
+		for _, alias := range same[1:] {
+			s.aliases[alias.Path] = same[0].Path
+			s.aliasSlugs[alias.Slug] = same[0]
+		}
+	}
+	slices.SortFunc(s.sorted, func(a, b *Recipe) int {
+		if n := strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	s.tags = nil
+	for _, r := range s.sorted {
+		s.bySlug[r.Slug] = r
+		s.byPrint[r.fingerprint] = append(s.byPrint[r.fingerprint], r)
+		for _, t := range r.Tags {
+			key := strings.ToLower(t)
+			if _, ok := s.byTag[key]; !ok {
+				s.tags = append(s.tags, t)
+			}
+			if !slices.Contains(s.byTag[key], r) {
+				s.byTag[key] = append(s.byTag[key], r)
+			}
+		}
+		for _, i := range r.AllIngredients() {
+			key := ingredientKey(i.Name)
+			if !slices.Contains(s.byIngr[key], r) {
+				s.byIngr[key] = append(s.byIngr[key], r)
+			}
+			if target, ok := linkTarget(r.Path, i.Link); ok && !slices.Contains(s.linked[target], r) {
+				s.linked[target] = append(s.linked[target], r)
+			}
+		}
+	}
+	slices.SortFunc(s.tags, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+}
+
+// linkTarget resolves a link in the file at p to a path in the file
+// system, as recipemd.ResolveLink does but without looking for the file:
+// links starting with a slash are relative to the root and links without
+// an extension point to a .md file. Links with a scheme are not resolved.
+func linkTarget(p, link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return "", false
+	}
+	var target string
+	if strings.HasPrefix(u.Path, "/") {
+		target = path.Clean(u.Path[1:])
+	} else {
+		target = path.Join(path.Dir(p), u.Path)
+	}
+	if path.Ext(target) == "" {
+		target += ".md"
+	}
+	return target, fs.ValidPath(target)
+}
+
+func ingredientKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Generation returns the generation of s. See Collection.Generation.
+func (s *Snapshot) Generation() uint64 {
+	return s.generation
+}
+
+// Recipes returns all recipes, sorted by title.
+func (s *Snapshot) Recipes() []*Recipe {
+	return slices.Clone(s.sorted)
+}
+
+// Get returns the recipe with the slug, its path without the extension.
+// The slug of an alias returns the recipe it is an alias of, whose Path
+// differs from the alias.
+func (s *Snapshot) Get(slug string) (*Recipe, bool) {
+	if r, ok := s.bySlug[slug]; ok {
+		return r, true
+	}
+	r, ok := s.aliasSlugs[slug]
+	return r, ok
+}
+
+// Tagged returns the recipes with the tag, ignoring case, sorted by title.
+func (s *Snapshot) Tagged(tag string) []*Recipe {
+	return slices.Clone(s.byTag[strings.ToLower(tag)])
+}
+
+// WithIngredient returns the recipes with an ingredient of the name,
+// ignoring case and differences in white space, sorted by title.
+func (s *Snapshot) WithIngredient(name string) []*Recipe {
+	return slices.Clone(s.byIngr[ingredientKey(name)])
+}
+
+// Dependents returns the recipes with an ingredient linking to one of the
+// paths, sorted by title. Recipes among paths themselves are included only
+// if they link to another of the paths.
+func (s *Snapshot) Dependents(paths ...string) []*Recipe {
+	var deps []*Recipe
+	for _, r := range s.sorted {
+		for _, p := range paths {
+			if p != r.Path && slices.Contains(s.linked[p], r) {
+				deps = append(deps, r)
+				break
+			}
+		}
+	}
+	return deps
+}
+
+// Duplicates returns the groups of recipes with the same fingerprint,
+// each sorted by title and the groups by the title of their first recipe.
+// Aliases are not duplicates: they are indexed once.
+func (s *Snapshot) Duplicates() [][]*Recipe {
+	var dups [][]*Recipe
+	for _, r := range s.sorted {
+		if g := s.byPrint[r.fingerprint]; len(g) > 1 && g[0] == r {
+			dups = append(dups, slices.Clone(g))
+		}
+	}
+	return dups
+}
+
+// Graph returns the graph of the recipes of the current snapshot. See
+// Snapshot.Graph.
+func (c *Collection) Graph() *Graph {
+	return c.Snapshot().Graph()
+}
+
+// WriteJSONLines writes the recipes of the current snapshot to w, one per
+// line. See Snapshot.WriteJSONLines.
+func (c *Collection) WriteJSONLines(w io.Writer) error {
+	return c.Snapshot().WriteJSONLines(w)
+}
+
+// Aliases returns the paths of the recipes indexed under other paths,
+// mapped to those paths: symbolic links, hard links and other copies of
+// the file.
+func (s *Snapshot) Aliases() map[string]string {
+	return maps.Clone(s.aliases)
+}
+
+// Tags returns the tags of all recipes without duplicates, sorted.
+func (s *Snapshot) Tags() []string {
+	return slices.Clone(s.tags)
+}
+
+// Errors returns the errors of the files that could not be indexed, by
+// path.
+func (s *Snapshot) Errors() map[string]error {
+	return maps.Clone(s.errs)
+}
+
+// Incomplete returns the recipes indexed despite errors, sorted by title.
+// Their Report tells which sections are affected.
+func (s *Snapshot) Incomplete() []*Recipe {
+	var incomplete []*Recipe
+	for _, r := range s.sorted {
+		if !r.Report.Complete() {
+			incomplete = append(incomplete, r)
+		}
+	}
+	return incomplete
+}
+
+// Recipes returns all recipes of the current snapshot, sorted by title.
+func (c *Collection) Recipes() []*Recipe {
+	return c.Snapshot().Recipes()
+}
+
+// Get returns the recipe of the current snapshot with the slug.
+func (c *Collection) Get(slug string) (*Recipe, bool) {
+	return c.Snapshot().Get(slug)
+}
+
+// Tagged returns the recipes of the current snapshot with the tag.
+func (c *Collection) Tagged(tag string) []*Recipe {
+	return c.Snapshot().Tagged(tag)
+}
+
+// WithIngredient returns the recipes of the current snapshot with an
+// ingredient of the name.
+func (c *Collection) WithIngredient(name string) []*Recipe {
+	return c.Snapshot().WithIngredient(name)
+}
+
+// Dependents returns the recipes of the current snapshot with an
+// ingredient linking to one of the paths.
+func (c *Collection) Dependents(paths ...string) []*Recipe {
+	return c.Snapshot().Dependents(paths...)
+}
+
+// Duplicates returns the groups of recipes of the current snapshot with
+// the same fingerprint.
+func (c *Collection) Duplicates() [][]*Recipe {
+	return c.Snapshot().Duplicates()
+}
+
+// Similar returns the n recipes of the current snapshot most similar to
+// r.
+func (c *Collection) Similar(r *recipemd.Recipe, n int) []Match {
+	return c.Snapshot().Similar(r, n)
+}
+
+// NearDuplicates returns the groups of similar recipes of the current
+// snapshot.
+func (c *Collection) NearDuplicates(threshold float64) [][]Match {
+	return c.Snapshot().NearDuplicates(threshold)
+}
+
+// Aliases returns the aliases of the current snapshot.
+func (c *Collection) Aliases() map[string]string {
+	return c.Snapshot().Aliases()
+}
+
+// Tags returns the tags of the current snapshot.
+func (c *Collection) Tags() []string {
+	return c.Snapshot().Tags()
+}
+
+// Errors returns the errors of the current snapshot, by path.
+func (c *Collection) Errors() map[string]error {
+	return c.Snapshot().Errors()
+}
+
+// Incomplete returns the recipes of the current snapshot indexed despite
+// errors.
+func (c *Collection) Incomplete() []*Recipe {
+	return c.Snapshot().Incomplete()
+}
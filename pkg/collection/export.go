@@ -0,0 +1,22 @@
+package collection
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// WriteJSONLines writes the recipes of s to w in JSON Lines format, one
+// recipe per line in the recipe JSON format, sorted by title. Recipes are
+// encoded one at a time as they are written, so the output can be
+// consumed while it is produced.
+func (s *Snapshot) WriteJSONLines(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	enc := json.NewEncoder(b)
+	for _, r := range s.sorted {
+		if err := enc.Encode(r.Recipe); err != nil {
+			return err
+		}
+	}
+	return b.Flush()
+}
@@ -0,0 +1,242 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ErrCycle is returned by Graph.Topological for graphs in which recipes
+// use each other.
+var ErrCycle = errors.New("collection: recipes link to each other in a cycle")
+
+// Graph is the graph of the recipes of a snapshot connected by ingredient
+// links: a recipe has an edge to every recipe one of its ingredients
+// links to, its sub-recipes, such as a pizza to its dough. Links to
+// aliases lead to the recipe they are aliases of; links to files that
+// are not recipes of the collection are left out.
+//
+// Recipes should form a directed acyclic graph. Cycles reports where they
+// do not.
+type Graph struct {
+	recipes []*Recipe // sorted by title
+	index   map[*Recipe]int
+	uses    [][]int // by index, sorted
+	usedBy  [][]int
+}
+
+// Graph returns the graph of the recipes of s.
+func (s *Snapshot) Graph() *Graph {
+	g := &Graph{
+		recipes: s.sorted,
+		index:   make(map[*Recipe]int, len(s.sorted)),
+		uses:    make([][]int, len(s.sorted)),
+		usedBy:  make([][]int, len(s.sorted)),
+	}
+	for i, r := range s.sorted {
+		g.index[r] = i
+	}
+	for i, r := range s.sorted {
+		for _, in := range r.AllIngredients() {
+			target, ok := linkTarget(r.Path, in.Link)
+			if !ok {
+				continue
+			}
+			if canonical, ok := s.aliases[target]; ok {
+				target = canonical
+			}
+			j, ok := g.index[s.files[target]]
+			if !ok {
+				continue
+			}
+			if !slices.Contains(g.uses[i], j) {
+				g.uses[i] = append(g.uses[i], j)
+				g.usedBy[j] = append(g.usedBy[j], i)
+			}
+		}
+		slices.Sort(g.uses[i])
+	}
+	for j := range g.usedBy {
+		slices.Sort(g.usedBy[j])
+	}
+	return g
+}
+
+// Recipes returns the recipes of g, sorted by title.
+func (g *Graph) Recipes() []*Recipe {
+	return slices.Clone(g.recipes)
+}
+
+// Uses returns the recipes that ingredients of r link to, sorted by
+// title.
+func (g *Graph) Uses(r *Recipe) []*Recipe {
+	i, ok := g.index[r]
+	if !ok {
+		return nil
+	}
+	return g.list(g.uses[i])
+}
+
+// UsedBy returns the recipes with ingredients linking to r, sorted by
+// title.
+func (g *Graph) UsedBy(r *Recipe) []*Recipe {
+	i, ok := g.index[r]
+	if !ok {
+		return nil
+	}
+	return g.list(g.usedBy[i])
+}
+
+func (g *Graph) list(indices []int) []*Recipe {
+	var list []*Recipe
+	for _, i := range indices {
+		list = append(list, g.recipes[i])
+	}
+	return list
+}
+
+// Cycles returns the groups of recipes that use each other, directly or
+// through others, including recipes that link to themselves. Each group
+// is sorted by title and the groups by the title of their first recipe.
+func (g *Graph) Cycles() [][]*Recipe {
+	var cycles [][]*Recipe
+	for _, c := range g.components() {
+		if len(c) > 1 || slices.Contains(g.uses[c[0]], c[0]) {
+			slices.Sort(c)
+			cycles = append(cycles, g.list(c))
+		}
+	}
+	slices.SortFunc(cycles, func(a, b []*Recipe) int {
+		return g.index[a[0]] - g.index[b[0]]
+	})
+	return cycles
+}
+
+// components returns the strongly connected components of g by Tarjan's
+// algorithm.
+func (g *Graph) components() [][]int {
+	n := len(g.recipes)
+	order := make([]int, n) // 1 + visiting order, 0 if not visited
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	var stack []int
+	var components [][]int
+	next := 1
+	var visit func(int)
+	visit = func(v int) {
+		order[v], low[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range g.uses[v] {
+			switch {
+			case order[w] == 0:
+				visit(w)
+				low[v] = min(low[v], low[w])
+			case onStack[w]:
+				low[v] = min(low[v], order[w])
+			}
+		}
+		if low[v] != order[v] {
+			return
+		}
+		var c []int
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			c = append(c, w)
+			if w == v {
+				break
+			}
+		}
+		components = append(components, c)
+	}
+	for v := range n {
+		if order[v] == 0 {
+			visit(v)
+		}
+	}
+	return components
+}
+
+// Topological returns the recipes of g in an order in which every recipe
+// comes after the recipes it uses, so sub-recipes can be built, checked
+// or cooked first. Of the recipes whose turn it is, the one first by
+// title comes first. If recipes use each other, there is no such order
+// and the error wraps ErrCycle and names the recipes of the first cycle.
+func (g *Graph) Topological() ([]*Recipe, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		var titles []string
+		for _, r := range cycles[0] {
+			titles = append(titles, r.Title)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrCycle, strings.Join(titles, ", "))
+	}
+	pending := make([]int, len(g.recipes)) // uses not yet in the order
+	for i, uses := range g.uses {
+		pending[i] = len(uses)
+	}
+	order := make([]*Recipe, 0, len(g.recipes))
+	done := make([]bool, len(g.recipes))
+	for len(order) < len(g.recipes) {
+		for i, r := range g.recipes {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+			done[i] = true
+			order = append(order, r)
+			for _, u := range g.usedBy[i] {
+				pending[u]--
+			}
+			break
+		}
+	}
+	return order, nil
+}
+
+// WriteDOT writes g to w in the DOT language of Graphviz, with an edge
+// from every recipe to each recipe it uses, labeled with titles and
+// identified by slugs. Edges within cycles are red. Recipes without links
+// are left out unless all is true.
+func (g *Graph) WriteDOT(w io.Writer, all bool) error {
+	inCycle := make([]bool, len(g.recipes))
+	for _, c := range g.Cycles() {
+		for _, r := range c {
+			inCycle[g.index[r]] = true
+		}
+	}
+	component := make([]int, len(g.recipes))
+	for i, c := range g.components() {
+		for _, v := range c {
+			component[v] = i
+		}
+	}
+	var b strings.Builder
+	b.WriteString("digraph recipes {\n\tnode [shape=box];\n")
+	for i, r := range g.recipes {
+		if !all && len(g.uses[i]) == 0 && len(g.usedBy[i]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", dotID(r.Slug), dotID(r.Title))
+	}
+	for i, r := range g.recipes {
+		for _, j := range g.uses[i] {
+			fmt.Fprintf(&b, "\t%s -> %s", dotID(r.Slug), dotID(g.recipes[j].Slug))
+			if inCycle[i] && component[i] == component[j] {
+				b.WriteString(" [color=red]")
+			}
+			b.WriteString(";\n")
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotID quotes s as a DOT identifier.
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
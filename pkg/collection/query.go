@@ -0,0 +1,109 @@
+package collection
+
+import (
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/filter"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// Query is a boolean query over the recipes of a collection in the filter
+// language of package filter, such as
+//
+//	("dessert" or "snack") and not "deep-fry"
+//
+// Snapshot.Query evaluates tag terms with the tag index and the other
+// terms by matching the recipes. A Query is immutable and safe for
+// concurrent use.
+type Query struct {
+	expr filter.Expr
+}
+
+// ParseQuery parses a filter expression. The error is a
+// *filter.SyntaxError if the expression is malformed.
+func ParseQuery(s string) (*Query, error) {
+	expr, err := filter.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{expr}, nil
+}
+
+// NewQuery returns a Query for a parsed or built filter expression.
+func NewQuery(expr filter.Expr) *Query {
+	return &Query{expr}
+}
+
+// Expr returns the filter expression of q.
+func (q *Query) Expr() filter.Expr {
+	return q.expr
+}
+
+// String returns q in filter language syntax.
+func (q *Query) String() string {
+	return q.expr.String()
+}
+
+// Match reports whether r satisfies q, for recipes that are not in a
+// collection.
+func (q *Query) Match(r *recipemd.Recipe) bool {
+	return q.expr.Match(r)
+}
+
+// Query returns the recipes satisfying q, sorted by title.
+func (s *Snapshot) Query(q *Query) []*Recipe {
+	pos := make(map[*Recipe]int, len(s.sorted))
+	for i, r := range s.sorted {
+		pos[r] = i
+	}
+	set := s.eval(q.expr, pos)
+	var matched []*Recipe
+	for i, r := range s.sorted {
+		if set[i] {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// eval returns the set of the recipes satisfying expr, indexed by their
+// position in s.sorted, which pos maps them to.
+func (s *Snapshot) eval(expr filter.Expr, pos map[*Recipe]int) []bool {
+	set := make([]bool, len(s.sorted))
+	switch e := expr.(type) {
+	case filter.Term:
+		if e.Field == filter.FieldTag {
+			for _, r := range s.byTag[strings.ToLower(e.Value)] {
+				set[pos[r]] = true
+			}
+			return set
+		}
+	case filter.And:
+		left, right := s.eval(e.Left, pos), s.eval(e.Right, pos)
+		for i := range set {
+			set[i] = left[i] && right[i]
+		}
+		return set
+	case filter.Or:
+		left, right := s.eval(e.Left, pos), s.eval(e.Right, pos)
+		for i := range set {
+			set[i] = left[i] || right[i]
+		}
+		return set
+	case filter.Not:
+		operand := s.eval(e.Expr, pos)
+		for i := range set {
+			set[i] = !operand[i]
+		}
+		return set
+	}
+	for i, r := range s.sorted {
+		set[i] = expr.Match(r.Recipe)
+	}
+	return set
+}
+
+// Query returns the recipes of the current snapshot satisfying q.
+func (c *Collection) Query(q *Query) []*Recipe {
+	return c.Snapshot().Query(q)
+}
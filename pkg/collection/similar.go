@@ -0,0 +1,137 @@
+package collection
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/slug"
+)
+
+// Match is a recipe found by Similar or NearDuplicates and its
+// similarity, see Similarity.
+type Match struct {
+	*Recipe
+	Score float64
+}
+
+// titleStopWords are words left out of the comparison of titles.
+var titleStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "with": true,
+}
+
+// Similarity returns how alike a and b are, from 0 for nothing in common
+// to 1: the weighted Jaccard similarity of their sets of ingredient names
+// (three fifths) and title words (two fifths). Ingredient names are
+// compared ignoring case, white space and a plural "s"; recipes without
+// ingredients are compared by title only.
+func Similarity(a, b *recipemd.Recipe) float64 {
+	title := jaccard(titleWords(a.Title), titleWords(b.Title))
+	ia, ib := ingredientSet(a), ingredientSet(b)
+	if len(ia) == 0 && len(ib) == 0 {
+		return title
+	}
+	return 0.6*jaccard(ia, ib) + 0.4*title
+}
+
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Split(slug.Make(title), "-") {
+		if !titleStopWords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+func ingredientSet(r *recipemd.Recipe) map[string]bool {
+	names := make(map[string]bool)
+	for _, in := range r.AllIngredients() {
+		key := ingredientKey(in.Name)
+		if len(key) > 3 {
+			key = strings.TrimSuffix(key, "s")
+		}
+		names[key] = true
+	}
+	return names
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	both := 0
+	for k := range a {
+		if b[k] {
+			both++
+		}
+	}
+	return float64(both) / float64(len(a)+len(b)-both)
+}
+
+// Similar returns the n recipes most similar to r, most similar first and
+// ties sorted by title, leaving out r itself and recipes with nothing in
+// common with it. r need not be in the collection.
+func (s *Snapshot) Similar(r *recipemd.Recipe, n int) []Match {
+	var matches []Match
+	for _, e := range s.sorted {
+		if e.Recipe == r {
+			continue
+		}
+		if score := Similarity(r, e.Recipe); score > 0 {
+			matches = append(matches, Match{Recipe: e, Score: score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return matches[:min(n, len(matches))]
+}
+
+// NearDuplicates returns the groups of recipes that are each at least threshold
+// similar to another recipe of their group, such as slightly different
+// copies of one recipe. Every group starts with its recipe that comes
+// first by title, with a Score of 1; the Score of the others is their
+// similarity to it, which can be below threshold for a recipe that joined
+// through a third. Groups are sorted by the title of their first recipe.
+func (s *Snapshot) NearDuplicates(threshold float64) [][]Match {
+	group := make([]int, len(s.sorted)) // union-find over s.sorted
+	for i := range group {
+		group[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if group[i] != i {
+			group[i] = find(group[i])
+		}
+		return group[i]
+	}
+	for i, a := range s.sorted {
+		for j := i + 1; j < len(s.sorted); j++ {
+			if Similarity(a.Recipe, s.sorted[j].Recipe) >= threshold {
+				// the root stays the recipe first by title
+				ri, rj := find(i), find(j)
+				group[max(ri, rj)] = min(ri, rj)
+			}
+		}
+	}
+	members := make(map[int][]int)
+	for i := range s.sorted {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+	var groups [][]Match
+	for i, first := range s.sorted {
+		m := members[i]
+		if len(m) < 2 || m[0] != i {
+			continue
+		}
+		g := []Match{{Recipe: first, Score: 1}}
+		for _, j := range m[1:] {
+			g = append(g, Match{Recipe: s.sorted[j], Score: Similarity(first.Recipe, s.sorted[j].Recipe)})
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
@@ -0,0 +1,30 @@
+package collection
+
+import (
+	"context"
+	"time"
+)
+
+// Watch refreshes c every interval until ctx is done and calls fn with the
+// changed paths, as returned by Refresh, whenever there are any. Watching
+// polls the file system, so it works for any fs.FS; files are only parsed
+// again when their modification time or size changes. Watch returns the
+// first error of Refresh or ctx.Err.
+func (c *Collection) Watch(ctx context.Context, interval time.Duration, fn func(changed []string)) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+		changed, err := c.Refresh()
+		if err != nil {
+			return err
+		}
+		if len(changed) > 0 {
+			fn(changed)
+		}
+	}
+}
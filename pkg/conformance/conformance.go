@@ -0,0 +1,167 @@
+// Package conformance checks RecipeMD parsers against a corpus of recipes
+// and their expected JSON, so forks can verify that their changes keep the
+// behavior of this module:
+//
+//	for _, f := range conformance.Check(conformance.Parse) {
+//		fmt.Println(f)
+//	}
+//
+// The corpus lives in testdata and covers ingredient groups nested three
+// deep, links to other recipes, several yields, pinned and unicode
+// amounts, ranges, package amounts, ingredient notes and preparations.
+// The recipes were written for this repository and share its license. The
+// expected JSON is the output of "recipemd show -format json".
+//
+// Run and CheckFormat use the same corpus to check importers and
+// exporters of other file formats by round trip.
+package conformance
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+//go:embed testdata/*.md testdata/*.json
+var corpus embed.FS
+
+// Case is a recipe of the corpus.
+type Case struct {
+	Name   string // file name without extension
+	Source []byte // the RecipeMD document
+	Want   []byte // the expected JSON
+}
+
+// Cases returns the corpus, ordered by name.
+func Cases() []Case {
+	entries, err := corpus.ReadDir("testdata")
+	if err != nil {
+		panic(err)
+	}
+	var cases []Case
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".md")
+		if !ok {
+			continue
+		}
+		c := Case{Name: name}
+		c.Source, _ = corpus.ReadFile(path.Join("testdata", name+".md"))
+		if c.Want, err = corpus.ReadFile(path.Join("testdata", name+".json")); err != nil {
+			panic(err)
+		}
+		cases = append(cases, c)
+	}
+	return cases
+}
+
+// A Parser turns a RecipeMD document into the JSON of its recipe.
+type Parser func(source []byte) ([]byte, error)
+
+// Parse is the Parser of this module.
+func Parse(source []byte) ([]byte, error) {
+	r, err := recipemd.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(r)
+}
+
+// Failure is a case a parser got wrong.
+type Failure struct {
+	Case string
+	Err  error
+}
+
+func (f Failure) Error() string {
+	return f.Case + ": " + f.Err.Error()
+}
+
+// Check runs parse on every case of the corpus and returns the failures.
+// JSON is compared by value, so its formatting does not matter.
+func Check(parse Parser) []Failure {
+	var failures []Failure
+	for _, c := range Cases() {
+		if err := check(parse, c); err != nil {
+			failures = append(failures, Failure{Case: c.Name, Err: err})
+		}
+	}
+	return failures
+}
+
+func check(parse Parser, c Case) error {
+	out, err := parse(c.Source)
+	if err != nil {
+		return err
+	}
+	var got, want any
+	if err := json.Unmarshal(out, &got); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if err := json.Unmarshal(c.Want, &want); err != nil {
+		return fmt.Errorf("invalid expected JSON: %w", err)
+	}
+	if p, ok := difference("", want, got); !ok {
+		return fmt.Errorf("%s differs: want %s, got %s", p.path, p.want, p.got)
+	}
+	return nil
+}
+
+type mismatch struct {
+	path, want, got string
+}
+
+// difference returns the first place where got differs from want.
+func difference(at string, want, got any) (mismatch, bool) {
+	switch w := want.(type) {
+	case map[string]any:
+		if g, ok := got.(map[string]any); ok {
+			for _, k := range union(w, g) {
+				if m, ok := difference(at+"."+k, w[k], g[k]); !ok {
+					return m, false
+				}
+			}
+			return mismatch{}, true
+		}
+	case []any:
+		if g, ok := got.([]any); ok && len(g) == len(w) {
+			for i := range w {
+				if m, ok := difference(fmt.Sprintf("%s[%d]", at, i), w[i], g[i]); !ok {
+					return m, false
+				}
+			}
+			return mismatch{}, true
+		}
+	default:
+		if reflect.DeepEqual(want, got) {
+			return mismatch{}, true
+		}
+	}
+	if at == "" {
+		at = "."
+	}
+	return mismatch{path: at, want: compact(want), got: compact(got)}, false
+}
+
+// union returns the keys of a and b, sorted.
+func union(a, b map[string]any) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func compact(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
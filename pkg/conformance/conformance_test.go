@@ -0,0 +1,30 @@
+package conformance
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	if len(Cases()) == 0 {
+		t.Fatal("empty corpus")
+	}
+	for _, f := range Check(Parse) {
+		t.Error(f)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("markdown", func(t *testing.T) { Run(t, Markdown) })
+	t.Run("json", func(t *testing.T) { Run(t, JSON) })
+}
+
+func TestCheckFormat(t *testing.T) {
+	for _, f := range CheckFormat(Markdown) {
+		t.Error(f)
+	}
+}
+
+// BenchmarkRun shows that Run accepts a testing.B.
+func BenchmarkRun(b *testing.B) {
+	for b.Loop() {
+		Run(b, JSON)
+	}
+}
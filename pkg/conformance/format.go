@@ -0,0 +1,125 @@
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// An Importer reads recipes from a file format. Like recipemd.ParsePartial
+// it reports how completely it read the recipe; the error is for data it
+// cannot read at all.
+type Importer interface {
+	Import(data []byte) (*recipemd.Recipe, *recipemd.Completeness, error)
+}
+
+// An Exporter writes recipes in a file format.
+type Exporter interface {
+	Export(w io.Writer, r *recipemd.Recipe) error
+}
+
+// A Format imports and exports the same file format.
+type Format interface {
+	Importer
+	Exporter
+}
+
+// The formats of this module, checked by the same corpus as plugins.
+var (
+	Markdown Format = markdownFormat{}
+	JSON     Format = jsonFormat{}
+)
+
+type markdownFormat struct{}
+
+func (markdownFormat) Import(data []byte) (*recipemd.Recipe, *recipemd.Completeness, error) {
+	r, report := recipemd.ParsePartial(data)
+	return r, report, nil
+}
+
+func (markdownFormat) Export(w io.Writer, r *recipemd.Recipe) error {
+	return recipemd.WriteMarkdown(w, r)
+}
+
+type jsonFormat struct{}
+
+func (jsonFormat) Import(data []byte) (*recipemd.Recipe, *recipemd.Completeness, error) {
+	var r recipemd.Recipe
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, nil, err
+	}
+	return &r, &recipemd.Completeness{}, nil
+}
+
+func (jsonFormat) Export(w io.Writer, r *recipemd.Recipe) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// CheckFormat exports the recipe of every case of the corpus with f,
+// imports it again and returns the failures: errors, reports that are
+// missing or not complete, and recipes whose JSON differs from the
+// expected JSON of the case.
+func CheckFormat(f Format) []Failure {
+	var failures []Failure
+	for _, c := range Cases() {
+		if err := roundTrip(f, c); err != nil {
+			failures = append(failures, Failure{Case: c.Name, Err: err})
+		}
+	}
+	return failures
+}
+
+// Run runs the checks of CheckFormat as subtests of tb, one per case, so
+// the tests of a format plugin can prove it round-trips the corpus the
+// way the formats of this module do:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, myformat.Format{})
+//	}
+//
+// Benchmarks and fuzz targets, which cannot run subtests, get an error
+// per failing case instead.
+func Run(tb testing.TB, f Format) {
+	tb.Helper()
+	for _, c := range Cases() {
+		t, ok := tb.(*testing.T)
+		if !ok {
+			if err := roundTrip(f, c); err != nil {
+				tb.Error(Failure{Case: c.Name, Err: err})
+			}
+			continue
+		}
+		t.Run(c.Name, func(t *testing.T) {
+			if err := roundTrip(f, c); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func roundTrip(f Format, c Case) error {
+	r, err := recipemd.Parse(c.Source)
+	if err != nil {
+		return fmt.Errorf("corpus: %w", err)
+	}
+	var b bytes.Buffer
+	if err := f.Export(&b, r); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	got, report, err := f.Import(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+	if got == nil || report == nil {
+		return errors.New("import: no recipe or no report")
+	}
+	if !report.Complete() {
+		return fmt.Errorf("import: incomplete: %v", report.Err())
+	}
+	return check(func([]byte) ([]byte, error) { return json.Marshal(got) }, c)
+}
@@ -0,0 +1,62 @@
+// Package diag defines the codes of the problems reported for RecipeMD
+// documents, so callers can tell them apart without matching messages.
+//
+// Codes implement error and the diagnostics of package recipemd unwrap to
+// their code:
+//
+//	if _, err := recipemd.Parse(source); errors.Is(err, diag.ErrMissingTitle) {
+//		// ...
+//	}
+package diag
+
+import "strings"
+
+// Code identifies a kind of problem. Codes of errors start with Err and
+// codes of warnings with Warn.
+type Code string
+
+// Error implements error.
+func (c Code) Error() string {
+	return string(c)
+}
+
+// IsWarning reports whether c is the code of a warning.
+func (c Code) IsWarning() bool {
+	return strings.HasPrefix(string(c), "warn-")
+}
+
+// Codes of violations of the RecipeMD specification.
+const (
+	ErrMissingTitle        Code = "missing-title"         // no first-level heading at the start
+	ErrEmptyTitle          Code = "empty-title"           // the first-level heading is empty
+	ErrDuplicateTags       Code = "duplicate-tags"        // more than one tags paragraph
+	ErrDuplicateYields     Code = "duplicate-yields"      // more than one yields paragraph
+	ErrYieldsBeforeTags    Code = "yields-before-tags"    // tags following the yields
+	ErrMissingDivider      Code = "missing-divider"       // content between yields and the divider
+	ErrEmptyIngredientName Code = "empty-ingredient-name" // an ingredient without a name
+)
+
+// Codes of problems only found when constructing recipes, which parsing
+// cannot produce.
+const (
+	ErrInvalidTag           Code = "invalid-tag"            // an empty tag or one containing a comma
+	ErrEmptyGroupTitle      Code = "empty-group-title"      // an ingredient group without a title
+	ErrIngredientAfterGroup Code = "ingredient-after-group" // an ingredient following a group of its level
+)
+
+// Codes of documents exceeding the limits set by parse options, such as
+// recipemd.WithMaxSize.
+const (
+	ErrTooLarge           Code = "too-large"            // the document is longer than the maximum size
+	ErrTooManyIngredients Code = "too-many-ingredients" // more ingredients than the maximum
+	ErrTooDeep            Code = "too-deep"             // ingredient groups nested deeper than the maximum
+)
+
+// Codes of content that is valid but ignored or likely unintended.
+const (
+	WarnYieldWithoutAmount Code = "warn-yield-without-amount" // a yield that does not start with a number
+	WarnUnparseableAmount  Code = "warn-unparseable-amount"   // an ingredient amount that does not start with a number
+	WarnEmptyGroup         Code = "warn-empty-group"          // an ingredient group without ingredients
+	WarnIgnoredBlock       Code = "warn-ignored-block"        // a block in the ingredients that is not a list or heading
+	WarnDroppedBlock       Code = "warn-dropped-block"        // a block the parser lost, which is a bug
+)
@@ -0,0 +1,33 @@
+package extension
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+)
+
+// dividerParser wraps goldmark's thematic break parser and produces Divider
+// nodes recording the source line of every break so the transformer can
+// locate section boundaries.
+type dividerParser struct {
+	parser.BlockParser
+}
+
+// NewDividerParser returns a BlockParser that parses thematic breaks into
+// Divider nodes.
+func NewDividerParser() parser.BlockParser {
+	return &dividerParser{BlockParser: parser.NewThematicBreakParser()}
+}
+
+func (b *dividerParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	_, segment := reader.PeekLine()
+	node, state := b.BlockParser.Open(parent, reader, pc)
+	if node == nil {
+		return nil, state
+	}
+	divider := ast.NewDivider()
+	divider.Lines().Append(segment.TrimRightSpace(reader.Source()))
+	return divider, state
+}
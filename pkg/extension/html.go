@@ -0,0 +1,764 @@
+package extension
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+	"github.com/xcapaldi/recipemd-go/pkg/slug"
+)
+
+// HTMLRenderer is a renderer.NodeRenderer implementation that renders
+// RecipeMD nodes as HTML annotated with schema.org Recipe microdata.
+//
+// It keeps no state between calls and does not modify the documents it
+// renders: the attributes it needs are set by the transformer of the
+// extension when the document is parsed. A goldmark.Markdown with the
+// extension can therefore convert documents from several goroutines at
+// once, and a parsed document can be rendered more than once, even
+// concurrently.
+type HTMLRenderer struct {
+	html.Config
+	HTMLConfig
+
+	labels Labels // Labels completed by those of Lang
+}
+
+// HTMLConfig configures the HTML an HTMLRenderer writes for recipes.
+type HTMLConfig struct {
+	// Checkboxes writes the name of each ingredient, with its amount, in
+	// a label with a checkbox, and the amount in data attributes of the
+	// ingredient. See WithCheckboxes.
+	Checkboxes bool
+
+	// ClassPrefix is prepended to the classes the renderer writes, such
+	// as "recipe" and "ingredient", unless Classes replaces them.
+	ClassPrefix string
+
+	// Classes maps the classes the renderer writes to the classes to
+	// write instead, which may be several separated by spaces or none.
+	// See WithClasses.
+	Classes map[string]string
+
+	// A11y writes landmarks and labels for assistive technology. See
+	// WithA11y.
+	A11y bool
+
+	// Lang is the language of the recipe as a BCP 47 tag, such as "de",
+	// written as the lang attribute of the recipe if it is not empty. It
+	// also selects the labels of LabelsFor.
+	Lang string
+
+	// Labels replaces the labels of the sections that LabelsFor returns
+	// for Lang. Empty labels are not replaced. See WithLabels.
+	Labels Labels
+
+	// Headings writes a heading with its label at the start of the
+	// ingredients and instructions. See WithHeadings.
+	Headings bool
+
+	// Dir is the text direction of the recipe, "ltr" or "rtl", written as
+	// its dir attribute. If it is empty, the direction is taken from the
+	// title and only "rtl" is written.
+	Dir string
+
+	// Microformats adds the classes of the microformats2 h-recipe
+	// vocabulary. See WithMicroformats.
+	Microformats bool
+}
+
+// class returns the class to write instead of name.
+func (c *HTMLConfig) class(name string) string {
+	if class, ok := c.Classes[name]; ok {
+		return class
+	}
+	return c.ClassPrefix + name
+}
+
+// HTMLOption configures an HTMLRenderer. It is also an html.Option, which
+// goldmark's HTML renderer ignores, so the options of both can be passed
+// to NewHTMLRenderer and WithHTMLOptions together.
+type HTMLOption interface {
+	html.Option
+	SetRecipeHTMLOption(*HTMLConfig)
+}
+
+type htmlOption func(*HTMLConfig)
+
+func (o htmlOption) SetHTMLOption(*html.Config) {}
+
+func (o htmlOption) SetRecipeHTMLOption(c *HTMLConfig) {
+	o(c)
+}
+
+// WithCheckboxes renders ingredients as items to check off: the name of
+// each ingredient, with its amount, becomes a label with a checkbox. The
+// amount is also written in data attributes of the ingredient, so scripts
+// can scale it without parsing the text: data-amount-factor holds the
+// factor as a decimal number, data-amount-max the upper end of a range
+// and data-amount-unit the unit.
+func WithCheckboxes() HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.Checkboxes = true
+	})
+}
+
+// WithClassPrefix prepends prefix to the classes the renderer writes, so
+// "ingredient" becomes "rmd-ingredient" with the prefix "rmd-". Classes
+// replaced with WithClasses are not prefixed.
+func WithClassPrefix(prefix string) HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.ClassPrefix = prefix
+	})
+}
+
+// WithClasses replaces the classes the renderer writes, to fit a naming
+// convention such as BEM or utility classes such as Tailwind's. Each key
+// is a class the renderer writes by default: recipe, description, tags,
+// yields, ingredients, ingredient-group, group-image, ingredient, pinned,
+// optional, ingredient-image, amount, instructions and section-heading.
+// Its value is written instead, as it is; an empty value leaves the class
+// out. The classes of several WithClasses options are merged.
+func WithClasses(classes map[string]string) HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		if c.Classes == nil {
+			c.Classes = make(map[string]string, len(classes))
+		}
+		for k, v := range classes {
+			c.Classes[k] = v
+		}
+	})
+}
+
+// WithA11y makes the HTML easier to use with assistive technology such as
+// screen readers. The recipe is an article, the ingredients, ingredient
+// groups and instructions are sections labeled with aria-label, and so
+// are the lists of tags and yields. Instructions whose steps are
+// paragraphs are written as an ordered list, a list item per step.
+func WithA11y() HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.A11y = true
+	})
+}
+
+// WithLang sets the lang attribute of the recipe to lang, a BCP 47
+// language tag such as "de" or "pt-BR", so screen readers pronounce it in
+// that language even on a page in another one. The labels of the
+// sections are those of LabelsFor(lang).
+func WithLang(lang string) HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.Lang = lang
+	})
+}
+
+// WithLabels replaces the labels of the sections, written as headings
+// with WithHeadings and as aria-label attributes with WithA11y. Empty
+// labels are left as they are.
+func WithLabels(labels Labels) HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.Labels = labels.Or(c.Labels)
+	})
+}
+
+// WithHeadings writes a second-level heading with the label of the
+// section, such as "Ingredients" or, with WithLang("de"), "Zutaten", at
+// the start of the ingredients and of the instructions. The headings
+// have the class section-heading.
+func WithHeadings() HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.Headings = true
+	})
+}
+
+// WithDirection sets the text direction of the recipe, "ltr" or "rtl",
+// instead of taking it from the title. Use it for recipes whose title is
+// in another script than the rest, or for pages that must set it either
+// way.
+func WithDirection(dir string) HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.Dir = dir
+	})
+}
+
+// WithMicroformats adds the classes of the microformats2 h-recipe
+// vocabulary to the schema.org microdata, for IndieWeb tools: h-recipe on
+// the recipe, p-name on the title, p-summary on the description,
+// p-category on the tags, p-yield on the yields, p-ingredient on the
+// ingredients, e-instructions on the instructions and u-photo on the
+// images of the description and instructions. The total time is written
+// as a data element with the class dt-duration. These classes are neither
+// prefixed nor replaced by WithClassPrefix and WithClasses.
+func WithMicroformats() HTMLOption {
+	return htmlOption(func(c *HTMLConfig) {
+		c.Microformats = true
+	})
+}
+
+// NewHTMLRenderer returns a new HTMLRenderer configured by opts, which
+// may be HTMLOptions and goldmark's html options.
+func NewHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &HTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+		if opt, ok := opt.(HTMLOption); ok {
+			opt.SetRecipeHTMLOption(&r.HTMLConfig)
+		}
+	}
+	r.labels = r.HTMLConfig.Labels.Or(LabelsFor(r.Lang))
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *HTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindRecipe, r.renderRecipe)
+	reg.Register(ast.KindTitle, r.renderTitle)
+	reg.Register(ast.KindDescription, r.renderDescription)
+	reg.Register(ast.KindTags, r.renderTags)
+	reg.Register(ast.KindYields, r.renderYields)
+	reg.Register(ast.KindIngredients, r.renderIngredients)
+	reg.Register(ast.KindIngredientGroup, r.renderIngredientGroup)
+	reg.Register(ast.KindIngredient, r.renderIngredient)
+	reg.Register(ast.KindAmount, r.renderAmount)
+	reg.Register(ast.KindInstructions, r.renderInstructions)
+	reg.Register(gast.KindListItem, r.renderListItem)
+	reg.Register(gast.KindParagraph, r.renderParagraph)
+	reg.Register(gast.KindTextBlock, r.renderTextBlock)
+}
+
+func (r *HTMLRenderer) renderRecipe(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	tag := "div"
+	if r.A11y {
+		tag = "article"
+	}
+	if entering {
+		_, _ = w.WriteString("<" + tag)
+		r.writeClasses(w, "h-recipe", "recipe")
+		if r.Lang != "" {
+			_, _ = w.WriteString(` lang="`)
+			_, _ = w.Write(util.EscapeHTML([]byte(r.Lang)))
+			_ = w.WriteByte('"')
+		}
+		dir := r.Dir
+		if dir == "" && recipeDirection(n, source) == "rtl" {
+			dir = "rtl"
+		}
+		if dir != "" {
+			_, _ = w.WriteString(` dir="`)
+			_, _ = w.Write(util.EscapeHTML([]byte(dir)))
+			_ = w.WriteByte('"')
+		}
+		_, _ = w.WriteString(` itemscope itemtype="https://schema.org/Recipe">` + "\n")
+	} else {
+		_, _ = w.WriteString("</" + tag + ">\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *HTMLRenderer) renderTitle(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		title, ok := n.(*ast.Title)
+		if !ok {
+			return gast.WalkStop, unexpectedNode(n)
+		}
+		_, _ = w.WriteString(`<h1`)
+		r.writeClasses(w, "p-name")
+		writeID(w, n, title.Title)
+		_, _ = w.WriteString(` itemprop="name">`)
+	} else {
+		_, _ = w.WriteString("</h1>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+// renderDescription renders the description, preceded by the times given
+// in it as schema.org durations.
+func (r *HTMLRenderer) renderDescription(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		t := ParseTimes(n, source)
+		writeDuration(w, "prepTime", t.Prep)
+		writeDuration(w, "cookTime", t.Cook)
+		writeDuration(w, "totalTime", t.Total)
+		if r.Microformats && t.Total > 0 {
+			_, _ = w.WriteString(`<data class="dt-duration" value="` + ISODuration(t.Total) + `"></data>` + "\n")
+		}
+		_, _ = w.WriteString(`<div`)
+		r.writeClasses(w, "p-summary", "description")
+		_, _ = w.WriteString(` itemprop="description">` + "\n")
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *HTMLRenderer) renderTags(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkSkipChildren, nil
+	}
+	tags, ok := n.(*ast.Tags)
+	if !ok {
+		return gast.WalkStop, unexpectedNode(n)
+	}
+	_, _ = w.WriteString(`<ul`)
+	r.writeClass(w, "tags")
+	r.writeLabel(w, r.labels.Tags)
+	_, _ = w.WriteString(">\n")
+	for _, tag := range tags.Tags {
+		_, _ = w.WriteString(`<li`)
+		r.writeClasses(w, "p-category")
+		_, _ = w.WriteString(` itemprop="keywords">`)
+		_, _ = w.Write(util.EscapeHTML([]byte(tag)))
+		_, _ = w.WriteString("</li>\n")
+	}
+	_, _ = w.WriteString("</ul>\n")
+	return gast.WalkSkipChildren, nil
+}
+
+func (r *HTMLRenderer) renderYields(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkSkipChildren, nil
+	}
+	yields, ok := n.(*ast.Yields)
+	if !ok {
+		return gast.WalkStop, unexpectedNode(n)
+	}
+	_, _ = w.WriteString(`<ul`)
+	r.writeClass(w, "yields")
+	r.writeLabel(w, r.labels.Yields)
+	_, _ = w.WriteString(">\n")
+	for _, y := range yields.Yields {
+		_, _ = w.WriteString(`<li`)
+		r.writeClasses(w, "p-yield")
+		_, _ = w.WriteString(` itemprop="recipeYield">`)
+		_, _ = w.Write(util.EscapeHTML([]byte(y.String())))
+		_, _ = w.WriteString("</li>\n")
+	}
+	_, _ = w.WriteString("</ul>\n")
+	return gast.WalkSkipChildren, nil
+}
+
+func (r *HTMLRenderer) renderIngredients(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<" + r.sectionTag())
+		r.writeClass(w, "ingredients")
+		r.writeLabel(w, r.labels.Ingredients)
+		_, _ = w.WriteString(">\n")
+		r.writeHeading(w, r.labels.Ingredients)
+	} else {
+		_, _ = w.WriteString("</" + r.sectionTag() + ">\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *HTMLRenderer) renderIngredientGroup(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		group, ok := n.(*ast.IngredientGroup)
+		if !ok {
+			return gast.WalkStop, unexpectedNode(n)
+		}
+		_, _ = w.WriteString("<" + r.sectionTag())
+		r.writeClass(w, "ingredient-group")
+		writeID(w, n, group.Title)
+		r.writeLabel(w, group.Title)
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</" + r.sectionTag() + ">\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *HTMLRenderer) renderIngredient(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		ingredient, ok := n.(*ast.Ingredient)
+		if !ok {
+			return gast.WalkStop, unexpectedNode(n)
+		}
+		_, preparation, optional := SplitName(ingredient.Name)
+		_, _ = w.WriteString(`<li`)
+		classes := []string{"ingredient"}
+		if ingredient.Pinned {
+			classes = append(classes, "pinned")
+		}
+		if optional {
+			classes = append(classes, "optional")
+		}
+		r.writeClasses(w, "p-ingredient", classes...)
+		if preparation != "" {
+			_, _ = w.WriteString(` data-preparation="`)
+			_, _ = w.Write(util.EscapeHTML([]byte(preparation)))
+			_ = w.WriteByte('"')
+		}
+		if r.Checkboxes && ingredient.Amount != nil {
+			writeAmountData(w, *ingredient.Amount)
+		}
+		_, _ = w.WriteString(` itemprop="recipeIngredient">`)
+		if fc := n.FirstChild(); fc != nil && fc.Kind() != gast.KindTextBlock {
+			_ = w.WriteByte('\n')
+		}
+	} else {
+		_, _ = w.WriteString("</li>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *HTMLRenderer) renderAmount(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<span`)
+		r.writeClass(w, "amount")
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</span>")
+	}
+	return gast.WalkContinue, nil
+}
+
+// renderInstructions renders the instructions. If they have steps, the
+// steps are the recipeInstructions, each a HowToStep; otherwise the
+// instructions as a whole are.
+func (r *HTMLRenderer) renderInstructions(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</" + r.sectionTag() + ">\n")
+		return gast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("<" + r.sectionTag())
+	r.writeClasses(w, "e-instructions", "instructions")
+	r.writeLabel(w, r.labels.Instructions)
+	if hasSteps(n) {
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString(` itemprop="recipeInstructions">` + "\n")
+	}
+	r.writeHeading(w, r.labels.Instructions)
+	return gast.WalkContinue, nil
+}
+
+// renderListItem renders list items like goldmark does, adding HowToStep
+// microdata to the steps of the instructions.
+func (r *HTMLRenderer) renderListItem(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	step := isStep(n)
+	if !entering {
+		if step {
+			_, _ = w.WriteString("</div>")
+		}
+		_, _ = w.WriteString("</li>\n")
+		return gast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("<li")
+	if n.Attributes() != nil {
+		html.RenderAttributes(w, n, html.ListItemAttributeFilter)
+	}
+	if step {
+		writeStepStart(w, n)
+		_, _ = w.WriteString(`<div itemprop="text">`)
+	} else {
+		_ = w.WriteByte('>')
+	}
+	if fc := n.FirstChild(); fc != nil && fc.Kind() != gast.KindTextBlock {
+		_ = w.WriteByte('\n')
+	}
+	return gast.WalkContinue, nil
+}
+
+// renderParagraph renders paragraphs like goldmark does, adding HowToStep
+// microdata to the steps of the instructions and the checkbox label of an
+// ingredient name. With A11y, steps are items of an ordered list.
+func (r *HTMLRenderer) renderParagraph(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	step := isStep(n)
+	tag := "p"
+	if step && r.A11y {
+		tag = "li"
+	}
+	if !entering {
+		if step {
+			_, _ = w.WriteString("</span>")
+		}
+		if r.isCheckboxLabel(n) {
+			_, _ = w.WriteString("</label>")
+		}
+		_, _ = w.WriteString("</" + tag + ">\n")
+		if tag == "li" && !isParagraphStep(n.NextSibling()) {
+			_, _ = w.WriteString("</ol>\n")
+		}
+		return gast.WalkContinue, nil
+	}
+	if tag == "li" && !isParagraphStep(n.PreviousSibling()) {
+		// steps after a block that is not a step continue the numbering
+		if number := StepNumber(n); number > 1 {
+			_, _ = w.WriteString(`<ol start="` + strconv.Itoa(number) + `">` + "\n")
+		} else {
+			_, _ = w.WriteString("<ol>\n")
+		}
+	}
+	_, _ = w.WriteString("<" + tag)
+	if n.Attributes() != nil {
+		html.RenderAttributes(w, n, html.ParagraphAttributeFilter)
+	}
+	if step {
+		writeStepStart(w, n)
+		_, _ = w.WriteString(`<span itemprop="text">`)
+	} else {
+		_ = w.WriteByte('>')
+	}
+	if r.isCheckboxLabel(n) {
+		r.writeCheckbox(w)
+	}
+	return gast.WalkContinue, nil
+}
+
+// renderTextBlock renders text blocks like goldmark does, adding the
+// checkbox label of an ingredient name.
+func (r *HTMLRenderer) renderTextBlock(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	label := r.isCheckboxLabel(n)
+	if entering {
+		if label {
+			r.writeCheckbox(w)
+		}
+		return gast.WalkContinue, nil
+	}
+	if label {
+		_, _ = w.WriteString("</label>")
+	}
+	if n.NextSibling() != nil && n.FirstChild() != nil {
+		_ = w.WriteByte('\n')
+	}
+	return gast.WalkContinue, nil
+}
+
+// isParagraphStep reports whether n is a paragraph that is a step.
+func isParagraphStep(n gast.Node) bool {
+	return n != nil && n.Kind() == gast.KindParagraph && isStep(n)
+}
+
+// isCheckboxLabel reports whether n is the block holding the amount and
+// name of an ingredient and checkboxes are enabled.
+func (r *HTMLRenderer) isCheckboxLabel(n gast.Node) bool {
+	p := n.Parent()
+	return r.Checkboxes && p != nil && p.Kind() == ast.KindIngredient && p.FirstChild() == n
+}
+
+// writeCheckbox opens the label of an ingredient name with its checkbox.
+func (r *HTMLRenderer) writeCheckbox(w util.BufWriter) {
+	if r.XHTML {
+		_, _ = w.WriteString(`<label><input type="checkbox" /> `)
+	} else {
+		_, _ = w.WriteString(`<label><input type="checkbox"> `)
+	}
+}
+
+// unexpectedNode returns the error for a node of a RecipeMD kind that is
+// not of the type of that kind, such as a node of another extension
+// claiming the kind.
+func unexpectedNode(n gast.Node) error {
+	return fmt.Errorf("extension: unexpected node %T of kind %s", n, n.Kind())
+}
+
+// writeStepStart ends the start tag of a step with its microdata and
+// writes its position.
+func writeStepStart(w util.BufWriter, n gast.Node) {
+	_, _ = w.WriteString(` itemprop="recipeInstructions" itemscope itemtype="https://schema.org/HowToStep">`)
+	_, _ = w.WriteString(`<meta itemprop="position" content="`)
+	_, _ = w.WriteString(strconv.Itoa(StepNumber(n)))
+	_, _ = w.WriteString(`">`)
+}
+
+// htmlTransformer sets the attributes of the nodes of a recipe that the
+// HTMLRenderer writes but goldmark renders, so rendering leaves the
+// document as it is. It writes the classes of image paragraphs as config
+// maps them, and gives the title and the ingredient groups ids that are
+// distinct within the document.
+type htmlTransformer struct {
+	config *HTMLConfig
+}
+
+func (t htmlTransformer) Transform(doc *gast.Document, reader text.Reader, pc parser.Context) {
+	recipe, ok := doc.FirstChild().(*ast.Recipe)
+	if !ok {
+		return
+	}
+	var ids slug.Set
+	_ = gast.Walk(recipe, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Title:
+			setID(n, ids.Add(n.Title))
+		case *ast.IngredientGroup:
+			setID(n, ids.Add(n.Title))
+		}
+		switch n.Kind() {
+		case ast.KindDescription, ast.KindInstructions:
+			markImages(n, t.config.Microformats)
+			return gast.WalkSkipChildren, nil
+		case ast.KindIngredientGroup:
+			markImageParagraph(n.FirstChild().NextSibling(), t.config.class("group-image"))
+		case ast.KindIngredient:
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if c != n.FirstChild() && markImageParagraph(c, t.config.class("ingredient-image")) {
+					break
+				}
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+// markImages makes the images in n images of the recipe, which goldmark's
+// image renderer writes as an itemprop attribute and, if mf is set, the
+// class u-photo.
+func markImages(n gast.Node, mf bool) {
+	_ = gast.Walk(n, func(c gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && c.Kind() == gast.KindImage {
+			c.SetAttributeString("itemprop", []byte("image"))
+			if mf {
+				c.SetAttributeString("class", []byte("u-photo"))
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+// markImageParagraph gives n the class, if any, if it is a paragraph of
+// just an image, the image of an ingredient group or ingredient, and
+// reports whether it is.
+func markImageParagraph(n gast.Node, class string) bool {
+	if _, ok := ImageParagraph(n); !ok {
+		return false
+	}
+	if class != "" {
+		n.SetAttributeString("class", []byte(class))
+	}
+	return true
+}
+
+// writeDuration writes a meta element for the duration property prop,
+// unless d is zero.
+func writeDuration(w util.BufWriter, prop string, d time.Duration) {
+	if d > 0 {
+		_, _ = w.WriteString(`<meta itemprop="` + prop + `" content="` + ISODuration(d) + `">` + "\n")
+	}
+}
+
+// writeClass writes the class attribute of an element with the classes
+// names by default, as configured, unless no class is left.
+func (r *HTMLRenderer) writeClass(w util.BufWriter, names ...string) {
+	r.writeClasses(w, "", names...)
+}
+
+// writeClasses writes the class attribute of an element with the classes
+// names by default, as configured, followed by the microformats class mf
+// if Microformats is set, unless no class is left.
+func (r *HTMLRenderer) writeClasses(w util.BufWriter, mf string, names ...string) {
+	var classes []string
+	for _, name := range names {
+		if class := r.class(name); class != "" {
+			classes = append(classes, class)
+		}
+	}
+	if r.Microformats && mf != "" {
+		classes = append(classes, mf)
+	}
+	if len(classes) == 0 {
+		return
+	}
+	_, _ = w.WriteString(` class="`)
+	_, _ = w.Write(util.EscapeHTML([]byte(strings.Join(classes, " "))))
+	_ = w.WriteByte('"')
+}
+
+// sectionTag returns the element of the sections of a recipe, such as
+// its ingredients.
+func (r *HTMLRenderer) sectionTag() string {
+	if r.A11y {
+		return "section"
+	}
+	return "div"
+}
+
+// writeLabel writes an aria-label attribute with label, if A11y is set.
+func (r *HTMLRenderer) writeLabel(w util.BufWriter, label string) {
+	if !r.A11y || label == "" {
+		return
+	}
+	_, _ = w.WriteString(` aria-label="`)
+	_, _ = w.Write(util.EscapeHTML([]byte(label)))
+	_ = w.WriteByte('"')
+}
+
+// writeHeading writes the heading of a section with label, if Headings
+// is set.
+func (r *HTMLRenderer) writeHeading(w util.BufWriter, label string) {
+	if !r.Headings {
+		return
+	}
+	_, _ = w.WriteString("<h2")
+	r.writeClass(w, "section-heading")
+	_ = w.WriteByte('>')
+	_, _ = w.Write(util.EscapeHTML([]byte(label)))
+	_, _ = w.WriteString("</h2>\n")
+}
+
+// writeAmountData writes the data attributes of an ingredient amount.
+func writeAmountData(w util.BufWriter, a amount.Amount) {
+	if a.Factor != nil {
+		_, _ = w.WriteString(` data-amount-factor="` + amount.Decimal(a.Factor) + `"`)
+	}
+	if a.IsRange() {
+		_, _ = w.WriteString(` data-amount-max="` + amount.Decimal(a.Max) + `"`)
+	}
+	if a.Unit != "" {
+		_, _ = w.WriteString(` data-amount-unit="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(a.Unit)))
+		_ = w.WriteByte('"')
+	}
+}
+
+// setID sets the id attribute of n, unless id is empty.
+func setID(n gast.Node, id string) {
+	if id != "" {
+		n.SetAttributeString("id", []byte(id))
+	}
+}
+
+// writeID writes the id attribute the transformer gave n or, for
+// documents it did not transform, one derived from text, if it has a
+// slug.
+func writeID(w util.BufWriter, n gast.Node, text string) {
+	id := slug.Make(text)
+	if v, ok := n.AttributeString("id"); ok {
+		b, _ := v.([]byte)
+		id = string(b)
+	}
+	if id != "" {
+		_, _ = w.WriteString(` id="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(id)))
+		_ = w.WriteByte('"')
+	}
+}
+
+// recipeDirection returns the text direction of a recipe, taken from its
+// title or, if the title has no letters, its description.
+func recipeDirection(n gast.Node, source []byte) string {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Title:
+			if dir := direction(c.Title); dir != "" {
+				return dir
+			}
+		case *ast.Description:
+			return direction(PlainText(c, source))
+		}
+	}
+	return ""
+}
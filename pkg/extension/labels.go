@@ -0,0 +1,61 @@
+package extension
+
+import "strings"
+
+// Labels are the names of the sections of a recipe that renderers write,
+// as headings or as labels for screen readers.
+type Labels struct {
+	Tags         string
+	Yields       string
+	Ingredients  string
+	Instructions string
+}
+
+// DefaultLabels are the labels of the languages LabelsFor knows, by
+// lowercase BCP 47 language tag. Add or replace entries to change what
+// LabelsFor returns; do so before rendering, as the map is not safe for
+// concurrent modification.
+var DefaultLabels = map[string]Labels{
+	"en": {Tags: "Tags", Yields: "Yields", Ingredients: "Ingredients", Instructions: "Instructions"},
+	"de": {Tags: "Schlagwörter", Yields: "Ergibt", Ingredients: "Zutaten", Instructions: "Zubereitung"},
+	"fr": {Tags: "Mots-clés", Yields: "Portions", Ingredients: "Ingrédients", Instructions: "Préparation"},
+	"es": {Tags: "Etiquetas", Yields: "Raciones", Ingredients: "Ingredientes", Instructions: "Preparación"},
+	"it": {Tags: "Tag", Yields: "Dosi", Ingredients: "Ingredienti", Instructions: "Preparazione"},
+	"nl": {Tags: "Tags", Yields: "Opbrengst", Ingredients: "Ingrediënten", Instructions: "Bereiding"},
+	"pt": {Tags: "Etiquetas", Yields: "Rendimento", Ingredients: "Ingredientes", Instructions: "Modo de preparo"},
+	"ar": {Tags: "الوسوم", Yields: "الكمية", Ingredients: "المكونات", Instructions: "طريقة التحضير"},
+	"he": {Tags: "תגיות", Yields: "כמות", Ingredients: "מצרכים", Instructions: "אופן ההכנה"},
+}
+
+// LabelsFor returns the labels of DefaultLabels for lang, a BCP 47
+// language tag such as "de" or "pt-BR". A tag without labels of its own
+// falls back to its language, "pt-BR" to "pt", and an unknown language
+// to English.
+func LabelsFor(lang string) Labels {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	if l, ok := DefaultLabels[lang]; ok {
+		return l.Or(DefaultLabels["en"])
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	if l, ok := DefaultLabels[base]; ok {
+		return l.Or(DefaultLabels["en"])
+	}
+	return DefaultLabels["en"]
+}
+
+// Or returns l with its empty labels taken from fallback.
+func (l Labels) Or(fallback Labels) Labels {
+	if l.Tags == "" {
+		l.Tags = fallback.Tags
+	}
+	if l.Yields == "" {
+		l.Yields = fallback.Yields
+	}
+	if l.Ingredients == "" {
+		l.Ingredients = fallback.Ingredients
+	}
+	if l.Instructions == "" {
+		l.Instructions = fallback.Instructions
+	}
+	return l
+}
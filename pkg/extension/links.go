@@ -0,0 +1,75 @@
+package extension
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+)
+
+// A LinkResolver returns the destination to write for link, the link of
+// an ingredient of the document at the slash-separated path from. It
+// reports false to leave the link as it is.
+type LinkResolver func(from, link string) (string, bool)
+
+// WithLinkResolver rewrites the links of ingredients with resolve, for
+// example to point them at the pages generated for the linked recipes.
+// Only documents whose path was given to SetDocumentPath are rewritten.
+// It has no effect together with WithNonDestructive, which leaves the
+// document as it is.
+func WithLinkResolver(resolve LinkResolver) Option {
+	return func(e *recipemd) {
+		e.resolve = resolve
+	}
+}
+
+var documentPathKey = parser.NewContextKey()
+
+// SetDocumentPath records in pc the slash-separated path of the document
+// parsed with it, which the links of its ingredients are relative to.
+func SetDocumentPath(pc parser.Context, p string) {
+	pc.Set(documentPathKey, p)
+}
+
+// linkTransformer rewrites the links of ingredients with a LinkResolver.
+type linkTransformer struct {
+	resolve LinkResolver
+}
+
+func (t linkTransformer) Transform(doc *gast.Document, reader text.Reader, pc parser.Context) {
+	from, ok := pc.Get(documentPathKey).(string)
+	if !ok {
+		return
+	}
+	recipe, ok := doc.FirstChild().(*ast.Recipe)
+	if !ok {
+		return
+	}
+	_ = gast.Walk(recipe, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindDescription, ast.KindInstructions:
+			return gast.WalkSkipChildren, nil
+		case ast.KindIngredient:
+			if fc := n.FirstChild(); fc != nil {
+				t.rewrite(from, fc)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+// rewrite rewrites the links of block, the first block of an ingredient,
+// which holds its amount and name.
+func (t linkTransformer) rewrite(from string, block gast.Node) {
+	for c := block.FirstChild(); c != nil; c = c.NextSibling() {
+		if l, ok := c.(*gast.Link); ok {
+			if dest, ok := t.resolve(from, string(l.Destination)); ok {
+				l.Destination = []byte(dest)
+			}
+		}
+	}
+}
@@ -1,16 +1,127 @@
+// Package extension implements RecipeMD as a goldmark extension. It
+// parses the sections of a recipe into the nodes of package ast and
+// renders them as HTML with schema.org microdata. Its own output is HTML;
+// recipemd.Extension wraps it to write the JSON or canonical markdown of
+// the recipe instead.
 package extension
 
 import (
+	"sync"
+
 	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 type recipemd struct {
+	nonDestructive bool
+	resolve        LinkResolver
+	htmlOptions    []html.Option
 }
 
 // recipemd is an extension that provides RecipeMD markdown functionalities.
 var RecipeMD = &recipemd{}
 
+// Option configures an extension returned by New.
+type Option func(*recipemd)
+
+// WithNonDestructive leaves the parsed document as goldmark builds it, so
+// it can be rendered by the default HTML renderer and combined with other
+// extensions that expect the usual node structure. The RecipeMD structure
+// is built from a separate parse of the source and stored in the parser
+// context, where RecipeDocument retrieves it. Only the CommonMark syntax
+// and RecipeMD are recognized in that parse.
+func WithNonDestructive() Option {
+	return func(e *recipemd) {
+		e.nonDestructive = true
+	}
+}
+
+// WithHTMLOptions configures the HTMLRenderer of the extension with opts,
+// HTMLOptions such as WithCheckboxes and goldmark's html options. It has
+// no effect together with WithNonDestructive, which leaves rendering to
+// goldmark.
+func WithHTMLOptions(opts ...html.Option) Option {
+	return func(e *recipemd) {
+		e.htmlOptions = append(e.htmlOptions, opts...)
+	}
+}
+
+// htmlConfig returns the configuration the HTMLOptions of e make.
+func (e *recipemd) htmlConfig() *HTMLConfig {
+	var c HTMLConfig
+	for _, opt := range e.htmlOptions {
+		if opt, ok := opt.(HTMLOption); ok {
+			opt.SetRecipeHTMLOption(&c)
+		}
+	}
+	return &c
+}
+
+// New returns a RecipeMD extension configured by opts. New() is
+// equivalent to RecipeMD.
+func New(opts ...Option) goldmark.Extender {
+	e := &recipemd{}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
 func (e *recipemd) Extend(m goldmark.Markdown) {
-	// noop for now
+	if e.nonDestructive {
+		m.Parser().AddOptions(
+			parser.WithASTTransformers(util.Prioritized(contextTransformer{}, 100)),
+		)
+		return
+	}
+	m.Parser().AddOptions(
+		// takes precedence over goldmark's thematic break parser (200)
+		parser.WithBlockParsers(util.Prioritized(NewDividerParser(), 199)),
+		parser.WithASTTransformers(
+			util.Prioritized(NewTransformer(), 100),
+			// runs after the transformer building the recipe
+			util.Prioritized(htmlTransformer{e.htmlConfig()}, 101),
+		),
+	)
+	if e.resolve != nil {
+		m.Parser().AddOptions(
+			parser.WithASTTransformers(util.Prioritized(linkTransformer{e.resolve}, 102)),
+		)
+	}
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(util.Prioritized(NewHTMLRenderer(e.htmlOptions...), 500)),
+	)
+}
+
+var recipeDocumentKey = parser.NewContextKey()
+
+// RecipeDocument returns the document with the RecipeMD structure that an
+// extension created WithNonDestructive stored in pc, or nil if there is
+// none. It can be passed to recipemd.ExtractRecipe with the source.
+func RecipeDocument(pc parser.Context) gast.Node {
+	if doc, ok := pc.Get(recipeDocumentKey).(gast.Node); ok {
+		return doc
+	}
+	return nil
+}
+
+// contextTransformer stores the RecipeMD structure of a document in the
+// parser context without changing the document.
+type contextTransformer struct{}
+
+var (
+	recipeParserOnce sync.Once
+	recipeParser     parser.Parser
+)
 
+func (contextTransformer) Transform(doc *gast.Document, reader text.Reader, pc parser.Context) {
+	recipeParserOnce.Do(func() {
+		recipeParser = goldmark.New(goldmark.WithExtensions(RecipeMD)).Parser()
+	})
+	pc.Set(recipeDocumentKey, recipeParser.Parse(text.NewReader(reader.Source())))
 }
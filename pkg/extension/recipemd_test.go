@@ -0,0 +1,163 @@
+package extension
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+)
+
+const tea = `# Tea
+
+A cup of tea.
+
+*hot, quick*
+
+**1 cup**
+
+---
+
+- *1* tea bag
+- water
+
+---
+
+Steep for *three* minutes.
+`
+
+func TestTransform(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(RecipeMD))
+	doc := md.Parser().Parse(text.NewReader([]byte(tea)))
+	recipe, ok := doc.FirstChild().(*ast.Recipe)
+	if !ok {
+		t.Fatalf("first child is %s, want a recipe", doc.FirstChild().Kind())
+	}
+	var kinds []gast.NodeKind
+	for c := recipe.FirstChild(); c != nil; c = c.NextSibling() {
+		kinds = append(kinds, c.Kind())
+	}
+	want := []gast.NodeKind{
+		ast.KindTitle, ast.KindDescription, ast.KindTags, ast.KindYields,
+		gast.KindThematicBreak, ast.KindIngredients,
+		gast.KindThematicBreak, ast.KindInstructions,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("recipe children = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("child %d = %s, want %s", i, kinds[i], want[i])
+		}
+	}
+}
+
+func TestTransformNoRecipe(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(RecipeMD))
+	doc := md.Parser().Parse(text.NewReader([]byte("Just a paragraph.\n\n# Late title\n")))
+	if _, ok := doc.FirstChild().(*ast.Recipe); ok {
+		t.Error("document without a leading title was turned into a recipe")
+	}
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"title", tea, []string{`<h1 id="tea" itemprop="name">Tea</h1>`, `itemtype="https://schema.org/Recipe"`}},
+		{"tags and yields", tea, []string{`<li itemprop="keywords">hot</li>`, `<li itemprop="recipeYield">1 cup</li>`}},
+		{"ingredients", tea, []string{
+			`<li class="ingredient" itemprop="recipeIngredient"><span class="amount">1</span> tea bag</li>`,
+			`<li class="ingredient" itemprop="recipeIngredient">water</li>`,
+		}},
+		{"instructions", tea, []string{`<span itemprop="text">Steep for <em>three</em> minutes.</span>`}},
+		{"no recipe", "Just *text*.\n", []string{"<p>Just <em>text</em>.</p>"}},
+	}
+	md := goldmark.New(goldmark.WithExtensions(RecipeMD))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := md.Convert([]byte(tt.source), &buf); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("output does not contain %s:\n%s", w, &buf)
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLRendererIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"emoji title", "# 🌮 Taco Tuesday\n", []string{`<h1 id="taco-tuesday"`}},
+		{"emoji only", "# 🌮\n", []string{`<h1 id="u1f32e"`}},
+		{
+			"group like the title",
+			"# 🌮 Taco Tuesday\n\n---\n\n## Taco Tuesday\n\n- salsa\n",
+			[]string{`<h1 id="taco-tuesday"`, `id="taco-tuesday-2"`},
+		},
+		{
+			"groups with the same title",
+			"# Tacos\n\n---\n\n## Sauce\n\n- salsa\n\n## Sauce\n\n- crema\n",
+			[]string{`id="sauce"`, `id="sauce-2"`},
+		},
+	}
+	md := goldmark.New(goldmark.WithExtensions(RecipeMD))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := md.Convert([]byte(tt.source), &buf); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("output does not contain %s:\n%s", w, &buf)
+				}
+			}
+		})
+	}
+}
+
+// TestConcurrentConvert converts with one goldmark.Markdown and renders
+// one parsed document from 16 goroutines; run it with -race.
+func TestConcurrentConvert(t *testing.T) {
+	source := []byte(tea + "\n![cup](cup.jpg)\n")
+	md := goldmark.New(goldmark.WithExtensions(RecipeMD))
+	var want bytes.Buffer
+	if err := md.Convert(source, &want); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(want.String(), `itemprop="image"`) {
+		t.Fatalf("image not marked:\n%s", &want)
+	}
+	doc := md.Parser().Parse(text.NewReader(source))
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			var converted, rendered bytes.Buffer
+			if err := md.Convert(source, &converted); err != nil {
+				t.Error(err)
+			}
+			if err := md.Renderer().Render(&rendered, source, doc); err != nil {
+				t.Error(err)
+			}
+			if converted.String() != want.String() || rendered.String() != want.String() {
+				t.Errorf("concurrent output differs:\n%s\n%s\nwant\n%s", &converted, &rendered, &want)
+			}
+		})
+	}
+	wg.Wait()
+}
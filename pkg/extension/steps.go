@@ -0,0 +1,92 @@
+package extension
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+)
+
+// Steps returns the steps among the children of n, the instructions of a
+// recipe. If the instructions contain ordered lists, the steps are their
+// items and the paragraphs around them are not steps. Otherwise every
+// paragraph is a step.
+func Steps(n gast.Node) []gast.Node {
+	var items, paragraphs []gast.Node
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *gast.List:
+			if c.IsOrdered() {
+				for item := c.FirstChild(); item != nil; item = item.NextSibling() {
+					items = append(items, item)
+				}
+			}
+		case *gast.Paragraph:
+			paragraphs = append(paragraphs, c)
+		}
+	}
+	if len(items) > 0 {
+		return items
+	}
+	return paragraphs
+}
+
+// StepNumber returns the number of a step returned by Steps: the number
+// of a list item as written in the list, and the position of a paragraph
+// among the paragraphs, counting from 1.
+func StepNumber(step gast.Node) int {
+	number := 1
+	if list, ok := step.Parent().(*gast.List); ok {
+		number = list.Start
+	}
+	for c := step.PreviousSibling(); c != nil; c = c.PreviousSibling() {
+		if c.Kind() == step.Kind() {
+			number++
+		}
+	}
+	return number
+}
+
+// isStep reports whether n is a step of the recipe instructions it is in,
+// one of Steps of them. It is called for every list item and paragraph
+// rendered, so unlike Steps it allocates nothing.
+func isStep(n gast.Node) bool {
+	switch n.Kind() {
+	case gast.KindListItem:
+		list, ok := n.Parent().(*gast.List)
+		return ok && list.IsOrdered() && list.Parent() != nil && list.Parent().Kind() == ast.KindInstructions
+	case gast.KindParagraph:
+		container := n.Parent()
+		if container == nil || container.Kind() != ast.KindInstructions {
+			return false
+		}
+		return !hasOrderedList(container)
+	}
+	return false
+}
+
+// hasSteps reports whether the instructions n have steps, without
+// collecting them as Steps does.
+func hasSteps(n gast.Node) bool {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *gast.List:
+			if c.IsOrdered() && c.HasChildren() {
+				return true
+			}
+		case *gast.Paragraph:
+			return true
+		}
+	}
+	return false
+}
+
+// hasOrderedList reports whether an ordered list is among the children of
+// n.
+func hasOrderedList(n gast.Node) bool {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if list, ok := c.(*gast.List); ok && list.IsOrdered() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package extension
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// PlainText returns the unformatted text content of n with escapes and
+// character references resolved. The texts of blocks in n, such as the
+// paragraphs of a list item or the cells of a table, are separated by a
+// space.
+func PlainText(n gast.Node, source []byte) string {
+	var b strings.Builder
+	writeText(&b, n, source)
+	return strings.TrimSpace(b.String())
+}
+
+// writeText writes the unformatted text content of n to b.
+func writeText(b *strings.Builder, n gast.Node, source []byte) {
+	_ = gast.Walk(n, func(c gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if c != n && c.Type() == gast.TypeBlock && b.Len() > 0 && b.String()[b.Len()-1] != ' ' {
+			b.WriteByte(' ')
+		}
+		switch c := c.(type) {
+		case *gast.Text:
+			v := c.Segment.Value(source)
+			if !c.IsRaw() {
+				v = util.UnescapePunctuations(v)
+				v = util.ResolveNumericReferences(v)
+				v = util.ResolveEntityNames(v)
+			}
+			b.Write(v)
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *gast.String:
+			b.Write(c.Value)
+		case *gast.AutoLink:
+			b.Write(c.Label(source))
+			return gast.WalkSkipChildren, nil
+		case *gast.RawHTML:
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+// optionalRe matches the "(optional)" marker of an ingredient name.
+var optionalRe = regexp.MustCompile(`(?i)\s*\(\s*optional\s*\)`)
+
+// SplitName splits the text of an ingredient into its name, the
+// preparation note following the first comma outside parentheses and
+// whether it is marked optional with "(optional)" or a trailing
+// ", optional": "butter, softened (optional)" is the name "butter" with
+// the preparation "softened". Text without a name before the comma is
+// not split.
+func SplitName(text string) (name, preparation string, optional bool) {
+	name = strings.TrimSpace(text)
+	if s := optionalRe.ReplaceAllString(name, ""); s != name && s != "" {
+		name, optional = strings.TrimSpace(s), true
+	}
+	depth := 0
+	for i, c := range name {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth != 0 || strings.TrimSpace(name[:i]) == "" {
+				continue
+			}
+			preparation = strings.TrimSpace(name[i+1:])
+			name = strings.TrimSpace(name[:i])
+			if p, ok := cutSuffixFold(preparation, "optional"); ok && (p == "" || strings.HasSuffix(p, ",")) {
+				preparation, optional = strings.TrimSpace(strings.TrimSuffix(p, ",")), true
+			}
+			return name, preparation, optional
+		}
+	}
+	return name, "", optional
+}
+
+// cutSuffixFold is strings.CutSuffix ignoring case.
+func cutSuffixFold(s, suffix string) (string, bool) {
+	if len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix) {
+		return strings.TrimSpace(s[:len(s)-len(suffix)]), true
+	}
+	return s, false
+}
+
+// splitTags splits a comma separated list, dropping empty entries.
+func splitTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// lineStart returns the offset of the beginning of the line containing i.
+func lineStart(source []byte, i int) int {
+	for i > 0 && source[i-1] != '\n' {
+		i--
+	}
+	return i
+}
+
+// lineEnd returns the offset just past the end of the line containing i,
+// including its line terminator.
+func lineEnd(source []byte, i int) int {
+	for i < len(source) && source[i] != '\n' {
+		i++
+	}
+	if i < len(source) {
+		i++
+	}
+	return i
+}
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Arabic,
+	unicode.Hebrew,
+	unicode.Nko,
+	unicode.Syriac,
+	unicode.Thaana,
+	unicode.Samaritan,
+	unicode.Mandaic,
+	unicode.Adlam,
+}
+
+// direction returns "rtl" or "ltr" depending on the first letter of s
+// written in a script with a strong direction, as the HTML dir="auto"
+// algorithm does, or "" if s has no such letter.
+func direction(s string) string {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if unicode.In(r, rtlScripts...) {
+			return "rtl"
+		}
+		return "ltr"
+	}
+	return ""
+}
+
+// ImageParagraph returns the image if n is a paragraph holding a single
+// image and nothing else, such as the picture of an ingredient group on
+// the line after its heading.
+func ImageParagraph(n gast.Node) (*gast.Image, bool) {
+	if n == nil || n.Kind() != gast.KindParagraph || n.ChildCount() != 1 {
+		return nil, false
+	}
+	img, ok := n.FirstChild().(*gast.Image)
+	return img, ok
+}
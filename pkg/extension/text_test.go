@@ -0,0 +1,61 @@
+package extension
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Steep for *three* minutes.", "Steep for three minutes."},
+		{"Fish &amp; chips\\!", "Fish & chips!"},
+		{"A soft\nbreak and `code`.", "A soft break and code."},
+		{"See <https://x.org> and <b>bold</b>.", "See https://x.org and bold."},
+		{"- one\n- two", "one two"},
+	}
+	md := goldmark.New()
+	for _, tt := range tests {
+		source := []byte(tt.in)
+		doc := md.Parser().Parse(text.NewReader(source))
+		if got := PlainText(doc, source); got != tt.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// longInstructions returns a recipe whose instructions have n steps.
+func longInstructions(n int) []byte {
+	var b strings.Builder
+	b.WriteString("# Stew\n\n---\n\n- *1 kg* beef\n\n---\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, "%d. Stir the *stew* for %d minutes &amp; taste it, then [check](notes.md) the `heat`.\n", i+1, i)
+	}
+	return []byte(b.String())
+}
+
+func BenchmarkPlainText(b *testing.B) {
+	source := longInstructions(200)
+	md := goldmark.New(goldmark.WithExtensions(RecipeMD))
+	doc := md.Parser().Parse(text.NewReader(source))
+	var instructions *ast.Instructions
+	for c := doc.FirstChild().FirstChild(); c != nil; c = c.NextSibling() {
+		if n, ok := c.(*ast.Instructions); ok {
+			instructions = n
+		}
+	}
+	if instructions == nil {
+		b.Fatal("no instructions")
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		PlainText(instructions, source)
+	}
+}
@@ -0,0 +1,94 @@
+package extension
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// Times are the preparation, cooking and total times of a recipe, zero
+// where not given.
+type Times struct {
+	Prep, Cook, Total time.Duration
+}
+
+// timeRe matches a time given in a description, like "Prep time: 15 min"
+// or "Cook: 1 h 30 min".
+var timeRe = regexp.MustCompile(`(?i)\b(prep(?:aration)?|cook(?:ing)?|total)(?:\s+time)?\s*:\s*((?:\d+(?:[.,]\d+)?\s*(?:days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)\b[\s,]*(?:and\s+)?)+)`)
+
+// durationRe matches the parts of a duration matched by timeRe.
+var durationRe = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*([a-z]+)`)
+
+// ParseTimes returns the times given in the paragraphs of n, the
+// description of a recipe, with the labels "Prep time", "Cook time" and
+// "Total time", or just "Prep", "Cook" and "Total", followed by a colon:
+// "Prep time: 15 min · Cook time: 1 h 30 min". If the total is not given
+// it is the sum of the preparation and cooking times when both are.
+func ParseTimes(n gast.Node, source []byte) Times {
+	var t Times
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		for _, m := range timeRe.FindAllStringSubmatch(PlainText(c, source), -1) {
+			d := ParseDuration(m[2])
+			switch strings.ToLower(m[1][:1]) {
+			case "p":
+				t.Prep = d
+			case "c":
+				t.Cook = d
+			case "t":
+				t.Total = d
+			}
+		}
+	}
+	if t.Total == 0 && t.Prep > 0 && t.Cook > 0 {
+		t.Total = t.Prep + t.Cook
+	}
+	return t
+}
+
+// ParseDuration returns the duration of a number of days, hours, minutes
+// and seconds such as "1 h 30 min" or "1.5 hours", going by the first
+// letter of each unit. Parts it cannot read are ignored.
+func ParseDuration(s string) time.Duration {
+	var d time.Duration
+	for _, m := range durationRe.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+		if err != nil {
+			continue
+		}
+		unit := time.Second
+		switch strings.ToLower(m[2])[0] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		}
+		d += time.Duration(n * float64(unit))
+	}
+	return d.Round(time.Second)
+}
+
+// ISODuration formats d as an ISO 8601 duration, the format of
+// schema.org durations: 90 minutes is "PT1H30M".
+func ISODuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return "PT0S"
+	}
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += fmt.Sprintf("%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		s += fmt.Sprintf("%dM", m)
+	}
+	if sec := d % time.Minute / time.Second; sec > 0 {
+		s += fmt.Sprintf("%dS", sec)
+	}
+	return s
+}
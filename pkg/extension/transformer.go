@@ -0,0 +1,328 @@
+package extension
+
+import (
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+	// tells amount.Parse units of measurement from containers
+	_ "github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+// PinMarker prefixes an ingredient amount that must not be scaled, as in
+// "*=7 g* dry yeast".
+const PinMarker = "="
+
+type recipeTransformer struct {
+}
+
+// NewTransformer returns an ASTTransformer that groups the blocks of a
+// document starting with a first-level heading into RecipeMD sections.
+// Documents that do not start with a first-level heading are left as is.
+// The blocks of the description and instructions are moved into their
+// sections unchanged, so the nodes of other extensions, such as tables
+// and footnote references, are rendered by the renderers of those
+// extensions.
+func NewTransformer() parser.ASTTransformer {
+	return &recipeTransformer{}
+}
+
+func (t *recipeTransformer) Transform(doc *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	heading, ok := doc.FirstChild().(*gast.Heading)
+	if !ok || heading.Level != 1 {
+		return
+	}
+	var blocks []gast.Node
+	for c := heading.NextSibling(); c != nil; c = c.NextSibling() {
+		blocks = append(blocks, c)
+	}
+	// blocks converted into RecipeMD nodes are consumed, all others must
+	// stay in the document
+	consumed := map[gast.Node]bool{heading: true}
+	defer checkKept(doc, blocks, consumed, pc)
+
+	recipe := ast.NewRecipe()
+	title := ast.NewTitle(PlainText(heading, source))
+	title.SetLines(heading.Lines())
+	moveChildren(title, heading)
+	doc.ReplaceChild(doc, heading, recipe)
+	recipe.AppendChild(recipe, title)
+
+	// description: everything up to the first tags, yields or divider
+	i := 0
+	desc := ast.NewDescription()
+	for ; i < len(blocks) && !isMeta(blocks[i]) && !isDivider(blocks[i]); i++ {
+		desc.AppendChild(desc, blocks[i])
+	}
+	if desc.HasChildren() {
+		end := len(source)
+		if i < len(blocks) {
+			end = blockStart(blocks[i], source)
+		}
+		desc.Lines().Append(section(titleEnd(heading, source), end, source))
+		recipe.AppendChild(recipe, desc)
+	}
+
+	// tags and yields; anything else before the divider stays a plain child
+	// of the recipe so extraction can report it
+	for ; i < len(blocks) && !isDivider(blocks[i]); i++ {
+		b := blocks[i]
+		switch {
+		case soleEmphasis(b, 1) != nil:
+			e := soleEmphasis(b, 1)
+			tags := ast.NewTags(splitTags(PlainText(e, source)))
+			tags.SetLines(b.Lines())
+			moveChildren(tags, b)
+			doc.RemoveChild(doc, b)
+			consumed[b] = true
+			recipe.AppendChild(recipe, tags)
+		case soleEmphasis(b, 2) != nil:
+			e := soleEmphasis(b, 2)
+			var yields []amount.Amount
+			for _, y := range amount.SplitList(PlainText(e, source)) {
+				yields = append(yields, amount.Parse(y))
+			}
+			node := ast.NewYields(yields)
+			node.SetLines(b.Lines())
+			moveChildren(node, b)
+			doc.RemoveChild(doc, b)
+			consumed[b] = true
+			recipe.AppendChild(recipe, node)
+		default:
+			recipe.AppendChild(recipe, b)
+		}
+	}
+	if i == len(blocks) {
+		return
+	}
+
+	// ingredients up to the second divider, instructions after it
+	first := blocks[i]
+	recipe.AppendChild(recipe, first)
+	i++
+	j := i
+	for j < len(blocks) && !isDivider(blocks[j]) {
+		j++
+	}
+	ingredients := ast.NewIngredients()
+	end := len(source)
+	if j < len(blocks) {
+		end = blockStart(blocks[j], source)
+	}
+	ingredients.Lines().Append(section(blockEnd(first, source), end, source))
+	buildIngredients(ingredients, blocks[i:j], source)
+	recipe.AppendChild(recipe, ingredients)
+	if j == len(blocks) {
+		return
+	}
+	second := blocks[j]
+	recipe.AppendChild(recipe, second)
+	instructions := ast.NewInstructions()
+	for _, b := range blocks[j+1:] {
+		instructions.AppendChild(instructions, b)
+	}
+	instructions.Lines().Append(section(blockEnd(second, source), len(source), source))
+	recipe.AppendChild(recipe, instructions)
+}
+
+var droppedKey = parser.NewContextKey()
+
+// DroppedBlocks returns the blocks of the document parsed with pc that the
+// RecipeMD transformer neither kept nor converted. It is a safety net: the
+// transformer is meant to keep all content, so any block returned here is
+// content that rendering and extraction would silently lose.
+func DroppedBlocks(pc parser.Context) []gast.Node {
+	dropped, _ := pc.Get(droppedKey).([]gast.Node)
+	return dropped
+}
+
+// checkKept records the blocks that are neither consumed nor attached to
+// doc any more in pc.
+func checkKept(doc *gast.Document, blocks []gast.Node, consumed map[gast.Node]bool, pc parser.Context) {
+	var dropped []gast.Node
+	for _, b := range blocks {
+		if consumed[b] {
+			continue
+		}
+		n := b
+		for n != nil && n != gast.Node(doc) {
+			n = n.Parent()
+		}
+		if n == nil {
+			dropped = append(dropped, b)
+		}
+	}
+	if len(dropped) > 0 {
+		pc.Set(droppedKey, dropped)
+	}
+}
+
+// buildIngredients appends blocks to container, nesting them into
+// ingredient groups according to heading levels and converting list items
+// into ingredients.
+func buildIngredients(container gast.Node, blocks []gast.Node, source []byte) {
+	stack := []gast.Node{container}
+	levels := []int{0}
+	for _, b := range blocks {
+		top := stack[len(stack)-1]
+		switch b := b.(type) {
+		case *gast.Heading:
+			for len(levels) > 1 && levels[len(levels)-1] >= b.Level {
+				stack = stack[:len(stack)-1]
+				levels = levels[:len(levels)-1]
+			}
+			top = stack[len(stack)-1]
+			group := ast.NewIngredientGroup(PlainText(b, source), b.Level)
+			group.AppendChild(group, b)
+			top.AppendChild(top, group)
+			stack = append(stack, group)
+			levels = append(levels, b.Level)
+		case *gast.List:
+			convertList(b, source)
+			top.AppendChild(top, b)
+		default:
+			top.AppendChild(top, b)
+		}
+	}
+}
+
+// convertList replaces every item of list with an Ingredient node.
+func convertList(list *gast.List, source []byte) {
+	for item := list.FirstChild(); item != nil; {
+		next := item.NextSibling()
+		ingredient := ast.NewIngredient()
+		moveChildren(ingredient, item)
+		list.ReplaceChild(list, item, ingredient)
+		parseIngredient(ingredient, source)
+		item = next
+	}
+}
+
+// parseIngredient extracts the amount, name and link of ingredient from its
+// first paragraph. Nested lists are converted as well.
+func parseIngredient(ingredient *ast.Ingredient, source []byte) {
+	for c := ingredient.FirstChild(); c != nil; c = c.NextSibling() {
+		if l, ok := c.(*gast.List); ok {
+			convertList(l, source)
+		}
+	}
+	block := ingredient.FirstChild()
+	if block == nil || (block.Kind() != gast.KindParagraph && block.Kind() != gast.KindTextBlock) {
+		return
+	}
+	if e, ok := block.FirstChild().(*gast.Emphasis); ok && e.Level == 1 {
+		s := PlainText(e, source)
+		if rest, ok := strings.CutPrefix(s, PinMarker); ok {
+			ingredient.Pinned = true
+			s = rest
+		}
+		a := amount.Parse(s)
+		node := ast.NewAmount(a)
+		moveChildren(node, e)
+		block.ReplaceChild(block, e, node)
+		if !a.IsZero() {
+			ingredient.Amount = &a
+		}
+	}
+	var name strings.Builder
+	var links []*gast.Link
+	others := 0
+	for c := block.FirstChild(); c != nil; c = c.NextSibling() {
+		if c.Kind() == ast.KindAmount {
+			continue
+		}
+		writeText(&name, c, source)
+		switch c := c.(type) {
+		case *gast.Link:
+			links = append(links, c)
+		case *gast.Text:
+			if len(strings.TrimSpace(string(c.Segment.Value(source)))) > 0 {
+				others++
+			}
+		default:
+			others++
+		}
+	}
+	ingredient.Name = strings.TrimSpace(name.String())
+	if len(links) == 1 && others == 0 {
+		ingredient.Link = string(links[0].Destination)
+	}
+}
+
+// soleEmphasis returns the emphasis of the given level if it is the only
+// content of paragraph n.
+func soleEmphasis(n gast.Node, level int) *gast.Emphasis {
+	if n.Kind() != gast.KindParagraph || n.ChildCount() != 1 {
+		return nil
+	}
+	if e, ok := n.FirstChild().(*gast.Emphasis); ok && e.Level == level {
+		return e
+	}
+	return nil
+}
+
+func isMeta(n gast.Node) bool {
+	return soleEmphasis(n, 1) != nil || soleEmphasis(n, 2) != nil
+}
+
+func isDivider(n gast.Node) bool {
+	return n.Kind() == gast.KindThematicBreak
+}
+
+func moveChildren(dst, src gast.Node) {
+	for c := src.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		dst.AppendChild(dst, c)
+		c = next
+	}
+}
+
+// blockStart returns the offset of the first line of a paragraph or divider.
+func blockStart(n gast.Node, source []byte) int {
+	if n.Lines().Len() == 0 {
+		return len(source)
+	}
+	return lineStart(source, n.Lines().At(0).Start)
+}
+
+// blockEnd returns the offset just past the last line of a divider.
+func blockEnd(n gast.Node, source []byte) int {
+	if n.Lines().Len() == 0 {
+		return len(source)
+	}
+	return lineEnd(source, n.Lines().At(n.Lines().Len()-1).Start)
+}
+
+// titleEnd returns the offset just past the title heading, including the
+// underline of a setext heading.
+func titleEnd(heading *gast.Heading, source []byte) int {
+	lines := heading.Lines()
+	if lines.Len() == 0 {
+		i := 0
+		for i < len(source) && (source[i] == ' ' || source[i] == '\t' || source[i] == '\n' || source[i] == '\r') {
+			i++
+		}
+		return lineEnd(source, i)
+	}
+	end := lineEnd(source, lines.At(lines.Len()-1).Start)
+	first := strings.TrimLeft(string(source[lineStart(source, lines.At(0).Start):lines.At(0).Start]), " ")
+	if !strings.HasPrefix(first, "#") {
+		end = lineEnd(source, end)
+	}
+	return end
+}
+
+// section returns the trimmed segment between start and stop.
+func section(start, stop int, source []byte) text.Segment {
+	if start > stop {
+		start = stop
+	}
+	s := text.NewSegment(start, stop)
+	s = s.TrimLeftSpace(source)
+	return s.TrimRightSpace(source)
+}
@@ -0,0 +1,178 @@
+// Package filter implements the recipe filter language of the RecipeMD
+// reference tool, e.g.
+//
+//	tag:vegan and not ingr:"peanut butter"
+//
+// An expression combines terms with the operators "and", "or" and "not"
+// (in increasing order of precedence) and parentheses. Adjacent terms
+// without an operator are joined with "and". A term is a field prefix
+// followed by a value, which may be quoted; a value without a prefix
+// matches tags. The fields are:
+//
+//	tag:   a tag equal to the value
+//	ingr:  an ingredient whose name contains the value
+//	unit:  an ingredient amount with a unit equal to the value
+//	title: a title containing the value
+//	diet:  a diet of recipemd.DefaultDiets, such as vegan, that the
+//	       recipe is a candidate for, guessed from its ingredient names
+//	allergen: an allergen of recipemd.DefaultAllergens, such as peanut,
+//	       that an ingredient name contains, also a guess
+//	difficulty: easy, medium or hard, the difficulty level of the
+//	       recipe by recipemd.DefaultComplexity
+//
+// All comparisons ignore case.
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// Expr is a parsed filter expression.
+type Expr interface {
+	// Match reports whether r satisfies the expression.
+	Match(r *recipemd.Recipe) bool
+	// String returns the expression in filter language syntax.
+	String() string
+}
+
+// Field selects the part of a recipe a Term is matched against.
+type Field string
+
+// Fields of the filter language.
+const (
+	FieldTag        Field = "tag"
+	FieldIngredient Field = "ingr"
+	FieldUnit       Field = "unit"
+	FieldTitle      Field = "title"
+	FieldDiet       Field = "diet"     // heuristic, see recipemd.Diet
+	FieldAllergen   Field = "allergen" // heuristic, see recipemd.Allergen
+	FieldDifficulty Field = "difficulty"
+)
+
+// Term matches a single field of a recipe against a value.
+type Term struct {
+	Field Field
+	Value string
+}
+
+// Match implements Expr.Match.
+func (t Term) Match(r *recipemd.Recipe) bool {
+	switch t.Field {
+	case FieldTag:
+		for _, tag := range r.Tags {
+			if strings.EqualFold(tag, t.Value) {
+				return true
+			}
+		}
+	case FieldIngredient:
+		value := strings.ToLower(t.Value)
+		for _, i := range r.AllIngredients() {
+			if strings.Contains(strings.ToLower(i.Name), value) {
+				return true
+			}
+		}
+	case FieldUnit:
+		for _, i := range r.AllIngredients() {
+			if i.Amount != nil && strings.EqualFold(i.Amount.Unit, t.Value) {
+				return true
+			}
+		}
+	case FieldTitle:
+		return strings.Contains(strings.ToLower(r.Title), strings.ToLower(t.Value))
+	case FieldDiet:
+		for _, d := range r.Diets(recipemd.DefaultDiets) {
+			if strings.EqualFold(d, t.Value) {
+				return true
+			}
+		}
+	case FieldAllergen:
+		for _, a := range r.Allergens(recipemd.DefaultAllergens) {
+			if strings.EqualFold(a, t.Value) {
+				return true
+			}
+		}
+	case FieldDifficulty:
+		return strings.EqualFold(r.Difficulty(recipemd.DefaultComplexity), t.Value)
+	}
+	return false
+}
+
+// String implements Expr.String.
+func (t Term) String() string {
+	return string(t.Field) + ":" + quote(t.Value)
+}
+
+// And matches recipes matched by both operands.
+type And struct {
+	Left, Right Expr
+}
+
+// Match implements Expr.Match.
+func (e And) Match(r *recipemd.Recipe) bool {
+	return e.Left.Match(r) && e.Right.Match(r)
+}
+
+// String implements Expr.String.
+func (e And) String() string {
+	return "(" + e.Left.String() + " and " + e.Right.String() + ")"
+}
+
+// Or matches recipes matched by either operand.
+type Or struct {
+	Left, Right Expr
+}
+
+// Match implements Expr.Match.
+func (e Or) Match(r *recipemd.Recipe) bool {
+	return e.Left.Match(r) || e.Right.Match(r)
+}
+
+// String implements Expr.String.
+func (e Or) String() string {
+	return "(" + e.Left.String() + " or " + e.Right.String() + ")"
+}
+
+// Not matches recipes not matched by its operand.
+type Not struct {
+	Expr Expr
+}
+
+// Match implements Expr.Match.
+func (e Not) Match(r *recipemd.Recipe) bool {
+	return !e.Expr.Match(r)
+}
+
+// String implements Expr.String.
+func (e Not) String() string {
+	return "not " + e.Expr.String()
+}
+
+// SyntaxError describes a malformed filter expression.
+type SyntaxError struct {
+	Offset int // byte offset into the expression
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("filter: %s at offset %d", e.Msg, e.Offset)
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"():") || isKeyword(s) {
+		return `"` + quoteReplacer.Replace(s) + `"`
+	}
+	return s
+}
+
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func isKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "and", "or", "not":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"vegan", "tag:vegan"},
+		{"tag:Vegan", "tag:Vegan"},
+		{"TAG:vegan", "tag:vegan"},
+		{`"quick and easy"`, `tag:"quick and easy"`},
+		{`ingr:"peanut butter"`, `ingr:"peanut butter"`},
+		{"ingredient:flour", "ingr:flour"},
+		{`title:"say \"hi\""`, `title:"say \"hi\""`},
+		{"unit:g title:bread", "(unit:g and title:bread)"},
+		{"a b c", "((tag:a and tag:b) and tag:c)"},
+		{"a or b and c", "(tag:a or (tag:b and tag:c))"},
+		{"(a or b) and c", "((tag:a or tag:b) and tag:c)"},
+		{"not a and b", "(not tag:a and tag:b)"},
+		{"not not a", "not not tag:a"},
+		{"a AND NOT b", "(tag:a and not tag:b)"},
+		{`"and"`, `tag:"and"`},
+		{"diet:vegan allergen:peanut difficulty:easy", "((diet:vegan and allergen:peanut) and difficulty:easy)"},
+		{"tag:vegan and not ingr:\"peanut butter\"", `(tag:vegan and not ingr:"peanut butter")`},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := e.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		again, err := Parse(e.String())
+		if err != nil || again.String() != e.String() {
+			t.Errorf("Parse(%q) = %v, %v, want %s", e.String(), again, err, e.String())
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		in     string
+		offset int
+		msg    string
+	}{
+		{"", 0, "expected a term but found end of expression"},
+		{"a and", 5, "expected a term but found end of expression"},
+		{"(a or b", 7, "expected ) but found end of expression"},
+		{"a)", 1, "unexpected )"},
+		{"or a", 0, "expected a term but found or"},
+		{"color:red", 0, "unknown field color"},
+		{"tag: vegan", 0, "missing value for field tag"},
+		{`ingr:"peanut`, 5, "unterminated string"},
+		{`a "b`, 2, "unterminated string"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Parse(%q) error = %v, want a *SyntaxError", tt.in, err)
+			continue
+		}
+		if se.Offset != tt.offset || se.Msg != tt.msg {
+			t.Errorf("Parse(%q) error = %q at %d, want %q at %d", tt.in, se.Msg, se.Offset, tt.msg, tt.offset)
+		}
+	}
+}
+
+const curry = `# Chickpea Curry
+
+*Vegan, quick*
+
+**4 servings**
+
+---
+
+- *400 g* chickpeas
+- *1 can (400 ml)* coconut milk
+
+## Spices
+
+- *2 tsp* curry powder
+
+---
+
+Simmer everything for 20 minutes.
+`
+
+func TestMatch(t *testing.T) {
+	r, err := recipemd.Parse([]byte(curry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"vegan", true},
+		{"tag:VEGAN", true},
+		{"tag:veg", false},
+		{"dessert", false},
+		{"ingr:chickpea", true},
+		{"ingr:CURRY", true},
+		{`ingr:"peanut butter"`, false},
+		{"unit:g", true},
+		{"unit:TSP", true},
+		{"unit:cup", false},
+		{"title:curry", true},
+		{"title:soup", false},
+		{"vegan quick", true},
+		{"vegan dessert", false},
+		{"vegan or dessert", true},
+		{"not dessert", true},
+		{"not (vegan or dessert)", false},
+		{`tag:vegan and not ingr:"peanut butter"`, true},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := e.Match(r); got != tt.want {
+			t.Errorf("%s matches: %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTerm
+)
+
+type token struct {
+	kind   tokenKind
+	offset int
+	term   Term
+}
+
+// Parse parses a filter expression. Errors are of type *SyntaxError.
+func Parse(s string) (Expr, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &SyntaxError{Offset: t.offset, Msg: "unexpected " + describe(t)}
+	}
+	return e, nil
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenNot, tokenLParen, tokenTerm:
+			// implicit and
+		default:
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (Expr, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenTerm:
+		return t.term, nil
+	case tokenLParen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokenRParen {
+			return nil, &SyntaxError{Offset: c.offset, Msg: "expected ) but found " + describe(c)}
+		}
+		return e, nil
+	}
+	return nil, &SyntaxError{Offset: t.offset, Msg: "expected a term but found " + describe(t)}
+}
+
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenLParen:
+		return "("
+	case tokenRParen:
+		return ")"
+	case tokenAnd:
+		return "and"
+	case tokenOr:
+		return "or"
+	case tokenNot:
+		return "not"
+	}
+	return t.term.String()
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for {
+		for i < len(s) {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			i += size
+		}
+		if i == len(s) {
+			return append(tokens, token{kind: tokenEOF, offset: i}), nil
+		}
+		start := i
+		switch s[i] {
+		case '(':
+			tokens = append(tokens, token{kind: tokenLParen, offset: i})
+			i++
+			continue
+		case ')':
+			tokens = append(tokens, token{kind: tokenRParen, offset: i})
+			i++
+			continue
+		case '"':
+			value, n, err := lexQuoted(s, i)
+			if err != nil {
+				return nil, err
+			}
+			i += n
+			tokens = append(tokens, token{kind: tokenTerm, offset: start, term: Term{Field: FieldTag, Value: value}})
+			continue
+		}
+		word := lexWord(s[i:])
+		i += len(word)
+		switch strings.ToLower(word) {
+		case "and":
+			tokens = append(tokens, token{kind: tokenAnd, offset: start})
+			continue
+		case "or":
+			tokens = append(tokens, token{kind: tokenOr, offset: start})
+			continue
+		case "not":
+			tokens = append(tokens, token{kind: tokenNot, offset: start})
+			continue
+		}
+		term := Term{Field: FieldTag, Value: word}
+		if prefix, value, ok := strings.Cut(word, ":"); ok {
+			field, known := fields[strings.ToLower(prefix)]
+			if !known {
+				return nil, &SyntaxError{Offset: start, Msg: "unknown field " + prefix}
+			}
+			term = Term{Field: field, Value: value}
+			if value == "" && i < len(s) && s[i] == '"' {
+				quoted, n, err := lexQuoted(s, i)
+				if err != nil {
+					return nil, err
+				}
+				i += n
+				term.Value = quoted
+			}
+			if term.Value == "" {
+				return nil, &SyntaxError{Offset: start, Msg: "missing value for field " + prefix}
+			}
+		}
+		tokens = append(tokens, token{kind: tokenTerm, offset: start, term: term})
+	}
+}
+
+var fields = map[string]Field{
+	"tag":        FieldTag,
+	"ingr":       FieldIngredient,
+	"ingredient": FieldIngredient,
+	"unit":       FieldUnit,
+	"title":      FieldTitle,
+	"diet":       FieldDiet,
+	"allergen":   FieldAllergen,
+	"difficulty": FieldDifficulty,
+}
+
+// lexWord returns the unquoted word at the start of s.
+func lexWord(s string) string {
+	for i, r := range s {
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// lexQuoted reads the double-quoted string starting at s[start] and returns
+// its unescaped value and length.
+func lexQuoted(s string, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), i + 1 - start, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, &SyntaxError{Offset: start, Msg: "unterminated string"}
+}
@@ -0,0 +1,206 @@
+package nutrition
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+// FoodDataCentral is a Database backed by the USDA FoodData Central API,
+// https://fdc.nal.usda.gov/api-guide. A name is looked up by a search
+// among the Foundation and SR Legacy foods, whose nutrients are given per
+// 100 grams, taking the best match. Results are kept for the lifetime of
+// the value, which is safe for concurrent use.
+type FoodDataCentral struct {
+	APIKey  string       // "DEMO_KEY" works for a few requests an hour
+	BaseURL string       // defaults to https://api.nal.usda.gov/fdc/v1
+	Client  *http.Client // defaults to http.DefaultClient
+
+	mu    sync.Mutex
+	cache map[string]*Food
+}
+
+// fdcNutrients are the FoodData Central nutrient numbers of Nutrients.
+var fdcNutrients = map[string]func(*Nutrients) *float64{
+	"208": func(n *Nutrients) *float64 { return &n.Calories },
+	"204": func(n *Nutrients) *float64 { return &n.Fat },
+	"606": func(n *Nutrients) *float64 { return &n.SaturatedFat },
+	"205": func(n *Nutrients) *float64 { return &n.Carbohydrates },
+	"269": func(n *Nutrients) *float64 { return &n.Sugar },
+	"291": func(n *Nutrients) *float64 { return &n.Fiber },
+	"203": func(n *Nutrients) *float64 { return &n.Protein },
+	"307": func(n *Nutrients) *float64 { return &n.Sodium },
+}
+
+// fdcEnergy are the numbers of the Atwater energy values of Foundation
+// foods, used if a food has no energy value "208".
+var fdcEnergy = []string{"957", "958"}
+
+// pieceWords are the portion names FoodData Central uses for a whole
+// piece of a food.
+var pieceWords = map[string]bool{
+	"each": true, "fruit": true, "item": true, "large": true, "medium": true,
+	"piece": true, "small": true, "whole": true,
+}
+
+// fdcFood is the part of a food of the FoodData Central API that is used.
+type fdcFood struct {
+	FDCID         int    `json:"fdcId"`
+	Description   string `json:"description"`
+	FoodNutrients []struct {
+		Nutrient struct {
+			Number string `json:"number"`
+		} `json:"nutrient"`
+		Amount float64 `json:"amount"`
+	} `json:"foodNutrients"`
+	FoodPortions []struct {
+		Amount      float64 `json:"amount"`
+		GramWeight  float64 `json:"gramWeight"`
+		Modifier    string  `json:"modifier"`
+		MeasureUnit struct {
+			Name string `json:"name"`
+		} `json:"measureUnit"`
+	} `json:"foodPortions"`
+}
+
+// Lookup implements Database.
+func (d *FoodDataCentral) Lookup(ctx context.Context, name string) (*Food, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	d.mu.Lock()
+	f, ok := d.cache[key]
+	d.mu.Unlock()
+	if ok {
+		if f == nil {
+			return nil, ErrNotFound
+		}
+		return f, nil
+	}
+	f, err := d.lookup(ctx, key)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	d.mu.Lock()
+	if d.cache == nil {
+		d.cache = make(map[string]*Food)
+	}
+	d.cache[key] = f
+	d.mu.Unlock()
+	return f, err
+}
+
+func (d *FoodDataCentral) lookup(ctx context.Context, name string) (*Food, error) {
+	var found struct {
+		Foods []struct {
+			FDCID int `json:"fdcId"`
+		} `json:"foods"`
+	}
+	q := url.Values{"query": {name}, "dataType": {"Foundation,SR Legacy"}, "pageSize": {"1"}}
+	if err := d.get(ctx, "/foods/search", q, &found); err != nil {
+		return nil, err
+	}
+	if len(found.Foods) == 0 {
+		return nil, ErrNotFound
+	}
+	var food fdcFood
+	if err := d.get(ctx, "/food/"+strconv.Itoa(found.Foods[0].FDCID), nil, &food); err != nil {
+		return nil, err
+	}
+	return food.food(), nil
+}
+
+// get decodes the JSON response to a GET request of the API path p into v.
+func (d *FoodDataCentral) get(ctx context.Context, p string, q url.Values, v any) error {
+	base := d.BaseURL
+	if base == "" {
+		base = "https://api.nal.usda.gov/fdc/v1"
+	}
+	if q == nil {
+		q = url.Values{}
+	}
+	q.Set("api_key", d.APIKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(base, "/")+p+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("nutrition: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nutrition: FoodData Central %s: %s", p, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("nutrition: FoodData Central %s: %w", p, err)
+	}
+	return nil
+}
+
+// food converts f to a Food. Portions measured in a known volume unit
+// give the density; the others are named by their unit or, for foods
+// whose unit is "undetermined", the first word of their modifier, as in
+// "cup, sliced" or "fruit, without skin and seed".
+func (f *fdcFood) food() *Food {
+	food := &Food{Name: f.Description, Portions: make(map[string]float64)}
+	values := make(map[string]float64)
+	for _, n := range f.FoodNutrients {
+		values[n.Nutrient.Number] = n.Amount
+	}
+	for number, field := range fdcNutrients {
+		*field(&food.Per100g) = values[number]
+	}
+	for _, number := range fdcEnergy {
+		if food.Per100g.Calories == 0 {
+			food.Per100g.Calories = values[number]
+		}
+	}
+	for _, p := range f.FoodPortions {
+		if p.Amount <= 0 || p.GramWeight <= 0 {
+			continue
+		}
+		unit := strings.ToLower(p.MeasureUnit.Name)
+		if unit == "" || unit == "undetermined" {
+			unit = firstWord(p.Modifier)
+		}
+		grams := p.GramWeight / p.Amount
+		if r, ok := units.Ratio(unit, "ml"); ok {
+			if food.Density == 0 {
+				ml, _ := r.Float64()
+				food.Density = grams / ml
+			}
+			continue
+		}
+		unit = units.Singular(unit)
+		if pieceWords[unit] {
+			unit = ""
+		}
+		if _, ok := food.Portions[unit]; !ok {
+			food.Portions[unit] = grams
+		}
+	}
+	return food
+}
+
+// firstWord returns the first word of s in lower case.
+func firstWord(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
@@ -0,0 +1,64 @@
+package nutrition
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// recipeStart is the end of the start tag of the element of a recipe in
+// the HTML of package extension.
+const recipeStart = `itemtype="https://schema.org/Recipe">`
+
+// HTML returns a table of the nutrients of one unit of the first yield,
+// or of the whole recipe if it has none, marked up as a schema.org
+// NutritionInformation for the nutrition property of a recipe.
+func (r *Report) HTML() string {
+	n, serving := r.Total, ""
+	if len(r.PerYield) > 0 {
+		n, serving = r.PerYield[0].Nutrients, "1 "+r.PerYield[0].Unit
+	}
+	var b strings.Builder
+	b.WriteString(`<table class="nutrition" itemprop="nutrition" itemscope itemtype="https://schema.org/NutritionInformation">` + "\n")
+	if serving != "" {
+		fmt.Fprintf(&b, `<caption>Per <span itemprop="servingSize">%s</span></caption>`+"\n", html.EscapeString(serving))
+	} else {
+		b.WriteString("<caption>Per recipe</caption>\n")
+	}
+	row := func(label, prop string, v float64, unit string) {
+		fmt.Fprintf(&b, `<tr><th>%s</th><td itemprop="%s">%.0f %s</td></tr>`+"\n", label, prop, v, unit)
+	}
+	row("Calories", "calories", n.Calories, "calories")
+	row("Fat", "fatContent", n.Fat, "g")
+	row("Saturated fat", "saturatedFatContent", n.SaturatedFat, "g")
+	row("Carbohydrates", "carbohydrateContent", n.Carbohydrates, "g")
+	row("Sugar", "sugarContent", n.Sugar, "g")
+	row("Fiber", "fiberContent", n.Fiber, "g")
+	row("Protein", "proteinContent", n.Protein, "g")
+	row("Sodium", "sodiumContent", n.Sodium, "mg")
+	b.WriteString("</table>\n")
+	return b.String()
+}
+
+// Insert returns a post processor that adds the HTML of rep at the start
+// of the first recipe of a page, so it becomes the recipe's nutrition
+// property. Pages without a recipe are returned as they are.
+func Insert(rep *Report) recipemd.PostProcessor {
+	return func(data []byte) ([]byte, error) {
+		i := bytes.Index(data, []byte(recipeStart))
+		if i < 0 {
+			return data, nil
+		}
+		i += len(recipeStart)
+		if i < len(data) && data[i] == '\n' {
+			i++
+		}
+		out := make([]byte, 0, len(data)+1024)
+		out = append(out, data[:i]...)
+		out = append(out, rep.HTML()...)
+		return append(out, data[i:]...), nil
+	}
+}
@@ -0,0 +1,220 @@
+// Package nutrition estimates the calories and macronutrients of recipes
+// from a food database.
+//
+// A Database looks up foods by ingredient name. FoodDataCentral queries
+// the USDA FoodData Central API; Table holds foods given by the user, for
+// example decoded from a JSON file. Estimate converts the amount of each
+// ingredient to grams and adds up the nutrients, for the whole recipe and
+// per unit of each yield.
+package nutrition
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+// ErrNotFound is returned by Database.Lookup for unknown foods.
+var ErrNotFound = errors.New("nutrition: food not found")
+
+// Database looks up foods by the name of an ingredient.
+type Database interface {
+	Lookup(ctx context.Context, name string) (*Food, error)
+}
+
+// Nutrients are the energy and nutrient contents of an amount of food:
+// calories in kcal, sodium in milligrams and the others in grams.
+type Nutrients struct {
+	Calories      float64 `json:"calories"`
+	Fat           float64 `json:"fat"`
+	SaturatedFat  float64 `json:"saturated_fat"`
+	Carbohydrates float64 `json:"carbohydrates"`
+	Sugar         float64 `json:"sugar"`
+	Fiber         float64 `json:"fiber"`
+	Protein       float64 `json:"protein"`
+	Sodium        float64 `json:"sodium"`
+}
+
+// MarshalJSON encodes n with the values rounded to two decimals.
+func (n Nutrients) MarshalJSON() ([]byte, error) {
+	type plain Nutrients
+	r := func(v float64) float64 { return math.Round(v*100) / 100 }
+	return json.Marshal(plain{
+		Calories:      r(n.Calories),
+		Fat:           r(n.Fat),
+		SaturatedFat:  r(n.SaturatedFat),
+		Carbohydrates: r(n.Carbohydrates),
+		Sugar:         r(n.Sugar),
+		Fiber:         r(n.Fiber),
+		Protein:       r(n.Protein),
+		Sodium:        r(n.Sodium),
+	})
+}
+
+// scale returns n multiplied by f.
+func (n Nutrients) scale(f float64) Nutrients {
+	return Nutrients{
+		Calories:      n.Calories * f,
+		Fat:           n.Fat * f,
+		SaturatedFat:  n.SaturatedFat * f,
+		Carbohydrates: n.Carbohydrates * f,
+		Sugar:         n.Sugar * f,
+		Fiber:         n.Fiber * f,
+		Protein:       n.Protein * f,
+		Sodium:        n.Sodium * f,
+	}
+}
+
+// add returns the sum of n and o.
+func (n Nutrients) add(o Nutrients) Nutrients {
+	return Nutrients{
+		Calories:      n.Calories + o.Calories,
+		Fat:           n.Fat + o.Fat,
+		SaturatedFat:  n.SaturatedFat + o.SaturatedFat,
+		Carbohydrates: n.Carbohydrates + o.Carbohydrates,
+		Sugar:         n.Sugar + o.Sugar,
+		Fiber:         n.Fiber + o.Fiber,
+		Protein:       n.Protein + o.Protein,
+		Sodium:        n.Sodium + o.Sodium,
+	}
+}
+
+// Food is an entry of a food database. Per100g are the nutrients of 100
+// grams. Density is the weight of a milliliter in grams, zero if unknown,
+// and Portions the weight in grams of one of a unit, such as "slice", by
+// its singular spelling; the empty unit is the weight of one piece, for
+// amounts like "2 eggs".
+type Food struct {
+	Name     string             `json:"name"`
+	Per100g  Nutrients          `json:"per_100g"`
+	Density  float64            `json:"density,omitempty"`
+	Portions map[string]float64 `json:"portions,omitempty"`
+}
+
+// grams returns the weight of the amount a of f, or false if it cannot
+// be told.
+func (f *Food) grams(a recipemd.Amount) (float64, bool) {
+	if a.Factor == nil {
+		return 0, false
+	}
+	if a.Size != nil {
+		// "2 cans (400 g)"
+		per, ok := f.grams(*a.Size)
+		if !ok {
+			return 0, false
+		}
+		n, _ := a.Factor.Float64()
+		return n * per, true
+	}
+	n, _ := a.Factor.Float64()
+	if r, ok := units.Ratio(a.Unit, "g"); ok {
+		g, _ := new(big.Rat).Mul(a.Factor, r).Float64()
+		return g, true
+	}
+	if g, ok := f.Portions[strings.ToLower(units.Singular(a.Unit))]; ok {
+		return n * g, true
+	}
+	if r, ok := units.Ratio(a.Unit, "ml"); ok && f.Density > 0 {
+		ml, _ := new(big.Rat).Mul(a.Factor, r).Float64()
+		return ml * f.Density, true
+	}
+	return 0, false
+}
+
+// Table is a Database of foods by name. Lookups ignore case and the
+// plural "s" of a name.
+type Table map[string]Food
+
+// Lookup implements Database.
+func (t Table) Lookup(ctx context.Context, name string) (*Food, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	for _, k := range []string{key, strings.TrimSuffix(key, "s"), strings.TrimSuffix(key, "es")} {
+		for name, f := range t {
+			if strings.EqualFold(name, k) {
+				if f.Name == "" {
+					f.Name = name
+				}
+				return &f, nil
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Report is the estimate of the nutrients of a recipe.
+type Report struct {
+	Total       Nutrients    `json:"total"`
+	PerYield    []YieldShare `json:"per_yield,omitempty"`
+	Ingredients []Share      `json:"ingredients"`
+	Missing     []string     `json:"missing,omitempty"` // ingredients not counted
+}
+
+// YieldShare is the share of one unit of a yield, such as "serving" for
+// the yield "4 servings".
+type YieldShare struct {
+	Unit      string    `json:"unit"`
+	Nutrients Nutrients `json:"nutrients"`
+}
+
+// Share is the share of an ingredient: the food it was matched with, its
+// weight and nutrients.
+type Share struct {
+	Name      string    `json:"name"`
+	Food      string    `json:"food"`
+	Grams     float64   `json:"grams"`
+	Nutrients Nutrients `json:"nutrients"`
+}
+
+// Estimate looks up the ingredients of r in db and adds up their
+// nutrients. Ingredients without an amount, unknown to db or whose amount
+// cannot be converted to grams are listed in Report.Missing; the estimate
+// is low by their share. Errors of db other than ErrNotFound end the
+// estimate.
+func Estimate(ctx context.Context, db Database, r *recipemd.Recipe) (*Report, error) {
+	rep := &Report{Ingredients: []Share{}}
+	foods := make(map[string]*Food)
+	for _, in := range r.AllIngredients() {
+		key := strings.ToLower(in.Name)
+		f, ok := foods[key]
+		if !ok {
+			var err error
+			f, err = db.Lookup(ctx, in.Name)
+			if errors.Is(err, ErrNotFound) {
+				f = nil
+			} else if err != nil {
+				return nil, err
+			}
+			foods[key] = f
+		}
+		if f == nil || in.Amount == nil {
+			rep.Missing = append(rep.Missing, in.Name)
+			continue
+		}
+		g, ok := f.grams(*in.Amount)
+		if !ok {
+			rep.Missing = append(rep.Missing, in.Name)
+			continue
+		}
+		n := f.Per100g.scale(g / 100)
+		rep.Ingredients = append(rep.Ingredients, Share{Name: in.Name, Food: f.Name, Grams: math.Round(g*100) / 100, Nutrients: n})
+		rep.Total = rep.Total.add(n)
+	}
+	for _, y := range r.Yields {
+		if y.Factor == nil || y.Factor.Sign() <= 0 {
+			continue
+		}
+		n, _ := y.Factor.Float64()
+		unit := units.Singular(y.Unit)
+		if unit == "" {
+			unit = "piece"
+		}
+		rep.PerYield = append(rep.PerYield, YieldShare{Unit: unit, Nutrients: rep.Total.scale(1 / n)})
+	}
+	return rep, nil
+}
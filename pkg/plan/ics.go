@@ -0,0 +1,238 @@
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICSOption configures WriteICS.
+type ICSOption func(*icsConfig)
+
+type icsConfig struct {
+	base     *url.URL
+	stamp    time.Time
+	location *time.Location
+	lang     string
+}
+
+// WithSite links the events of relative links to the pages of the recipes
+// on the site at base, as built by pkg/site from the directory of the
+// plan: "pizza.md" becomes base + "pizza.html". Without it, only links
+// that are absolute URLs are linked.
+func WithSite(base *url.URL) ICSOption {
+	return func(c *icsConfig) {
+		c.base = base
+	}
+}
+
+// WithStamp sets the time the calendar was created, which every event
+// carries, instead of the current time. Fixed stamps make the output
+// reproducible.
+func WithStamp(t time.Time) ICSOption {
+	return func(c *icsConfig) {
+		c.stamp = t
+	}
+}
+
+// WithLocation places the meals on the days of the plan in loc, the time
+// zone of the household: events start and end at the midnights of the day
+// there, so that a calendar read in another time zone shows the meal at
+// the same moment, not on the same date. Without it, events are all-day
+// events on floating dates. DTSTAMP is in UTC either way, as RFC 5545
+// requires.
+func WithLocation(loc *time.Location) ICSOption {
+	return func(c *icsConfig) {
+		c.location = loc
+	}
+}
+
+// WithLocale labels the calendar in lang, a BCP 47 language tag such as
+// "de", with the Labels of DefaultLabels: it names a plan without a title,
+// labels the recipe link in the description of events and writes amounts
+// with the decimal separator of lang. Summaries and descriptions carry
+// lang as their LANGUAGE. Without it, the calendar has no labels of its
+// own and amounts use a decimal point.
+func WithLocale(lang string) ICSOption {
+	return func(c *icsConfig) {
+		c.lang = lang
+	}
+}
+
+// Labels are the words WriteICS adds to a calendar.
+type Labels struct {
+	Plan    string // name of a calendar whose plan has no title
+	Recipe  string // labels the link to the recipe of a meal
+	Decimal string // separates the fraction of decimal amounts
+}
+
+// DefaultLabels are the labels of the languages WithLocale knows, by
+// lowercase BCP 47 language tag. A tag without labels of its own falls
+// back to its language, "pt-BR" to "pt", and an unknown language to
+// English.
+var DefaultLabels = map[string]Labels{
+	"en": {Plan: "Meal plan", Recipe: "Recipe", Decimal: "."},
+	"de": {Plan: "Essensplan", Recipe: "Rezept", Decimal: ","},
+	"fr": {Plan: "Menu de la semaine", Recipe: "Recette", Decimal: ","},
+	"es": {Plan: "Menú semanal", Recipe: "Receta", Decimal: ","},
+	"it": {Plan: "Menù settimanale", Recipe: "Ricetta", Decimal: ","},
+	"nl": {Plan: "Weekmenu", Recipe: "Recept", Decimal: ","},
+	"pt": {Plan: "Cardápio", Recipe: "Receita", Decimal: ","},
+}
+
+func labelsFor(lang string) Labels {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	if l, ok := DefaultLabels[lang]; ok {
+		return l
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	if l, ok := DefaultLabels[base]; ok {
+		return l
+	}
+	return DefaultLabels["en"]
+}
+
+var decimalPoint = regexp.MustCompile(`(\d)\.(\d)`)
+
+// WriteICS writes meals to w as an iCalendar (RFC 5545) calendar named
+// after the plan, with an event per meal on its day titled with the name and
+// title of the meal and linking to its recipe. Events keep their UID as
+// long as the date, name and link of the meal stay the same, so calendars
+// that subscribe to the file update them rather than adding copies.
+func (p *Plan) WriteICS(w io.Writer, meals []Meal, opts ...ICSOption) error {
+	c := icsConfig{stamp: time.Now()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	var b strings.Builder
+	line := func(name, value string) {
+		writeFolded(&b, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//recipemd-go//meal plan//EN")
+	line("CALSCALE", "GREGORIAN")
+	labels := labelsFor(c.lang)
+	if p.Title != "" {
+		line("X-WR-CALNAME", icsText(p.Title))
+	} else if c.lang != "" {
+		line("X-WR-CALNAME", icsText(labels.Plan))
+	}
+	if c.location != nil {
+		line("X-WR-TIMEZONE", c.location.String())
+	}
+	text := ""
+	if c.lang != "" {
+		text = ";LANGUAGE=" + c.lang
+	}
+	stamp := c.stamp.UTC().Format("20060102T150405Z")
+	seen := make(map[string]int)
+	for _, m := range meals {
+		key := m.uid()
+		uid := key
+		if n := seen[key]; n > 0 {
+			// the same recipe twice in a meal
+			uid += "-" + strconv.Itoa(n)
+		}
+		seen[key]++
+		summary := m.Title
+		if m.Amount != nil {
+			a := m.Amount.String()
+			if labels.Decimal != "." {
+				a = decimalPoint.ReplaceAllString(a, "${1}"+labels.Decimal+"${2}")
+			}
+			summary = a + " " + summary
+		}
+		if m.Name != "" {
+			summary = m.Name + ": " + summary
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", uid+"@recipemd")
+		line("DTSTAMP", stamp)
+		if c.location != nil {
+			y, mo, d := m.Date.Date()
+			start := time.Date(y, mo, d, 0, 0, 0, 0, c.location)
+			line("DTSTART", start.UTC().Format("20060102T150405Z"))
+			line("DTEND", start.AddDate(0, 0, 1).UTC().Format("20060102T150405Z"))
+		} else {
+			line("DTSTART;VALUE=DATE", m.Date.Format("20060102"))
+			line("DTEND;VALUE=DATE", m.Date.AddDate(0, 0, 1).Format("20060102"))
+		}
+		line("SUMMARY"+text, icsText(summary))
+		if m.Link != "" {
+			u := c.link(m.Link)
+			if u != "" {
+				line("URL", u)
+			} else {
+				u = m.Link
+			}
+			if c.lang != "" {
+				u = labels.Recipe + ": " + u
+			}
+			line("DESCRIPTION"+text, icsText(u))
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// uid returns an identifier of m derived from its date, name and link, or
+// its title if it has no link.
+func (m Meal) uid() string {
+	target := m.Link
+	if target == "" {
+		target = m.Title
+	}
+	h := sha256.Sum256([]byte(m.Date.Format(time.DateOnly) + "\x00" + m.Name + "\x00" + target))
+	return hex.EncodeToString(h[:16])
+}
+
+// link returns the URL of the recipe link points to, or "" if it has none.
+func (c *icsConfig) link(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	if u.IsAbs() {
+		return u.String()
+	}
+	if c.base == nil || u.Path == "" || path.IsAbs(u.Path) {
+		return ""
+	}
+	if strings.EqualFold(path.Ext(u.Path), ".md") {
+		u.Path = strings.TrimSuffix(u.Path, path.Ext(u.Path)) + ".html"
+	}
+	return c.base.ResolveReference(u).String()
+}
+
+// icsText escapes s as an iCalendar TEXT value.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes the content line l to b, folded into lines of at most
+// 75 octets without splitting UTF-8 sequences, and ended with CRLF.
+func writeFolded(b *strings.Builder, l string) {
+	limit := 75
+	for len(l) > limit {
+		i := limit
+		for i > 0 && l[i]&0xC0 == 0x80 {
+			i--
+		}
+		b.WriteString(l[:i])
+		b.WriteString("\r\n ")
+		l = l[i:]
+		limit = 74 // the leading space counts
+	}
+	b.WriteString(l)
+	b.WriteString("\r\n")
+}
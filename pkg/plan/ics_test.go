@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const week = `# Week 12
+
+## Monday 2026-03-16
+
+### Dinner
+
+- *1.5* [Pizza](pizza.md)
+
+## Tuesday 2026-03-17
+
+- Leftovers
+`
+
+func TestWriteICS(t *testing.T) {
+	p, err := Parse([]byte(week))
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	base, _ := url.Parse("https://x.org/")
+	stamp := WithStamp(time.Date(2026, 3, 1, 12, 0, 0, 0, berlin))
+	tests := []struct {
+		name    string
+		opts    []ICSOption
+		want    []string
+		notWant []string
+	}{
+		{
+			"default",
+			nil,
+			[]string{
+				"X-WR-CALNAME:Week 12\r\n",
+				"DTSTAMP:20260301T110000Z\r\n",
+				"DTSTART;VALUE=DATE:20260316\r\n",
+				"DTEND;VALUE=DATE:20260317\r\n",
+				"SUMMARY:Dinner: 1.5 Pizza\r\n",
+				"DESCRIPTION:pizza.md\r\n",
+				"SUMMARY:Leftovers\r\n",
+			},
+			[]string{"X-WR-TIMEZONE", "LANGUAGE"},
+		},
+		{
+			"site",
+			[]ICSOption{WithSite(base)},
+			[]string{"URL:https://x.org/pizza.html\r\n", "DESCRIPTION:https://x.org/pizza.html\r\n"},
+			nil,
+		},
+		{
+			"location",
+			[]ICSOption{WithLocation(berlin)},
+			[]string{
+				"X-WR-TIMEZONE:Europe/Berlin\r\n",
+				"DTSTAMP:20260301T110000Z\r\n",
+				"DTSTART:20260315T230000Z\r\n",
+				"DTEND:20260316T230000Z\r\n",
+			},
+			[]string{"VALUE=DATE"},
+		},
+		{
+			"locale",
+			[]ICSOption{WithLocale("de-AT"), WithSite(base)},
+			[]string{
+				"X-WR-CALNAME:Week 12\r\n",
+				"SUMMARY;LANGUAGE=de-AT:Dinner: 1\\,5 Pizza\r\n",
+				"DESCRIPTION;LANGUAGE=de-AT:Rezept: https://x.org/pizza.html\r\n",
+			},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := p.WriteICS(&b, p.Meals, append([]ICSOption{stamp}, tt.opts...)...); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(b.String(), w) {
+					t.Errorf("calendar does not contain %q:\n%s", w, &b)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(b.String(), w) {
+					t.Errorf("calendar contains %q:\n%s", w, &b)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteICSUntitled(t *testing.T) {
+	p := &Plan{}
+	var b strings.Builder
+	if err := p.WriteICS(&b, nil, WithLocale("fr")); err != nil {
+		t.Fatal(err)
+	}
+	if want := "X-WR-CALNAME:Menu de la semaine\r\n"; !strings.Contains(b.String(), want) {
+		t.Errorf("calendar does not contain %q:\n%s", want, &b)
+	}
+}
@@ -0,0 +1,205 @@
+// Package plan parses meal plans: markdown documents that list the recipes
+// to cook on each day.
+//
+// A plan is an optional title heading followed by a level-two heading per
+// day, containing the date as YYYY-MM-DD anywhere in its text, and a list
+// of the meals of that day. Level-three headings name the meals listed
+// under them, such as lunch and dinner. A list item is a meal: a link to a
+// recipe or a plain text, such as "Leftovers", optionally preceded by an
+// emphasized amount, as for ingredients, to cook more or less than one
+// batch of the recipe:
+//
+//	# Week 12
+//
+//	## Monday 2026-03-16
+//
+//	### Dinner
+//
+//	- *2* [Pizza](pizza.md)
+//	- [Green salad](salad.md)
+//
+//	## Tuesday 2026-03-17
+//
+//	- *4 servings* [Lentil soup](soup.md)
+//	- Leftovers
+//
+// The meals of a plan can be combined into a shopping list or written as
+// an iCalendar file.
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// Plan is a parsed meal plan.
+type Plan struct {
+	Title string `json:"title,omitempty"`
+	Meals []Meal `json:"meals"` // in document order
+}
+
+// Meal is a meal of a plan. Title is the text of the link, or the whole
+// text of meals that are not links. Amount is nil if the meal gives no
+// amount and is then one batch of the recipe.
+type Meal struct {
+	Date   time.Time // midnight UTC of the day
+	Name   string    // of the level-three heading above the meal, if any
+	Title  string
+	Link   string
+	Amount *recipemd.Amount
+}
+
+var dateRe = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`)
+
+var plain = goldmark.New()
+
+// Parse parses the meal plan in source. Meals must come after the heading
+// of their day.
+func Parse(source []byte) (*Plan, error) {
+	doc := plain.Parser().Parse(text.NewReader(source))
+	p := &Plan{}
+	var day time.Time
+	var name string
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		switch n := n.(type) {
+		case *gast.Heading:
+			title := extension.PlainText(n, source)
+			switch n.Level {
+			case 1:
+				if p.Title == "" && day.IsZero() {
+					p.Title = title
+				}
+			case 2:
+				d := dateRe.FindString(title)
+				t, err := time.Parse(time.DateOnly, d)
+				if err != nil {
+					return nil, fmt.Errorf("plan: line %d: no valid date in heading %q", line(n, source), title)
+				}
+				day, name = t, ""
+			case 3:
+				name = title
+			}
+		case *gast.List:
+			for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+				if day.IsZero() {
+					return nil, fmt.Errorf("plan: line %d: meal before the first date", line(item, source))
+				}
+				m := meal(item, source)
+				m.Date, m.Name = day, name
+				p.Meals = append(p.Meals, m)
+			}
+		}
+	}
+	return p, nil
+}
+
+// meal returns the meal of the list item.
+func meal(item gast.Node, source []byte) Meal {
+	var m Meal
+	block := item.FirstChild()
+	if block == nil {
+		return m
+	}
+	c := block.FirstChild()
+	skip := ""
+	if e, ok := c.(*gast.Emphasis); ok {
+		skip = extension.PlainText(e, source)
+		if a := amount.Parse(skip); a.Factor != nil {
+			m.Amount = &a
+			c = e.NextSibling()
+		}
+	}
+	for ; c != nil; c = c.NextSibling() {
+		if l, ok := c.(*gast.Link); ok {
+			m.Title = extension.PlainText(l, source)
+			m.Link = string(l.Destination)
+			return m
+		}
+	}
+	m.Title = extension.PlainText(block, source)
+	if m.Amount != nil {
+		m.Title = strings.TrimSpace(strings.TrimPrefix(m.Title, skip))
+	}
+	return m
+}
+
+// line returns the line of source on which the block n starts.
+func line(n gast.Node, source []byte) int {
+	for ; n != nil; n = n.FirstChild() {
+		if n.Lines().Len() > 0 {
+			return bytes.Count(source[:n.Lines().At(0).Start], []byte("\n")) + 1
+		}
+	}
+	return 0
+}
+
+// Between returns the meals of p on the days from from up to, but not
+// including, to. A zero to has no end.
+func (p *Plan) Between(from, to time.Time) []Meal {
+	var meals []Meal
+	for _, m := range p.Meals {
+		if !m.Date.Before(from) && (to.IsZero() || m.Date.Before(to)) {
+			meals = append(meals, m)
+		}
+	}
+	return meals
+}
+
+// recipe returns a recipe with an ingredient per meal of meals linking to
+// a recipe, the link and amount of the meal, so the recipes of the meals
+// are its sub-recipes.
+func recipe(meals []Meal) *recipemd.Recipe {
+	r := &recipemd.Recipe{}
+	for _, m := range meals {
+		if m.Link != "" {
+			r.Ingredients = append(r.Ingredients, recipemd.Ingredient{Name: m.Title, Link: m.Link, Amount: m.Amount})
+		}
+	}
+	return r
+}
+
+// ShoppingList returns the combined shopping list of meals of the plan at
+// base: the ingredients of the recipes the meals link to, scaled to the
+// amounts of the meals, and of the recipes their ingredients link to, as
+// resolved by resolve. Meals that are not recipes are left out.
+func ShoppingList(meals []Meal, base string, resolve recipemd.Resolver) (*recipemd.ShoppingList, error) {
+	subs, err := recipe(meals).SubRecipes(base, resolve)
+	if err != nil {
+		return nil, err
+	}
+	var list recipemd.ShoppingList
+	for _, s := range subs {
+		for _, in := range s.Recipe.FullIngredients(s.SubRecipes) {
+			list.AddIngredient(in)
+		}
+	}
+	return &list, nil
+}
+
+// MarshalJSON encodes m with its date as YYYY-MM-DD and its amount as
+// written in plans.
+func (m Meal) MarshalJSON() ([]byte, error) {
+	var a string
+	if m.Amount != nil {
+		a = m.Amount.String()
+	}
+	return json.Marshal(struct {
+		Date   string `json:"date"`
+		Name   string `json:"name,omitempty"`
+		Title  string `json:"title"`
+		Link   string `json:"link,omitempty"`
+		Amount string `json:"amount,omitempty"`
+	}{m.Date.Format(time.DateOnly), m.Name, m.Title, m.Link, a})
+}
@@ -0,0 +1,60 @@
+package qr
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// quietZone is the width in modules of the light border a code needs
+// around it to scan.
+const quietZone = 4
+
+// Image returns the code as a black and white image with scale pixels per
+// module, including the quiet zone.
+func (c *Code) Image(scale int) image.Image {
+	scale = max(scale, 1)
+	n := (c.Size + 2*quietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, n, n), color.Palette{color.White, color.Black})
+	for y := range n {
+		for x := range n {
+			if c.Dark(x/scale-quietZone, y/scale-quietZone) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the code as a PNG image with scale pixels per module.
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, c.Image(scale))
+}
+
+// WriteSVG writes the code as an SVG image one unit per module, which
+// scales to any size without blurring. Each row of dark modules is one
+// path segment per run.
+func (c *Code) WriteSVG(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	n := c.Size + 2*quietZone
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", n, n)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", n, n)
+	b.WriteString(`<path fill="#000" d="`)
+	for y := range c.Size {
+		for x := 0; x < c.Size; x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			start := x
+			for x < c.Size && c.Dark(x, y) {
+				x++
+			}
+			fmt.Fprintf(b, "M%d %dh%dv1h-%dz", start+quietZone, y+quietZone, x-start, x-start)
+		}
+	}
+	b.WriteString("\"/>\n</svg>\n")
+	return b.Flush()
+}
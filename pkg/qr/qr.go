@@ -0,0 +1,452 @@
+// Package qr encodes data as QR codes and writes them as PNG or SVG
+// images, for printed recipe cards that link back to a recipe online.
+//
+// Data is always encoded in byte mode, in the smallest version (size) of
+// symbol that holds it at the requested error correction level, as
+// specified by ISO/IEC 18004.
+package qr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Level is the error correction level of a code: the share of the symbol
+// that can be damaged or covered while it still scans.
+type Level int
+
+// Error correction levels.
+const (
+	Low      Level = iota // about 7%
+	Medium                // about 15%
+	Quartile              // about 25%
+	High                  // about 30%
+)
+
+// String returns the letter of l: L, M, Q or H.
+func (l Level) String() string {
+	return string("LMQH"[l])
+}
+
+// formatBits are the bits of the levels in the format information.
+var formatBits = [4]int{Low: 1, Medium: 0, Quartile: 3, High: 2}
+
+// eccPerBlock is the number of error correction codewords in each block,
+// by level and version.
+var eccPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// eccBlocks is the number of error correction blocks, by level and
+// version.
+var eccBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// ErrTooLong is returned by Encode for data that does not fit in the
+// largest QR code, version 40, at the requested level.
+var ErrTooLong = errors.New("qr: data too long")
+
+// Code is a QR code symbol: a square of Size × Size dark or light modules.
+type Code struct {
+	Version int
+	Level   Level
+	Size    int
+
+	modules  []bool // dark modules, row by row
+	function []bool // modules of the function patterns, never masked
+}
+
+// Dark reports whether the module in column x and row y is dark. Modules
+// outside the symbol are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y*c.Size+x]
+}
+
+// Encode returns the smallest QR code holding data at level l.
+func Encode(data []byte, l Level) (*Code, error) {
+	for v := 1; v <= 40; v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if len(data) >= 1<<countBits {
+			continue
+		}
+		capacity := dataCodewords(v, l) * 8
+		if 4+countBits+8*len(data) > capacity {
+			continue
+		}
+		var b bitBuffer
+		b.append(0b0100, 4) // byte mode
+		b.append(len(data), countBits)
+		for _, d := range data {
+			b.append(int(d), 8)
+		}
+		b.append(0, min(4, capacity-len(b))) // terminator
+		b.append(0, (8-len(b)%8)%8)
+		for pad := 0xEC; len(b) < capacity; pad ^= 0xEC ^ 0x11 {
+			b.append(pad, 8)
+		}
+		c := newCode(v, l)
+		c.drawCodewords(c.addECC(b.bytes()))
+		c.applyBestMask()
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: %d bytes, at most %d at level %v", ErrTooLong, len(data), (dataCodewords(40, l)*8-20)/8, l)
+}
+
+// rawModules returns the number of modules of a symbol of version v that
+// hold data or error correction bits, including the remainder bits.
+func rawModules(v int) int {
+	n := (16*v+128)*v + 64
+	if v >= 2 {
+		align := v/7 + 2
+		n -= (25*align-10)*align - 55
+		if v >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+// dataCodewords returns the number of data codewords of version v at
+// level l.
+func dataCodewords(v int, l Level) int {
+	return rawModules(v)/8 - eccPerBlock[l][v]*eccBlocks[l][v]
+}
+
+// alignmentPositions returns the centers of the alignment patterns of
+// version v along either axis.
+func alignmentPositions(v int) []int {
+	if v == 1 {
+		return nil
+	}
+	n := v/7 + 2
+	step := (v*4 + n*2 + 1) / (n*2 - 2) * 2
+	if v == 32 {
+		step = 26
+	}
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, v*4+17-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// newCode returns a symbol of version v with its function patterns drawn.
+func newCode(v int, l Level) *Code {
+	size := v*4 + 17
+	c := &Code{Version: v, Level: l, Size: size, modules: make([]bool, size*size), function: make([]bool, size*size)}
+	for i := range size {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+	for _, p := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := p[0]+dx, p[1]+dy
+				if x >= 0 && y >= 0 && x < size && y < size {
+					d := max(abs(dx), abs(dy))
+					c.set(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+	pos := alignmentPositions(v)
+	last := len(pos) - 1
+	for i, x := range pos {
+		for j, y := range pos {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue // finder patterns
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	c.drawFormat(0)
+	if v >= 7 {
+		rem := v
+		for range 12 {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := v<<12 | rem
+		for i := range 18 {
+			a, b := size-11+i%3, i/3
+			c.set(a, b, bits>>i&1 != 0)
+			c.set(b, a, bits>>i&1 != 0)
+		}
+	}
+	return c
+}
+
+// set makes the module at x, y a function module of the given color.
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y*c.Size+x] = dark
+	c.function[y*c.Size+x] = true
+}
+
+// drawFormat draws both copies of the format information for mask.
+func (c *Code) drawFormat(mask int) {
+	data := formatBits[c.Level]<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+	for i := range 6 {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+	for i := range 8 {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	c.set(8, c.Size-8, true) // the dark module
+}
+
+// addECC splits data into blocks, appends their error correction
+// codewords and returns the interleaved codewords of all blocks.
+func (c *Code) addECC(data []byte) []byte {
+	blocks, eccLen := eccBlocks[c.Level][c.Version], eccPerBlock[c.Level][c.Version]
+	raw := rawModules(c.Version) / 8
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+	divisor := rsDivisor(eccLen)
+	var all [][]byte
+	for i, k := 0, 0; i < blocks; i++ {
+		n := shortLen - eccLen
+		if i >= short {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, divisor)
+		if i < short {
+			block = append(block, 0) // placeholder, skipped below
+		}
+		all = append(all, append(block, ecc...))
+	}
+	var out []byte
+	for i := range all[0] {
+		for j, block := range all {
+			if i != shortLen-eccLen || j >= short {
+				out = append(out, block[i])
+			}
+		}
+	}
+	return out
+}
+
+// drawCodewords places data in the zigzag order of the standard, in pairs
+// of columns from the bottom right, skipping function modules.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // the vertical timing pattern
+		}
+		for vert := range c.Size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert // upward
+				}
+				if !c.function[y*c.Size+x] && i < len(data)*8 {
+					c.modules[y*c.Size+x] = data[i>>3]>>(7-i&7)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// masks are the conditions of the eight data masks.
+var masks = [8]func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+// applyMask inverts the data modules selected by mask; applying it twice
+// undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := range c.Size {
+		for x := range c.Size {
+			if !c.function[y*c.Size+x] && masks[mask](x, y) {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+// applyBestMask applies the mask with the lowest penalty.
+func (c *Code) applyBestMask() {
+	best, bestPenalty := 0, -1
+	for mask := range masks {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormat(best)
+}
+
+// penalty scores the symbol by the four rules of the standard: runs of
+// five or more modules of one color, 2×2 blocks of one color, patterns
+// that look like finder patterns and an unbalanced share of dark modules.
+func (c *Code) penalty() int {
+	p := 0
+	n := c.Size
+	finder := [2][11]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	for _, transpose := range []bool{false, true} {
+		at := func(i, j int) bool {
+			if transpose {
+				return c.modules[j*n+i]
+			}
+			return c.modules[i*n+j]
+		}
+		for i := range n {
+			run := 1
+			for j := 1; j <= n; j++ {
+				if j < n && at(i, j) == at(i, j-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					p += run - 2
+				}
+				run = 1
+			}
+			for j := 0; j+11 <= n; j++ {
+				for _, f := range finder {
+					match := true
+					for k := range 11 {
+						if at(i, j+k) != f[k] {
+							match = false
+							break
+						}
+					}
+					if match {
+						p += 40
+					}
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := range n {
+		for x := range n {
+			d := c.modules[y*n+x]
+			if d {
+				dark++
+			}
+			if x+1 < n && y+1 < n && d == c.modules[y*n+x+1] && d == c.modules[(y+1)*n+x] && d == c.modules[(y+1)*n+x+1] {
+				p += 3
+			}
+		}
+	}
+	total := n * n
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return p + k*10
+}
+
+// rsDivisor returns the generator polynomial of degree n of the
+// Reed-Solomon code, without its leading coefficient.
+func rsDivisor(n int) []byte {
+	d := make([]byte, n)
+	d[n-1] = 1
+	root := byte(1)
+	for range n {
+		for j := range d {
+			d[j] = gfMul(d[j], root)
+			if j+1 < n {
+				d[j] ^= d[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return d
+}
+
+// rsRemainder returns the error correction codewords of data.
+func rsRemainder(data, divisor []byte) []byte {
+	r := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ r[0]
+		copy(r, r[1:])
+		r[len(r)-1] = 0
+		for i, d := range divisor {
+			r[i] ^= gfMul(d, factor)
+		}
+	}
+	return r
+}
+
+// gfMul multiplies x and y in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// bitBuffer is a sequence of bits, one per element.
+type bitBuffer []bool
+
+// append appends the low n bits of v, most significant first.
+func (b *bitBuffer) append(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v>>i&1 != 0)
+	}
+}
+
+// bytes packs the bits into bytes, most significant bit first.
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, (len(b)+7)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,115 @@
+package recipemd
+
+// Allergen is a group of foods that some people must not eat, such as
+// peanuts or shellfish, recognized by ingredient names like a Diet: an
+// ingredient contains the allergen if its name contains one of the
+// Keywords as whole words, ignoring case, unless one of the Allows
+// keywords it contains is longer, as in "coconut milk" for dairy.
+//
+// Like diets, allergens are guessed from names. Ingredients such as
+// "stock" or "chocolate" can contain allergens without naming them, so
+// the guess never replaces reading the labels when cooking for someone
+// with an allergy.
+type Allergen struct {
+	Name     string
+	Keywords []string
+	Allows   []string
+}
+
+// in reports whether the ingredient name contains a.
+func (a Allergen) in(name string) bool {
+	return Diet{Excludes: a.Keywords, Allows: a.Allows}.excludes(name)
+}
+
+// dairy are the keywords of milk products.
+var dairy = []string{
+	"butter", "buttermilk", "cheese", "cream", "creme fraiche", "feta",
+	"ghee", "milk", "mozzarella", "parmesan", "quark", "ricotta",
+	"sour cream", "whey", "yoghurt", "yogurt",
+}
+
+// dairyFree are ingredients named like dairy that are not.
+var dairyFree = []string{
+	"almond butter", "almond milk", "cocoa butter", "coconut cream",
+	"coconut milk", "cream of tartar", "nut butter", "oat milk",
+	"peanut butter", "soy milk", "vegan butter", "vegan cheese",
+}
+
+// eggs are the keywords of eggs and foods made mostly of them.
+var eggs = []string{"egg", "mayonnaise", "meringue"}
+
+// DefaultAllergens are common food allergens: peanut, tree nut, dairy,
+// egg, gluten, soy, fish, shellfish and sesame.
+var DefaultAllergens = []Allergen{
+	{
+		Name:     "peanut",
+		Keywords: []string{"groundnut", "peanut"},
+	},
+	{
+		Name: "tree nut",
+		Keywords: []string{
+			"almond", "brazil nut", "cashew", "chestnut", "hazelnut",
+			"macadamia", "marzipan", "nut", "nut butter", "pecan",
+			"pine nut", "pistachio", "praline", "walnut",
+		},
+	},
+	{
+		Name:     "dairy",
+		Keywords: dairy,
+		Allows:   dairyFree,
+	},
+	{
+		Name:     "egg",
+		Keywords: eggs,
+		Allows:   []string{"vegan mayonnaise"},
+	},
+	{
+		Name:     "gluten",
+		Keywords: gluten,
+		Allows:   glutenless,
+	},
+	{
+		Name: "soy",
+		Keywords: []string{
+			"edamame", "miso", "soy", "soy sauce", "soya", "soybean",
+			"tamari", "tempeh", "tofu",
+		},
+	},
+	{
+		Name: "fish",
+		Keywords: []string{
+			"anchovy", "cod", "fish", "fish sauce", "haddock", "halibut",
+			"mackerel", "salmon", "sardine", "trout", "tuna",
+			"worcestershire",
+		},
+	},
+	{
+		Name: "shellfish",
+		Keywords: []string{
+			"clam", "crab", "crayfish", "lobster", "mussel", "oyster",
+			"oyster sauce", "prawn", "scallop", "shrimp", "squid",
+		},
+		Allows: []string{"oyster mushroom"},
+	},
+	{
+		Name:     "sesame",
+		Keywords: []string{"sesame", "tahini"},
+	},
+}
+
+// Allergens returns the names of the allergens among allergens that some
+// ingredient of r contains, in the order of allergens. The result is a
+// guess from ingredient names, see Allergen: an allergen missing from it
+// may still be in the recipe.
+func (r *Recipe) Allergens(allergens []Allergen) []string {
+	var names []string
+	for _, a := range allergens {
+		for _, in := range r.AllIngredients() {
+			if a.in(in.Name) {
+				names = append(names, a.Name)
+				break
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,50 @@
+package recipemd
+
+import (
+	"slices"
+
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+// Annotate returns a copy of r in which every ingredient amount of one of
+// the unit classes is followed by its conversion to the other unit
+// system, e.g. "1 cup (240 ml)". Without classes, all classes are
+// annotated. The annotation becomes part of the unit, so Annotate is meant
+// to be applied last, right before the recipe is written.
+func (r *Recipe) Annotate(classes ...units.Class) *Recipe {
+	if len(classes) == 0 {
+		classes = units.Classes
+	}
+	a := r.Clone()
+	annotateIngredients(a.Ingredients, classes)
+	annotateGroups(a.IngredientGroups, classes)
+	return a
+}
+
+func annotateIngredients(ingredients []Ingredient, classes []units.Class) {
+	for i := range ingredients {
+		in := &ingredients[i]
+		if in.Amount == nil || in.Amount.Size != nil {
+			// the unit of a package amount is its container
+			continue
+		}
+		u, ok := units.Lookup(in.Amount.Unit)
+		if !ok || !slices.Contains(classes, u.Class) {
+			continue
+		}
+		system := units.Metric
+		if u.System == units.Metric {
+			system = units.US
+		}
+		if c, ok := units.Convert(*in.Amount, system); ok {
+			in.Amount.Unit += " (" + c.String() + ")"
+		}
+	}
+}
+
+func annotateGroups(groups []IngredientGroup, classes []units.Class) {
+	for i := range groups {
+		annotateIngredients(groups[i].Ingredients, classes)
+		annotateGroups(groups[i].IngredientGroups, classes)
+	}
+}
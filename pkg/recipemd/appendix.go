@@ -0,0 +1,79 @@
+package recipemd
+
+import (
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// Appendix is a section after the instructions under a first-level
+// heading of its own, such as "Notes" or "Variations". Text is its
+// markdown without the heading.
+type Appendix struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// WithAppendices splits first-level headings after the instructions, and
+// what follows each of them, off the instructions into
+// Recipe.Appendices. Without it they are part of the instructions.
+func WithAppendices() ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.appendices = true
+	}
+}
+
+// splitAppendices returns the markdown of the instructions n up to the
+// first first-level heading and the appendices starting at each of them.
+func splitAppendices(n gast.Node, source []byte) (string, []Appendix) {
+	seg := n.Lines().At(0)
+	var headings []*gast.Heading
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if h, ok := c.(*gast.Heading); ok && h.Level == 1 && h.Lines().Len() > 0 {
+			headings = append(headings, h)
+		}
+	}
+	if len(headings) == 0 {
+		return string(seg.Value(source)), nil
+	}
+	start := func(h *gast.Heading) int {
+		return lineStart(source, h.Lines().At(0).Start)
+	}
+	instructions := strings.TrimSpace(string(source[seg.Start:start(headings[0])]))
+	var appendices []Appendix
+	for i, h := range headings {
+		// past the heading, and the underline of a setext heading
+		last := h.Lines().At(h.Lines().Len() - 1).Stop
+		body := nextLine(source, max(last-1, 0))
+		if !strings.HasPrefix(strings.TrimLeft(string(source[start(h):last]), " "), "#") {
+			body = nextLine(source, body)
+		}
+		end := seg.Stop
+		if i+1 < len(headings) {
+			end = start(headings[i+1])
+		}
+		appendices = append(appendices, Appendix{
+			Title: extension.PlainText(h, source),
+			Text:  strings.TrimSpace(string(source[min(body, end):end])),
+		})
+	}
+	return instructions, appendices
+}
+
+// fullInstructions returns the instructions of r followed by its
+// appendices, as they are written in a document.
+func (r *Recipe) fullInstructions() string {
+	parts := []string{r.Instructions}
+	if r.Instructions == "" {
+		parts = nil
+	}
+	for _, a := range r.Appendices {
+		parts = append(parts, "# "+escapeMarkdown(a.Title))
+		if a.Text != "" {
+			parts = append(parts, a.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
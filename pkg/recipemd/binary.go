@@ -0,0 +1,622 @@
+package recipemd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// binaryVersion is the version of the binary format, the first item of
+// the encoding of a recipe. It changes with the fields of the model, so
+// that caches written by an older version are reported as stale.
+const binaryVersion = 1
+
+// ErrBinaryVersion is reported by UnmarshalBinary for data written in
+// another version of the binary format. Caches should parse the recipe
+// from its source again.
+var ErrBinaryVersion = errors.New("recipemd: unsupported binary format version")
+
+// CBOR major types and tags.
+const (
+	cborUint   = 0
+	cborNegint = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborTag    = 6
+	cborSimple = 7
+
+	cborFalse = 20
+	cborTrue  = 21
+	cborNull  = 22
+
+	tagPosBignum = 2
+	tagNegBignum = 3
+	tagRational  = 30
+)
+
+// MarshalBinary encodes r compactly for caches, which can decode it with
+// UnmarshalBinary much faster than parsing its markdown. See AppendBinary.
+func (r Recipe) MarshalBinary() ([]byte, error) {
+	return r.AppendBinary(nil)
+}
+
+// AppendBinary appends the binary encoding of r to b. Unlike the JSON it
+// keeps every field of r, including Sections and the Text of ingredients,
+// and nil slices stay nil, so the decoded recipe is identical to r.
+//
+// The encoding is CBOR (RFC 8949): an array of the format version and the
+// fields of the recipe in declaration order. Structs are arrays of their
+// fields, amounts are rational numbers (tag 30) and durations integer
+// nanoseconds. Encodings can be concatenated into a CBOR sequence to
+// store many recipes in one file.
+func (r Recipe) AppendBinary(b []byte) ([]byte, error) {
+	e := &cborEncoder{b: b}
+	e.array(16)
+	e.int(binaryVersion)
+	e.text(r.Title)
+	e.text(r.Description)
+	e.int(int64(r.PrepTime))
+	e.int(int64(r.CookTime))
+	e.int(int64(r.TotalTime))
+	e.list(len(r.Images), r.Images == nil)
+	for _, img := range r.Images {
+		e.image(img)
+	}
+	e.list(len(r.Tags), r.Tags == nil)
+	for _, t := range r.Tags {
+		e.text(t)
+	}
+	e.list(len(r.Yields), r.Yields == nil)
+	for _, y := range r.Yields {
+		e.amount(y)
+	}
+	e.ingredients(r.Ingredients)
+	e.notes(r.IngredientNotes)
+	e.groups(r.IngredientGroups)
+	e.text(r.Instructions)
+	e.list(len(r.Appendices), r.Appendices == nil)
+	for _, a := range r.Appendices {
+		e.array(2)
+		e.text(a.Title)
+		e.text(a.Text)
+	}
+	e.list(len(r.InstructionSteps), r.InstructionSteps == nil)
+	for _, s := range r.InstructionSteps {
+		e.step(s)
+	}
+	e.list(len(r.Sections), r.Sections == nil)
+	for _, s := range r.Sections {
+		e.text(string(s))
+	}
+	return e.b, nil
+}
+
+// UnmarshalBinary decodes r from data written by MarshalBinary or
+// AppendBinary. It reports an error wrapping ErrBinaryVersion if data is
+// in another version of the format.
+func (r *Recipe) UnmarshalBinary(data []byte) error {
+	d := &cborDecoder{b: data}
+	if n := d.array(); n != 16 && d.err == nil {
+		d.fail("a recipe has %d items, not 16", n)
+	}
+	if v := d.int(); v != binaryVersion && d.err == nil {
+		return fmt.Errorf("%w %d", ErrBinaryVersion, v)
+	}
+	var x Recipe
+	x.Title = d.text()
+	x.Description = d.text()
+	x.PrepTime = time.Duration(d.int())
+	x.CookTime = time.Duration(d.int())
+	x.TotalTime = time.Duration(d.int())
+	if n, ok := d.list(); ok {
+		x.Images = make([]Image, n)
+		for i := range x.Images {
+			x.Images[i] = d.image()
+		}
+	}
+	if n, ok := d.list(); ok {
+		x.Tags = make([]string, n)
+		for i := range x.Tags {
+			x.Tags[i] = d.text()
+		}
+	}
+	if n, ok := d.list(); ok {
+		x.Yields = make([]Amount, n)
+		for i := range x.Yields {
+			x.Yields[i] = d.amount()
+		}
+	}
+	x.Ingredients = d.ingredients()
+	x.IngredientNotes = d.notes()
+	x.IngredientGroups = d.groups()
+	x.Instructions = d.text()
+	if n, ok := d.list(); ok {
+		x.Appendices = make([]Appendix, n)
+		for i := range x.Appendices {
+			d.fields("an appendix", 2)
+			x.Appendices[i] = Appendix{Title: d.text(), Text: d.text()}
+		}
+	}
+	if n, ok := d.list(); ok {
+		x.InstructionSteps = make([]Step, n)
+		for i := range x.InstructionSteps {
+			x.InstructionSteps[i] = d.step()
+		}
+	}
+	if n, ok := d.list(); ok {
+		x.Sections = make([]Section, n)
+		for i := range x.Sections {
+			x.Sections[i] = Section(d.text())
+		}
+	}
+	if d.err == nil && len(d.b) > 0 {
+		d.fail("%d bytes after the recipe", len(d.b))
+	}
+	if d.err != nil {
+		return d.err
+	}
+	*r = x
+	return nil
+}
+
+// cborEncoder appends the CBOR encoding of recipes to b.
+type cborEncoder struct {
+	b []byte
+}
+
+// head appends the initial byte of an item of type major with argument n
+// and the bytes of n that follow it.
+func (e *cborEncoder) head(major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		e.b = append(e.b, major|byte(n))
+	case n <= math.MaxUint8:
+		e.b = append(e.b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		e.b = append(e.b, major|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		e.b = append(e.b, major|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		e.b = append(e.b, major|27, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32),
+			byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func (e *cborEncoder) array(n int) { e.head(cborArray, uint64(n)) }
+func (e *cborEncoder) null()       { e.b = append(e.b, cborSimple<<5|cborNull) }
+
+func (e *cborEncoder) int(n int64) {
+	if n < 0 {
+		e.head(cborNegint, uint64(-(n + 1)))
+		return
+	}
+	e.head(cborUint, uint64(n))
+}
+
+func (e *cborEncoder) text(s string) {
+	e.head(cborText, uint64(len(s)))
+	e.b = append(e.b, s...)
+}
+
+func (e *cborEncoder) bool(v bool) {
+	if v {
+		e.b = append(e.b, cborSimple<<5|cborTrue)
+	} else {
+		e.b = append(e.b, cborSimple<<5|cborFalse)
+	}
+}
+
+// bigInt appends n as an integer, or as a bignum if it does not fit into
+// an int64.
+func (e *cborEncoder) bigInt(n *big.Int) {
+	if n.IsInt64() {
+		e.int(n.Int64())
+		return
+	}
+	if n.Sign() > 0 {
+		e.head(cborTag, tagPosBignum)
+		e.bytes(n.Bytes())
+		return
+	}
+	m := new(big.Int).Neg(n) // -1-n
+	m.Sub(m, big.NewInt(1))
+	e.head(cborTag, tagNegBignum)
+	e.bytes(m.Bytes())
+}
+
+func (e *cborEncoder) bytes(p []byte) {
+	e.head(cborBytes, uint64(len(p)))
+	e.b = append(e.b, p...)
+}
+
+// rat appends x as a rational number, [numerator, denominator] with tag
+// 30, or null if it is nil.
+func (e *cborEncoder) rat(x *big.Rat) {
+	if x == nil {
+		e.null()
+		return
+	}
+	e.head(cborTag, tagRational)
+	e.array(2)
+	e.bigInt(x.Num())
+	e.bigInt(x.Denom())
+}
+
+// list appends the head of an array of n items, or null for a nil slice,
+// whose items are appended by the caller.
+func (e *cborEncoder) list(n int, isNil bool) {
+	if isNil {
+		e.null()
+		return
+	}
+	e.array(n)
+}
+
+func (e *cborEncoder) amount(a Amount) {
+	e.array(5)
+	e.rat(a.Factor)
+	e.rat(a.Max)
+	e.text(a.Unit)
+	e.bool(a.Approx)
+	if a.Size == nil {
+		e.null()
+	} else {
+		e.amount(*a.Size)
+	}
+}
+
+func (e *cborEncoder) image(img Image) {
+	e.array(3)
+	e.text(img.URL)
+	e.text(img.Alt)
+	e.text(img.Title)
+}
+
+// imageRef appends *img, or null if img is nil.
+func (e *cborEncoder) imageRef(img *Image) {
+	if img == nil {
+		e.null()
+		return
+	}
+	e.image(*img)
+}
+
+func (e *cborEncoder) ingredients(s []Ingredient) {
+	e.list(len(s), s == nil)
+	for _, in := range s {
+		e.array(10)
+		e.text(in.Name)
+		if in.Amount == nil {
+			e.null()
+		} else {
+			e.amount(*in.Amount)
+		}
+		e.text(in.Link)
+		e.bool(in.Pinned)
+		e.text(in.Note)
+		e.text(in.Preparation)
+		e.bool(in.Optional)
+		e.text(in.Text)
+		e.text(in.Markdown)
+		e.imageRef(in.Image)
+	}
+}
+
+func (e *cborEncoder) groups(s []IngredientGroup) {
+	e.list(len(s), s == nil)
+	for _, g := range s {
+		e.array(5)
+		e.text(g.Title)
+		e.ingredients(g.Ingredients)
+		e.notes(g.Notes)
+		e.groups(g.IngredientGroups)
+		e.imageRef(g.Image)
+	}
+}
+
+func (e *cborEncoder) notes(s []Note) {
+	e.list(len(s), s == nil)
+	for _, n := range s {
+		e.array(2)
+		e.text(n.Text)
+		e.int(int64(n.Index))
+	}
+}
+
+func (e *cborEncoder) step(s Step) {
+	e.array(4)
+	e.int(int64(s.Number))
+	e.text(s.Text)
+	e.ingredients(s.Ingredients)
+	e.list(len(s.Timers), s.Timers == nil)
+	for _, t := range s.Timers {
+		e.array(3)
+		e.text(t.Text)
+		e.int(int64(t.Duration))
+		e.int(int64(t.Max))
+	}
+}
+
+// cborDecoder decodes the recipes cborEncoder encodes from b. The first
+// error is kept in err; after it, all methods return zero values.
+type cborDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *cborDecoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("recipemd: invalid binary recipe: "+format, args...)
+	}
+}
+
+// head reads the initial byte of an item and its argument. For simple
+// values the argument is the simple value.
+func (d *cborDecoder) head() (major byte, n uint64) {
+	if d.err != nil {
+		return 0, 0
+	}
+	if len(d.b) == 0 {
+		d.fail("unexpected end of data")
+		return 0, 0
+	}
+	major, info := d.b[0]>>5, d.b[0]&0x1f
+	d.b = d.b[1:]
+	if info < 24 {
+		return major, uint64(info)
+	}
+	size := 0
+	switch info {
+	case 24:
+		size = 1
+	case 25:
+		size = 2
+	case 26:
+		size = 4
+	case 27:
+		size = 8
+	default:
+		d.fail("unsupported additional information %d", info)
+		return 0, 0
+	}
+	if len(d.b) < size {
+		d.fail("unexpected end of data")
+		return 0, 0
+	}
+	for _, c := range d.b[:size] {
+		n = n<<8 | uint64(c)
+	}
+	d.b = d.b[size:]
+	return major, n
+}
+
+// null reports whether the next item is null and consumes it if it is.
+// After an error it reports true, which ends the decoding of optional
+// items and nested lists.
+func (d *cborDecoder) null() bool {
+	if d.err != nil {
+		return true
+	}
+	if len(d.b) > 0 && d.b[0] == cborSimple<<5|cborNull {
+		d.b = d.b[1:]
+		return true
+	}
+	return false
+}
+
+// array reads the head of an array and returns its length.
+func (d *cborDecoder) array() int {
+	major, n := d.head()
+	if major != cborArray && d.err == nil {
+		d.fail("want an array, not major type %d", major)
+	}
+	if n > uint64(len(d.b)) {
+		d.fail("array of %d items is longer than the data", n)
+	}
+	if d.err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+// fields reads the head of the array of a struct, which must have n
+// fields.
+func (d *cborDecoder) fields(what string, n int) {
+	if m := d.array(); m != n && d.err == nil {
+		d.fail("%s has %d items, not %d", what, n, m)
+	}
+}
+
+func (d *cborDecoder) int() int64 {
+	major, n := d.head()
+	switch {
+	case d.err != nil:
+	case major == cborUint && n <= math.MaxInt64:
+		return int64(n)
+	case major == cborNegint && n <= math.MaxInt64:
+		return -1 - int64(n)
+	default:
+		d.fail("want an integer, not major type %d", major)
+	}
+	return 0
+}
+
+// raw reads the content of a text or byte string of type major.
+func (d *cborDecoder) raw(major byte) []byte {
+	m, n := d.head()
+	if m != major && d.err == nil {
+		d.fail("want major type %d, not %d", major, m)
+	}
+	if n > uint64(len(d.b)) {
+		d.fail("string of %d bytes is longer than the data", n)
+	}
+	if d.err != nil {
+		return nil
+	}
+	p := d.b[:n]
+	d.b = d.b[n:]
+	return p
+}
+
+func (d *cborDecoder) text() string {
+	return string(d.raw(cborText))
+}
+
+func (d *cborDecoder) bool() bool {
+	major, n := d.head()
+	switch {
+	case d.err != nil:
+	case major == cborSimple && n == cborTrue:
+		return true
+	case major == cborSimple && n == cborFalse:
+	default:
+		d.fail("want a boolean, not major type %d", major)
+	}
+	return false
+}
+
+// bigInt reads an integer or a bignum.
+func (d *cborDecoder) bigInt() *big.Int {
+	if d.err == nil && len(d.b) > 0 && d.b[0]>>5 == cborTag {
+		_, tag := d.head()
+		n := new(big.Int).SetBytes(d.raw(cborBytes))
+		switch tag {
+		case tagPosBignum:
+		case tagNegBignum:
+			n.Neg(n).Sub(n, big.NewInt(1))
+		default:
+			d.fail("want a bignum, not tag %d", tag)
+		}
+		return n
+	}
+	return big.NewInt(d.int())
+}
+
+// rat reads a rational number or null.
+func (d *cborDecoder) rat() *big.Rat {
+	if d.null() {
+		return nil
+	}
+	if major, tag := d.head(); (major != cborTag || tag != tagRational) && d.err == nil {
+		d.fail("want a rational number")
+	}
+	d.fields("a rational number", 2)
+	num, denom := d.bigInt(), d.bigInt()
+	if d.err != nil {
+		return nil
+	}
+	if denom.Sign() <= 0 {
+		d.fail("denominator %v is not positive", denom)
+		return nil
+	}
+	return new(big.Rat).SetFrac(num, denom)
+}
+
+// list reads the head of an array and returns its length. It reports
+// false for null, the encoding of a nil slice.
+func (d *cborDecoder) list() (int, bool) {
+	if d.null() {
+		return 0, false
+	}
+	n := d.array()
+	return n, d.err == nil
+}
+
+func (d *cborDecoder) amount() Amount {
+	d.fields("an amount", 5)
+	a := Amount{Factor: d.rat(), Max: d.rat(), Unit: d.text(), Approx: d.bool()}
+	if !d.null() {
+		size := d.amount()
+		a.Size = &size
+	}
+	return a
+}
+
+func (d *cborDecoder) image() Image {
+	d.fields("an image", 3)
+	return Image{URL: d.text(), Alt: d.text(), Title: d.text()}
+}
+
+// imageRef reads an image or null.
+func (d *cborDecoder) imageRef() *Image {
+	if d.null() {
+		return nil
+	}
+	img := d.image()
+	return &img
+}
+
+func (d *cborDecoder) ingredients() []Ingredient {
+	n, ok := d.list()
+	if !ok {
+		return nil
+	}
+	s := make([]Ingredient, n)
+	for i := range s {
+		d.fields("an ingredient", 10)
+		in := &s[i]
+		in.Name = d.text()
+		if !d.null() {
+			a := d.amount()
+			in.Amount = &a
+		}
+		in.Link = d.text()
+		in.Pinned = d.bool()
+		in.Note = d.text()
+		in.Preparation = d.text()
+		in.Optional = d.bool()
+		in.Text = d.text()
+		in.Markdown = d.text()
+		in.Image = d.imageRef()
+	}
+	return s
+}
+
+func (d *cborDecoder) groups() []IngredientGroup {
+	n, ok := d.list()
+	if !ok {
+		return nil
+	}
+	s := make([]IngredientGroup, n)
+	for i := range s {
+		d.fields("an ingredient group", 5)
+		s[i] = IngredientGroup{
+			Title:            d.text(),
+			Ingredients:      d.ingredients(),
+			Notes:            d.notes(),
+			IngredientGroups: d.groups(),
+			Image:            d.imageRef(),
+		}
+	}
+	return s
+}
+
+func (d *cborDecoder) notes() []Note {
+	n, ok := d.list()
+	if !ok {
+		return nil
+	}
+	s := make([]Note, n)
+	for i := range s {
+		d.fields("a note", 2)
+		s[i] = Note{Text: d.text(), Index: int(d.int())}
+	}
+	return s
+}
+
+func (d *cborDecoder) step() Step {
+	d.fields("a step", 4)
+	s := Step{Number: int(d.int()), Text: d.text(), Ingredients: d.ingredients()}
+	if n, ok := d.list(); ok {
+		s.Timers = make([]Timer, n)
+		for i := range s.Timers {
+			d.fields("a timer", 3)
+			s.Timers[i] = Timer{Text: d.text(), Duration: time.Duration(d.int()), Max: time.Duration(d.int())}
+		}
+	}
+	return s
+}
@@ -0,0 +1,216 @@
+package recipemd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// Builder constructs a recipe step by step:
+//
+//	md, err := recipemd.NewBuilder().
+//		Title("Pancakes").
+//		Tag("breakfast").
+//		Yield("4 servings").
+//		Ingredient("200 g", "flour").
+//		Group("Topping").
+//		Ingredient("", "maple syrup").
+//		Instructions("Mix and fry.").
+//		Markdown()
+//
+// Every step validates its input. The first invalid step is reported by
+// Recipe, Markdown and AST, and the steps after it are ignored; errors
+// wrap the diag code of the problem.
+type Builder struct {
+	r     Recipe
+	stack []*IngredientGroup // open groups, innermost last
+	err   error
+}
+
+// NewBuilder returns a Builder for an empty recipe.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) fail(code diag.Code, format string, args ...any) *Builder {
+	if b.err == nil {
+		b.err = fmt.Errorf("recipemd: "+format+": %w", append(args, code)...)
+	}
+	return b
+}
+
+// Title sets the title.
+func (b *Builder) Title(title string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return b.fail(diag.ErrEmptyTitle, "empty title")
+	}
+	b.r.Title = title
+	return b
+}
+
+// Description sets the description, which is markdown.
+func (b *Builder) Description(markdown string) *Builder {
+	if b.err == nil {
+		b.r.Description = strings.TrimSpace(markdown)
+	}
+	return b
+}
+
+// Tag adds tags. Tags must not be empty or contain commas.
+func (b *Builder) Tag(tags ...string) *Builder {
+	for _, t := range tags {
+		if b.err != nil {
+			return b
+		}
+		t = strings.TrimSpace(t)
+		if t == "" || strings.Contains(t, ",") {
+			return b.fail(diag.ErrInvalidTag, "invalid tag %q", t)
+		}
+		b.r.Tags = append(b.r.Tags, t)
+	}
+	return b
+}
+
+// Yield adds a yield such as "4 servings", which must start with a number.
+func (b *Builder) Yield(yield string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	a := amount.Parse(yield)
+	if a.Factor == nil {
+		return b.fail(diag.WarnYieldWithoutAmount, "yield %q has no amount", yield)
+	}
+	b.r.Yields = append(b.r.Yields, a)
+	return b
+}
+
+// Ingredient adds an ingredient to the innermost open group, or to the
+// recipe if no group is open. An empty amount adds the ingredient without
+// one; an amount starting with the pin marker "=" pins it.
+func (b *Builder) Ingredient(amount, name string) *Builder {
+	return b.LinkedIngredient(amount, name, "")
+}
+
+// LinkedIngredient adds an ingredient whose name links to another recipe.
+func (b *Builder) LinkedIngredient(amt, name, link string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return b.fail(diag.ErrEmptyIngredientName, "ingredient has no name")
+	}
+	in := Ingredient{Link: strings.TrimSpace(link)}
+	in.setText(name)
+	amt, in.Pinned = strings.CutPrefix(strings.TrimSpace(amt), extension.PinMarker)
+	if amt != "" {
+		a := amount.Parse(amt)
+		in.Amount = &a
+	}
+	ingredients, groups := &b.r.Ingredients, b.r.IngredientGroups
+	if len(b.stack) > 0 {
+		g := b.stack[len(b.stack)-1]
+		ingredients, groups = &g.Ingredients, g.IngredientGroups
+	}
+	if len(groups) > 0 {
+		return b.fail(diag.ErrIngredientAfterGroup, "ingredient %q follows a group of the same level", name)
+	}
+	*ingredients = append(*ingredients, in)
+	return b
+}
+
+// Group closes all open groups and starts a top-level ingredient group.
+func (b *Builder) Group(title string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	b.stack = nil
+	return b.SubGroup(title)
+}
+
+// SubGroup starts an ingredient group inside the innermost open group, or
+// a top-level group if none is open.
+func (b *Builder) SubGroup(title string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return b.fail(diag.ErrEmptyGroupTitle, "empty group title")
+	}
+	groups := &b.r.IngredientGroups
+	if len(b.stack) > 0 {
+		groups = &b.stack[len(b.stack)-1].IngredientGroups
+	}
+	*groups = append(*groups, IngredientGroup{Title: title})
+	b.stack = append(b.stack, &(*groups)[len(*groups)-1])
+	return b
+}
+
+// EndGroup closes the innermost open group.
+func (b *Builder) EndGroup() *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.stack) == 0 {
+		b.err = errors.New("recipemd: EndGroup without an open group")
+		return b
+	}
+	b.stack = b.stack[:len(b.stack)-1]
+	return b
+}
+
+// Instructions sets the instructions, which are markdown.
+func (b *Builder) Instructions(markdown string) *Builder {
+	if b.err == nil {
+		b.r.Instructions = strings.TrimSpace(markdown)
+	}
+	return b
+}
+
+// Recipe returns a copy of the recipe built so far.
+func (b *Builder) Recipe() (*Recipe, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.r.Title == "" {
+		return nil, fmt.Errorf("recipemd: no title: %w", diag.ErrMissingTitle)
+	}
+	return b.r.Clone(), nil
+}
+
+// Markdown returns the recipe as a RecipeMD document written by
+// WriteMarkdown.
+func (b *Builder) Markdown() ([]byte, error) {
+	r, err := b.Recipe()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AST returns the document of the recipe parsed with the RecipeMD
+// extension, together with its source.
+func (b *Builder) AST() (gast.Node, []byte, error) {
+	source, err := b.Markdown()
+	if err != nil {
+		return nil, nil, err
+	}
+	return markdown.Parser().Parse(text.NewReader(source)), source, nil
+}
@@ -0,0 +1,136 @@
+package recipemd
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Category is an aisle of a store, such as produce or dairy, that shopping
+// lists can be sorted by. An ingredient belongs to the category with the
+// longest keyword it contains as whole words, ignoring case, so "peanut
+// butter" is pantry and not dairy.
+type Category struct {
+	Name     string
+	Keywords []string
+}
+
+// match returns the length of the longest keyword of c in name, or 0.
+func (c Category) match(name string) int {
+	name = strings.ToLower(name)
+	best := 0
+	for _, k := range c.Keywords {
+		if len(k) <= best {
+			continue
+		}
+		pattern := `\b` + regexp.QuoteMeta(strings.ToLower(k)) + `s?\b`
+		if regexp.MustCompile(pattern).MatchString(name) {
+			best = len(k)
+		}
+	}
+	return best
+}
+
+// categoryOf returns the index of the category of name in categories, or
+// -1 if it has none.
+func categoryOf(name string, categories []Category) int {
+	found, best := -1, 0
+	for i, c := range categories {
+		if n := c.match(name); n > best {
+			found, best = i, n
+		}
+	}
+	return found
+}
+
+// DefaultCategories are common aisles of a grocery store in a typical
+// order of a walk through it.
+var DefaultCategories = []Category{
+	{
+		Name: "produce",
+		Keywords: []string{
+			"apple", "avocado", "banana", "basil", "bell pepper", "berry",
+			"cabbage", "carrot", "celery", "cherry", "chili", "cilantro",
+			"cucumber", "garlic", "ginger", "herb", "kale", "leek", "lemon",
+			"lettuce", "lime", "mint", "mushroom", "onion", "orange",
+			"parsley", "parsnip", "pear", "potato", "rosemary", "salad",
+			"scallion", "shallot", "spinach", "thyme", "tomato", "zucchini",
+		},
+	},
+	{
+		Name:     "bakery",
+		Keywords: []string{"bagel", "baguette", "bread", "bun", "roll", "tortilla"},
+	},
+	{
+		Name: "meat and fish",
+		Keywords: []string{
+			"bacon", "beef", "chicken", "cod", "fish", "ham", "lamb", "meat",
+			"pork", "prawn", "salmon", "sausage", "shrimp", "tuna", "turkey",
+		},
+	},
+	{
+		Name: "dairy and eggs",
+		Keywords: []string{
+			"butter", "buttermilk", "cheese", "cream", "creme fraiche", "egg",
+			"milk", "mozzarella", "parmesan", "quark", "sour cream", "yogurt",
+			"yoghurt",
+		},
+	},
+	{
+		Name: "pantry",
+		Keywords: []string{
+			"baking powder", "baking soda", "bean", "broth", "chickpea",
+			"chocolate", "cocoa", "coconut milk", "cornstarch", "flour",
+			"honey", "jam", "lentil", "maple syrup", "mustard", "noodle",
+			"nut", "oat", "oil", "pasta", "peanut butter", "rice", "salsa",
+			"soy sauce", "stock", "sugar", "syrup", "tomato paste", "vinegar",
+			"yeast",
+		},
+	},
+	{
+		Name: "spices",
+		Keywords: []string{
+			"cardamom", "cayenne", "cinnamon", "clove", "coriander", "cumin",
+			"curry", "nutmeg", "oregano", "paprika", "pepper", "pepper flakes",
+			"salt", "spice", "turmeric", "vanilla",
+		},
+	},
+	{
+		Name:     "frozen",
+		Keywords: []string{"frozen", "ice cream"},
+	},
+	{
+		Name:     "drinks",
+		Keywords: []string{"beer", "coffee", "juice", "mineral water", "tea", "wine"},
+	},
+}
+
+// StoreLayout returns the categories reordered to follow a store: the
+// categories named in order come first, in that order, followed by the
+// others in their original order. Names are compared ignoring case; it is
+// an error to name a category that is not among categories.
+func StoreLayout(categories []Category, order ...string) ([]Category, error) {
+	rank := func(c Category) int {
+		i := slices.IndexFunc(order, func(name string) bool {
+			return strings.EqualFold(strings.TrimSpace(name), c.Name)
+		})
+		if i < 0 {
+			return len(order)
+		}
+		return i
+	}
+	for _, name := range order {
+		if !slices.ContainsFunc(categories, func(c Category) bool {
+			return strings.EqualFold(strings.TrimSpace(name), c.Name)
+		}) {
+			return nil, fmt.Errorf("recipemd: unknown category %q", name)
+		}
+	}
+	layout := slices.Clone(categories)
+	slices.SortStableFunc(layout, func(a, b Category) int {
+		return cmp.Compare(rank(a), rank(b))
+	})
+	return layout, nil
+}
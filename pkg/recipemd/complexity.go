@@ -0,0 +1,106 @@
+package recipemd
+
+import "time"
+
+// Technique is a cooking technique that makes a recipe harder, such as
+// kneading or tempering chocolate, recognized by Keywords in the
+// instructions as whole words, ignoring case.
+type Technique struct {
+	Name     string
+	Keywords []string
+	Weight   float64
+}
+
+// Complexity weighs what makes a recipe complex for Recipe.Complexity and
+// the thresholds of its difficulty levels: recipes scoring up to Easy are
+// easy, up to Medium medium, and harder ones hard.
+type Complexity struct {
+	Ingredient float64     // per ingredient
+	Step       float64     // per step of the instructions
+	Hour       float64     // per hour of the total time
+	Techniques []Technique // each adds its weight once if the instructions use it
+	Easy       float64
+	Medium     float64
+}
+
+// Difficulty levels of recipes.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// DefaultTechniques are techniques that take practice or attention.
+var DefaultTechniques = []Technique{
+	{Name: "kneading", Keywords: []string{"knead"}, Weight: 2},
+	{Name: "proofing", Keywords: []string{"proof", "let rise", "prove"}, Weight: 2},
+	{Name: "laminating", Keywords: []string{"laminate", "fold in the butter"}, Weight: 5},
+	{Name: "tempering", Keywords: []string{"temper"}, Weight: 4},
+	{Name: "deep-frying", Keywords: []string{"deep-fry", "deep fry", "deep-fried", "deep fried"}, Weight: 3},
+	{Name: "caramel", Keywords: []string{"caramelize", "caramelise", "candy thermometer", "soft ball", "hard crack"}, Weight: 3},
+	{Name: "emulsion", Keywords: []string{"emulsify", "emulsion", "hollandaise", "mayonnaise"}, Weight: 3},
+	{Name: "water bath", Keywords: []string{"water bath", "bain-marie", "bain marie", "double boiler"}, Weight: 2},
+	{Name: "piping", Keywords: []string{"pipe", "piping bag"}, Weight: 2},
+	{Name: "flambé", Keywords: []string{"flambé", "flambe", "ignite"}, Weight: 3},
+	{Name: "sous vide", Keywords: []string{"sous vide"}, Weight: 2},
+	{Name: "stiff peaks", Keywords: []string{"stiff peaks", "fold in the egg whites", "meringue"}, Weight: 2},
+}
+
+// DefaultComplexity scores a point per ingredient and step and four per
+// hour of total time. A weeknight dinner of eight ingredients in four
+// steps taking half an hour scores 14 and is easy; most bread recipes
+// are hard. The filter field "difficulty" uses it, so programs can assign
+// their own weights to it before filtering.
+var DefaultComplexity = Complexity{
+	Ingredient: 1,
+	Step:       1,
+	Hour:       4,
+	Techniques: DefaultTechniques,
+	Easy:       15,
+	Medium:     30,
+}
+
+// Complexity returns the complexity score of r weighed by c: the sum of
+// the weights of its ingredients, the steps of its instructions, its
+// total time and the techniques its instructions mention. Recipes
+// without times score nothing for time.
+func (r *Recipe) Complexity(c Complexity) float64 {
+	score := c.Ingredient*float64(len(r.AllIngredients())) +
+		c.Step*float64(len(r.Steps())) +
+		c.Hour*float64(r.TotalTime)/float64(time.Hour)
+	for _, t := range c.Techniques {
+		if t.in(r.Instructions) {
+			score += t.Weight
+		}
+	}
+	return score
+}
+
+// Difficulty returns the difficulty level of r by its Complexity:
+// DifficultyEasy, DifficultyMedium or DifficultyHard.
+func (r *Recipe) Difficulty(c Complexity) string {
+	switch score := r.Complexity(c); {
+	case score <= c.Easy:
+		return DifficultyEasy
+	case score <= c.Medium:
+		return DifficultyMedium
+	}
+	return DifficultyHard
+}
+
+// Techniques returns the names of the techniques among techniques that
+// the instructions of r mention, in the order of techniques.
+func (r *Recipe) Techniques(techniques []Technique) []string {
+	var names []string
+	for _, t := range techniques {
+		if t.in(r.Instructions) {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// in reports whether text mentions t.
+func (t Technique) in(text string) bool {
+	return Category{Keywords: t.Keywords}.match(text) > 0
+}
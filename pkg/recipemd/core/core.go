@@ -0,0 +1,224 @@
+// Package core is the RecipeMD recipe model and parser without the
+// goldmark extension machinery: no goldmark type appears in its API, so
+// programs that only turn documents into recipes and back need not import
+// goldmark or know about its AST.
+//
+//	r, err := core.Parse(source)
+//
+// The parser is still built on goldmark, which is linked into programs
+// using this package. Use package recipemd for access to the document
+// tree, and package extension to render recipes with goldmark.
+package core
+
+import (
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// The recipe model of package recipemd.
+type (
+	Recipe          = recipemd.Recipe
+	Ingredient      = recipemd.Ingredient
+	IngredientGroup = recipemd.IngredientGroup
+	Note            = recipemd.Note
+	Step            = recipemd.Step
+	Timer           = recipemd.Timer
+	Image           = recipemd.Image
+	Appendix        = recipemd.Appendix
+	Amount          = recipemd.Amount
+	Diagnostic      = recipemd.Diagnostic
+	Severity        = recipemd.Severity
+	Position        = recipemd.Position
+	Completeness    = recipemd.Completeness
+	Section         = recipemd.Section
+	Fix             = recipemd.Fix
+	NameCase        = recipemd.NameCase
+	ParseOption     = recipemd.ParseOption
+	WriteOption     = recipemd.WriteOption
+	PostProcessor   = recipemd.PostProcessor
+	Renderer        = recipemd.Renderer
+	RendererFunc    = recipemd.RendererFunc
+)
+
+// ErrBinaryVersion is reported by Recipe.UnmarshalBinary for data written
+// in another version of the binary format.
+var ErrBinaryVersion = recipemd.ErrBinaryVersion
+
+// Severities of diagnostics.
+const (
+	SeverityError   = recipemd.SeverityError
+	SeverityWarning = recipemd.SeverityWarning
+)
+
+// Sections of a recipe, in document order.
+const (
+	SectionTitle        = recipemd.SectionTitle
+	SectionDescription  = recipemd.SectionDescription
+	SectionTags         = recipemd.SectionTags
+	SectionYields       = recipemd.SectionYields
+	SectionIngredients  = recipemd.SectionIngredients
+	SectionInstructions = recipemd.SectionInstructions
+)
+
+// Ingredient name casings.
+const (
+	PreserveCase = recipemd.PreserveCase
+	LowerCase    = recipemd.LowerCase
+	SentenceCase = recipemd.SentenceCase
+)
+
+// WithNameCase normalizes the casing of ingredient names.
+func WithNameCase(c NameCase) ParseOption {
+	return recipemd.WithNameCase(c)
+}
+
+// WithAppendices parses first-level headings after the instructions as
+// appendices.
+func WithAppendices() ParseOption {
+	return recipemd.WithAppendices()
+}
+
+// WithInstructionSteps adds the steps of the instructions to recipes.
+func WithInstructionSteps() ParseOption {
+	return recipemd.WithInstructionSteps()
+}
+
+// WithTitleFallback gives documents without a title the title given.
+func WithTitleFallback(title string) ParseOption {
+	return recipemd.WithTitleFallback(title)
+}
+
+// WithMaxSize rejects documents longer than n bytes.
+func WithMaxSize(n int) ParseOption {
+	return recipemd.WithMaxSize(n)
+}
+
+// WithMaxIngredients limits recipes to n ingredients.
+func WithMaxIngredients(n int) ParseOption {
+	return recipemd.WithMaxIngredients(n)
+}
+
+// WithMaxGroupDepth limits how deeply ingredient groups nest.
+func WithMaxGroupDepth(n int) ParseOption {
+	return recipemd.WithMaxGroupDepth(n)
+}
+
+// UnicodeFractions makes WriteMarkdown write fractions as unicode
+// characters such as "½".
+func UnicodeFractions() WriteOption {
+	return recipemd.UnicodeFractions()
+}
+
+// WithPostProcessors makes WriteMarkdown pass the document through fns
+// before it is written.
+func WithPostProcessors(fns ...PostProcessor) WriteOption {
+	return recipemd.WithPostProcessors(fns...)
+}
+
+// Parse parses a RecipeMD document into a recipe.
+func Parse(source []byte, opts ...ParseOption) (*Recipe, error) {
+	return recipemd.Parse(source, opts...)
+}
+
+// ParsePartial parses a RecipeMD document as far as possible and reports
+// which sections are complete.
+func ParsePartial(source []byte, opts ...ParseOption) (*Recipe, *Completeness) {
+	return recipemd.ParsePartial(source, opts...)
+}
+
+// ParseDir parses the markdown files of a file system in parallel and
+// returns the recipes by path and an error per file that failed.
+func ParseDir(ctx context.Context, fsys fs.FS, opts ...ParseOption) (map[string]*Recipe, []error) {
+	return recipemd.ParseDir(ctx, fsys, opts...)
+}
+
+// ResolveLink resolves the link of an ingredient of the recipe at path
+// from in fsys to the path of the file it points to.
+func ResolveLink(fsys fs.FS, from, link string) (string, bool) {
+	return recipemd.ResolveLink(fsys, from, link)
+}
+
+// ParseAll parses a document holding several recipes, each starting with
+// a first-level heading.
+func ParseAll(source []byte, opts ...ParseOption) ([]*Recipe, []error) {
+	return recipemd.ParseAll(source, opts...)
+}
+
+// Validate reports the problems of a RecipeMD document.
+func Validate(source []byte) []Diagnostic {
+	return recipemd.Validate(source)
+}
+
+// Format returns the canonical formatting of a RecipeMD document.
+func Format(source []byte) ([]byte, error) {
+	return recipemd.Format(source)
+}
+
+// WriteMarkdown writes r to w as a RecipeMD document.
+func WriteMarkdown(w io.Writer, r *Recipe, opts ...WriteOption) error {
+	return recipemd.WriteMarkdown(w, r, opts...)
+}
+
+// WriteHTML writes r to w as HTML with schema.org Recipe microdata. It
+// is safe for concurrent use.
+func WriteHTML(w io.Writer, r *Recipe, opts ...WriteOption) error {
+	return recipemd.WriteHTML(w, r, opts...)
+}
+
+// WritePlainText writes r to w as plain text without markup.
+func WritePlainText(w io.Writer, r *Recipe, opts ...WriteOption) error {
+	return recipemd.WritePlainText(w, r, opts...)
+}
+
+// WriteSocialMeta writes the Open Graph and Twitter card meta elements
+// of a page showing r, published at pageURL.
+func WriteSocialMeta(w io.Writer, r *Recipe, pageURL string) error {
+	return recipemd.WriteSocialMeta(w, r, pageURL)
+}
+
+// WriteJSONLD writes r to w as a schema.org Recipe in JSON-LD.
+func WriteJSONLD(w io.Writer, r *Recipe) error {
+	return recipemd.WriteJSONLD(w, r)
+}
+
+// WriteTOML writes r to w as TOML with the structure of the recipe JSON.
+func WriteTOML(w io.Writer, r *Recipe) error {
+	return recipemd.WriteTOML(w, r)
+}
+
+// WriteXML writes r to w as XML with the structure of the recipe JSON.
+func WriteXML(w io.Writer, r *Recipe) error {
+	return recipemd.WriteXML(w, r)
+}
+
+// RegisterFormat registers r as the renderer of the output format name,
+// whose files have the extension ext.
+func RegisterFormat(name, ext string, r Renderer) {
+	recipemd.RegisterFormat(name, ext, r)
+}
+
+// RegisterStreamFormat registers r as the renderer of the output format
+// name, whose renderings of several recipes may be concatenated.
+func RegisterStreamFormat(name, ext string, r Renderer) {
+	recipemd.RegisterStreamFormat(name, ext, r)
+}
+
+// Streamable reports whether the renderings of the output format name may
+// be concatenated.
+func Streamable(name string) bool {
+	return recipemd.Streamable(name)
+}
+
+// LookupFormat returns the renderer of the output format name and the
+// extension of its files.
+func LookupFormat(name string) (r Renderer, ext string, ok bool) {
+	return recipemd.LookupFormat(name)
+}
+
+// Formats returns the names of the registered output formats, sorted.
+func Formats() []string {
+	return recipemd.Formats()
+}
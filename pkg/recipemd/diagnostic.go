@@ -0,0 +1,150 @@
+package recipemd
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+)
+
+// Position is a location in a source document. Line and Column are
+// 1-based; Column counts characters.
+type Position struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// PositionAt returns the Position of the byte offset in source.
+func PositionAt(source []byte, offset int) Position {
+	offset = max(0, min(offset, len(source)))
+	p := Position{Offset: offset, Line: 1, Column: 1}
+	lineStart := 0
+	for i := 0; i < offset; i++ {
+		if source[i] == '\n' {
+			p.Line++
+			lineStart = i + 1
+		}
+	}
+	p.Column = utf8.RuneCount(source[lineStart:offset]) + 1
+	return p
+}
+
+// Severity classifies a Diagnostic.
+type Severity int
+
+// Severities of diagnostics. Errors are violations of the RecipeMD
+// specification that prevent extraction; warnings flag content that is
+// valid markdown but ignored or likely unintended.
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem found in a RecipeMD document. Code identifies
+// the kind of problem; it is empty for diagnostics created outside this
+// package without one.
+type Diagnostic struct {
+	Pos      Position  `json:"position"`
+	Severity Severity  `json:"severity"`
+	Code     diag.Code `json:"code,omitempty"`
+	Message  string    `json:"message"`
+}
+
+// Error implements error, so error diagnostics can be returned directly.
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("recipemd: %s: %s", d.Pos, d.Message)
+}
+
+// Unwrap returns the code of d, so errors.Is(err, diag.ErrMissingTitle)
+// reports whether err is a diagnostic of that kind.
+func (d *Diagnostic) Unwrap() error {
+	if d.Code == "" {
+		return nil
+	}
+	return d.Code
+}
+
+// diagnostics collects the diagnostics of one document.
+type diagnostics struct {
+	source []byte
+	list   []Diagnostic
+}
+
+// report adds a diagnostic at n with the code, an error or a warning as
+// the code says.
+func (d *diagnostics) report(n gast.Node, code diag.Code, format string, args ...any) {
+	offset := 0
+	if n != nil {
+		offset = nodeOffset(n)
+	}
+	severity := SeverityError
+	if code.IsWarning() {
+		severity = SeverityWarning
+	}
+	d.list = append(d.list, Diagnostic{
+		Pos:      PositionAt(d.source, offset),
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// firstError returns the first error diagnostic, or nil.
+func (d *diagnostics) firstError() error {
+	for i := range d.list {
+		if d.list[i].Severity == SeverityError {
+			return &d.list[i]
+		}
+	}
+	return nil
+}
+
+func (d *diagnostics) sorted() []Diagnostic {
+	sort.SliceStable(d.list, func(i, j int) bool {
+		return d.list[i].Pos.Offset < d.list[j].Pos.Offset
+	})
+	return d.list
+}
+
+// nodeOffset returns the source offset of the first content of n, or 0 if
+// n has no position.
+func nodeOffset(n gast.Node) int {
+	offset := -1
+	_ = gast.Walk(n, func(c gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		var seg text.Segment
+		if t, ok := c.(*gast.Text); ok {
+			seg = t.Segment
+		} else if c.Type() == gast.TypeBlock && c.Lines().Len() > 0 {
+			seg = c.Lines().At(0)
+		} else {
+			return gast.WalkContinue, nil
+		}
+		offset = seg.Start
+		return gast.WalkStop, nil
+	})
+	return max(offset, 0)
+}
@@ -0,0 +1,102 @@
+package recipemd
+
+import "slices"
+
+// Diet is a dietary class such as vegetarian, recognized by the names of
+// the ingredients that rule it out. An ingredient rules the diet out if
+// it contains one of the Excludes keywords as whole words, ignoring case,
+// unless one of the Allows keywords it contains is longer: for vegans,
+// "butter" rules out "butter" but "peanut butter" allows "peanut butter".
+//
+// The classification is a heuristic on names. A recipe that is a
+// candidate for a diet can still contain hidden animal products or gluten
+// and should be checked before it is served to someone who depends on it.
+type Diet struct {
+	Name     string
+	Excludes []string
+	Allows   []string
+}
+
+// excludes reports whether the ingredient name rules out d.
+func (d Diet) excludes(name string) bool {
+	n := Category{Keywords: d.Excludes}.match(name)
+	return n > 0 && n >= Category{Keywords: d.Allows}.match(name)
+}
+
+// meat are the keywords of meat and fish, which vegetarians do not eat.
+var meat = []string{
+	"anchovy", "bacon", "beef", "broth", "chicken", "chorizo", "cod",
+	"duck", "fish", "fish sauce", "gelatin", "gelatine", "ham", "lamb",
+	"lard", "meat", "mussel", "oyster", "pancetta", "pork", "prawn",
+	"prosciutto", "salami", "salmon", "sausage", "shrimp", "squid",
+	"stock", "tuna", "turkey", "veal",
+}
+
+// meatless are ingredients named like meat that are not.
+var meatless = []string{
+	"mushroom stock", "vegan sausage", "vegetable broth",
+	"vegetable stock", "veggie sausage",
+}
+
+// gluten are the keywords of wheat, barley and rye and foods made of them.
+var gluten = []string{
+	"baguette", "barley", "beer", "biscuit", "bread", "breadcrumbs",
+	"bulgur", "bun", "couscous", "cracker", "croissant", "farro", "flour",
+	"lasagna", "macaroni", "malt", "noodle", "panko", "pasta", "pastry",
+	"pita", "rye", "seitan", "semolina", "soy sauce", "spaghetti", "spelt",
+	"toast", "tortilla", "udon", "wheat",
+}
+
+// glutenless are ingredients named like gluten that are free of it.
+var glutenless = []string{
+	"almond flour", "buckwheat flour", "chickpea flour", "coconut flour",
+	"corn flour", "corn tortilla", "gluten-free beer", "gluten-free bread",
+	"gluten-free flour", "gluten-free pasta", "gluten-free soy sauce",
+	"potato flour", "rice flour", "rice noodle", "tamari", "tapioca flour",
+}
+
+// DefaultDiets are the diets Diets classifies recipes by: vegetarian,
+// vegan and gluten-free.
+var DefaultDiets = []Diet{
+	{
+		Name:     "vegetarian",
+		Excludes: meat,
+		Allows:   meatless,
+	},
+	{
+		Name:     "vegan",
+		Excludes: slices.Concat(dairy, eggs, []string{"honey"}, meat),
+		Allows:   slices.Concat(dairyFree, []string{"vegan mayonnaise"}, meatless),
+	},
+	{
+		Name:     "gluten-free",
+		Excludes: gluten,
+		Allows:   glutenless,
+	},
+}
+
+// Diets returns the names of the diets among diets that r is a candidate
+// for, in the order of diets: those none of whose ingredients rule them
+// out. Recipes without ingredients are candidates for none. The result is
+// a guess from ingredient names, see Diet, and is meant for filtering
+// and suggestions, not as a guarantee.
+func (r *Recipe) Diets(diets []Diet) []string {
+	ingredients := r.AllIngredients()
+	if len(ingredients) == 0 {
+		return nil
+	}
+	var names []string
+	for _, d := range diets {
+		fits := true
+		for _, in := range ingredients {
+			if d.excludes(in.Name) {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			names = append(names, d.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,214 @@
+package recipemd
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind classifies a Change.
+type ChangeKind int
+
+// Kinds of changes between two versions of a recipe.
+const (
+	Modified ChangeKind = iota
+	Added
+	Removed
+)
+
+// Change is a single difference between two versions of a recipe. Field is
+// "title", "description", "tags", "yields", "instructions" or "ingredient".
+// For ingredients Name is the ingredient name and OldAmount and NewAmount
+// its amounts; for the other fields Old and New hold the values.
+type Change struct {
+	Kind      ChangeKind
+	Field     string
+	Name      string
+	Old, New  string
+	OldAmount *Amount
+	NewAmount *Amount
+}
+
+// Ratio returns the factor by which the amount of an ingredient changed, or
+// nil if the change is not a change of amount with the same unit.
+func (c Change) Ratio() *big.Rat {
+	if c.Kind != Modified || c.OldAmount == nil || c.NewAmount == nil {
+		return nil
+	}
+	return ratio(*c.OldAmount, *c.NewAmount)
+}
+
+// String describes the change on one line, e.g.
+// "flour: 500 g → 600 g (+20%)".
+func (c Change) String() string {
+	if c.Field != "ingredient" {
+		switch {
+		case c.Field == "description" || c.Field == "instructions":
+			return c.Field + " changed"
+		case c.Old == "":
+			return fmt.Sprintf("%s: added %s", c.Field, c.New)
+		case c.New == "":
+			return fmt.Sprintf("%s: removed %s", c.Field, c.Old)
+		}
+		return fmt.Sprintf("%s: %s → %s", c.Field, c.Old, c.New)
+	}
+	switch c.Kind {
+	case Added:
+		return "+ " + ingredientString(c.Name, c.NewAmount)
+	case Removed:
+		return "- " + ingredientString(c.Name, c.OldAmount)
+	}
+	s := fmt.Sprintf("%s: %s → %s", c.Name, amountString(c.OldAmount), amountString(c.NewAmount))
+	if r := c.Ratio(); r != nil {
+		s += " (" + percent(r) + ")"
+	}
+	return s
+}
+
+// RecipeDiff is the semantic difference between two versions of a recipe.
+type RecipeDiff struct {
+	Changes []Change
+
+	// Factor is set if the new version is a pure rescaling of the old one:
+	// the ingredients are the same and every amount, including the yields,
+	// changed by Factor.
+	Factor *big.Rat
+}
+
+// Diff compares two versions of a recipe. Ingredients are matched by
+// name, ignoring case, regardless of the group they are in.
+func Diff(old, new *Recipe) *RecipeDiff {
+	d := &RecipeDiff{}
+	text := func(field, o, n string) {
+		if o != n {
+			d.Changes = append(d.Changes, Change{Field: field, Old: o, New: n})
+		}
+	}
+	text("title", old.Title, new.Title)
+	text("description", old.Description, new.Description)
+	text("tags", strings.Join(old.Tags, ", "), strings.Join(new.Tags, ", "))
+	text("yields", amountList(old.Yields), amountList(new.Yields))
+	text("instructions", old.Instructions, new.Instructions)
+	for i := range max(len(old.Appendices), len(new.Appendices)) {
+		var o, n Appendix
+		if i < len(old.Appendices) {
+			o = old.Appendices[i]
+		}
+		if i < len(new.Appendices) {
+			n = new.Appendices[i]
+		}
+		field := "appendix " + cmp.Or(n.Title, o.Title)
+		text(field+" title", o.Title, n.Title)
+		text(field, o.Text, n.Text)
+	}
+
+	// ingredients of the new version not yet matched, by name
+	unmatched := make(map[string][]Ingredient)
+	var order []string
+	for _, i := range new.AllIngredients() {
+		key := ingredientKey(i.Name)
+		if _, ok := unmatched[key]; !ok {
+			order = append(order, key)
+		}
+		unmatched[key] = append(unmatched[key], i)
+	}
+	sameIngredients := true
+	var ratios []*big.Rat
+	for _, o := range old.AllIngredients() {
+		key := ingredientKey(o.Name)
+		if len(unmatched[key]) == 0 {
+			d.Changes = append(d.Changes, Change{Kind: Removed, Field: "ingredient", Name: o.Name, OldAmount: o.Amount})
+			sameIngredients = false
+			continue
+		}
+		n := unmatched[key][0]
+		unmatched[key] = unmatched[key][1:]
+		if o.Amount != nil && n.Amount != nil {
+			ratios = append(ratios, ratio(*o.Amount, *n.Amount))
+		} else if o.Amount != nil || n.Amount != nil {
+			ratios = append(ratios, nil)
+		}
+		if amountString(o.Amount) != amountString(n.Amount) {
+			d.Changes = append(d.Changes, Change{Field: "ingredient", Name: n.Name, OldAmount: o.Amount, NewAmount: n.Amount})
+		}
+	}
+	for _, key := range order {
+		for _, n := range unmatched[key] {
+			d.Changes = append(d.Changes, Change{Kind: Added, Field: "ingredient", Name: n.Name, NewAmount: n.Amount})
+			sameIngredients = false
+		}
+	}
+
+	if sameIngredients && len(old.Yields) == len(new.Yields) {
+		for i := range old.Yields {
+			ratios = append(ratios, ratio(old.Yields[i], new.Yields[i]))
+		}
+		d.Factor = commonRatio(ratios)
+	}
+	return d
+}
+
+// commonRatio returns the ratio shared by all ratios if it is not 1.
+func commonRatio(ratios []*big.Rat) *big.Rat {
+	if len(ratios) == 0 || ratios[0] == nil {
+		return nil
+	}
+	for _, r := range ratios[1:] {
+		if r == nil || r.Cmp(ratios[0]) != 0 {
+			return nil
+		}
+	}
+	if ratios[0].Cmp(big.NewRat(1, 1)) == 0 {
+		return nil
+	}
+	return ratios[0]
+}
+
+// ratio returns cur/old if both amounts have a factor and the same unit.
+func ratio(old, cur Amount) *big.Rat {
+	if old.Factor == nil || cur.Factor == nil || old.Factor.Sign() == 0 ||
+		!sameUnit(old.Unit, cur.Unit) || !sameSize(old, cur) {
+		return nil
+	}
+	return new(big.Rat).Quo(cur.Factor, old.Factor)
+}
+
+// percent formats the change by the factor r as a signed percentage.
+func percent(r *big.Rat) string {
+	f, _ := r.Float64()
+	p := math.Round((f-1)*1000) / 10
+	s := strconv.FormatFloat(p, 'f', -1, 64) + "%"
+	if p >= 0 {
+		s = "+" + s
+	}
+	return s
+}
+
+func ingredientKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func ingredientString(name string, a *Amount) string {
+	if a == nil {
+		return name
+	}
+	return a.String() + " " + name
+}
+
+func amountString(a *Amount) string {
+	if a == nil {
+		return "(none)"
+	}
+	return a.String()
+}
+
+func amountList(amounts []Amount) string {
+	s := make([]string, len(amounts))
+	for i, a := range amounts {
+		s[i] = a.String()
+	}
+	return strings.Join(s, ", ")
+}
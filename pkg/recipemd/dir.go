@@ -0,0 +1,121 @@
+package recipemd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ParseDir parses the markdown files of fsys in parallel, as many at a
+// time as there are CPUs, and returns the recipes by slash-separated path.
+// Directories whose names start with a dot are skipped.
+//
+// Files that cannot be read or parsed are left out and reported in the
+// errors, one per file and sorted by path, as *fs.PathError values whose
+// Err is the error Parse returned. If ctx is canceled, ParseDir stops
+// starting new files and the last error is that of ctx.
+func ParseDir(ctx context.Context, fsys fs.FS, opts ...ParseOption) (map[string]*Recipe, []error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var paths []string
+	var walkErrs []error
+	fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			walkErrs = append(walkErrs, err)
+			return nil
+		}
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(path.Ext(p), ".md") {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+
+	type result struct {
+		recipe *Recipe
+		err    error
+	}
+	results := make([]result, len(paths))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				source, err := readFile(fsys, paths[i], cfg.maxSize)
+				if err != nil {
+					results[i].err = err
+					continue
+				}
+				if results[i].recipe, err = Parse(source, opts...); err != nil {
+					results[i].err = &fs.PathError{Op: "parse", Path: paths[i], Err: err}
+				}
+			}
+		}()
+	}
+	started := 0
+feed:
+	for ; started < len(paths) && ctx.Err() == nil; started++ {
+		select {
+		case next <- started:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	recipes := make(map[string]*Recipe, started)
+	errs := walkErrs
+	for i, r := range results[:started] {
+		if r.err != nil {
+			errs = append(errs, r.err)
+		} else {
+			recipes[paths[i]] = r.recipe
+		}
+	}
+	slices.SortStableFunc(errs, func(a, b error) int {
+		return strings.Compare(errorPath(a), errorPath(b))
+	})
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return recipes, errs
+}
+
+// readFile reads the file name of fsys. If limit is positive, it reads at
+// most one byte more than limit, enough for Parse to reject the file.
+func readFile(fsys fs.FS, name string, limit int) ([]byte, error) {
+	if limit <= 0 {
+		return fs.ReadFile(fsys, name)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, int64(limit)+1))
+}
+
+// errorPath returns the path of the file err is about, or "".
+func errorPath(err error) string {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		return pe.Path
+	}
+	return ""
+}
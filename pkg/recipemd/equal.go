@@ -0,0 +1,72 @@
+package recipemd
+
+import (
+	"slices"
+	"strings"
+)
+
+// Normalize returns a copy of r without differences that do not change
+// its meaning: line endings, trailing white space and surrounding blank
+// lines of the markdown texts, runs of white space in titles, tags,
+// names and units, how ingredients write their preparation and optional
+// marker, and the order of the tags, which are sorted without duplicates.
+// Amounts are written by value, so "1/2" and "0.5" need no normalization.
+func (r *Recipe) Normalize() *Recipe {
+	n := r.Clone()
+	n.Title = collapseSpace(n.Title)
+	n.Description = normalizeMarkdown(n.Description)
+	for i, t := range n.Tags {
+		n.Tags[i] = collapseSpace(t)
+	}
+	slices.Sort(n.Tags)
+	n.Tags = slices.Compact(n.Tags)
+	for i := range n.Yields {
+		n.Yields[i].Unit = collapseSpace(n.Yields[i].Unit)
+	}
+	normalizeIngredients(n.Ingredients, n.IngredientNotes)
+	normalizeGroups(n.IngredientGroups)
+	n.Instructions = normalizeMarkdown(n.Instructions)
+	for i := range n.Appendices {
+		n.Appendices[i].Title = collapseSpace(n.Appendices[i].Title)
+		n.Appendices[i].Text = normalizeMarkdown(n.Appendices[i].Text)
+	}
+	return n
+}
+
+func normalizeIngredients(ingredients []Ingredient, notes []Note) {
+	for i := range ingredients {
+		in := &ingredients[i]
+		in.Name = collapseSpace(in.Name)
+		in.Preparation = collapseSpace(in.Preparation)
+		in.Text = ""
+		in.Markdown = collapseSpace(in.Markdown)
+		in.Note = normalizeMarkdown(in.Note)
+		for a := in.Amount; a != nil; a = a.Size {
+			a.Unit = collapseSpace(a.Unit)
+		}
+	}
+	for i := range notes {
+		notes[i].Text = normalizeMarkdown(notes[i].Text)
+	}
+}
+
+func normalizeGroups(groups []IngredientGroup) {
+	for i := range groups {
+		g := &groups[i]
+		g.Title = collapseSpace(g.Title)
+		normalizeIngredients(g.Ingredients, g.Notes)
+		normalizeGroups(g.IngredientGroups)
+	}
+}
+
+// normalizeMarkdown normalizes line endings and trailing white space of
+// markdown text the way Format does.
+func normalizeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Trim(cleanLines(s), "\n")
+}
+
+// Equal reports whether a and b are the same recipe once normalized.
+func Equal(a, b *Recipe) bool {
+	return a.Hash() == b.Hash()
+}
@@ -0,0 +1,26 @@
+package recipemd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Fingerprint returns a hex encoded SHA-256 hash of the canonical markdown
+// of r, as written by WriteMarkdown. Recipes that differ only in the
+// layout of their source, such as list markers, emphasis style or blank
+// lines, have the same fingerprint.
+func (r *Recipe) Fingerprint() string {
+	var b bytes.Buffer
+	_ = WriteMarkdown(&b, r)
+	sum := sha256.Sum256(b.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// Hash returns the fingerprint of r normalized, which changes only when
+// the content of r does: unlike Fingerprint it ignores trailing white
+// space, runs of spaces and the order of tags. It suits keying caches
+// and telling real changes from formatting noise.
+func (r *Recipe) Hash() string {
+	return r.Normalize().Fingerprint()
+}
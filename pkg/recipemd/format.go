@@ -0,0 +1,379 @@
+package recipemd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+)
+
+// Format returns the canonical formatting of a RecipeMD document: an ATX
+// title, tags before yields, "---" dividers, ingredients as "- " bullets
+// with "*amount*" emphasis, one blank line between blocks and no trailing
+// whitespace. Description, instructions and ingredient names keep their
+// markdown. Format reports an error if source is not a valid recipe.
+//
+// Format never changes the meaning of a recipe: it parses the result and
+// reports an error wrapping ErrChangedRecipe, rather than returning it, if
+// the recipe is not Equal to the one of source.
+func Format(source []byte) ([]byte, error) {
+	out, err := format(source)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckFormat(source, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ErrChangedRecipe is reported by Format and CheckFormat if formatting
+// would change the meaning of a recipe, which is a bug of Format.
+var ErrChangedRecipe = errors.New("recipemd: formatting changes the recipe")
+
+// CheckFormat reports whether formatted, the formatting of source, holds
+// the same recipe as source. The error wraps ErrChangedRecipe and lists
+// the differences. Source may have its yields before its tags, which
+// formatting puts in order; other errors of source are returned as they
+// are.
+func CheckFormat(source, formatted []byte) error {
+	old, d := validate(source, &parseConfig{})
+	for i := range d.list {
+		if d.list[i].Severity == SeverityError && d.list[i].Code != diag.ErrYieldsBeforeTags {
+			return &d.list[i]
+		}
+	}
+	new, err := Parse(formatted)
+	if err != nil {
+		return fmt.Errorf("%w: the result is invalid: %w", ErrChangedRecipe, err)
+	}
+	if Equal(old, new) {
+		return nil
+	}
+	var changes []string
+	for _, c := range Diff(old.Normalize(), new.Normalize()).Changes {
+		changes = append(changes, c.String())
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "ingredient notes or structure changed")
+	}
+	return fmt.Errorf("%w: %s", ErrChangedRecipe, strings.Join(changes, "; "))
+}
+
+func format(source []byte) ([]byte, error) {
+	source = bytes.ReplaceAll(source, []byte("\r\n"), []byte("\n"))
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	node, ok := doc.FirstChild().(*ast.Recipe)
+	if !ok {
+		return nil, errors.New("recipemd: missing title: a recipe must start with a first-level heading")
+	}
+	f := &formatter{source: source}
+	var title, desc, tags, yields, ingredients, instructions gast.Node
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		var slot *gast.Node
+		switch c.Kind() {
+		case ast.KindTitle:
+			slot = &title
+		case ast.KindDescription:
+			slot = &desc
+		case ast.KindTags:
+			slot = &tags
+		case ast.KindYields:
+			slot = &yields
+		case ast.KindIngredients:
+			slot = &ingredients
+		case ast.KindInstructions:
+			slot = &instructions
+		case gast.KindThematicBreak:
+			continue
+		default:
+			return nil, fmt.Errorf("recipemd: unexpected %s after tags and yields, expected a divider", c.Kind())
+		}
+		if *slot != nil {
+			return nil, fmt.Errorf("recipemd: %s given more than once", strings.ToLower(c.Kind().String()))
+		}
+		*slot = c
+	}
+	if t, ok := title.(*ast.Title); !ok || t.Title == "" {
+		return nil, errors.New("recipemd: missing title: the first-level heading is empty")
+	}
+
+	f.block("# " + f.lines(title))
+	if desc != nil {
+		f.block(cleanLines(linesText(desc.Lines(), source)))
+	}
+	if tags != nil {
+		f.block("*" + strings.Join(f.list(tags, 1), ", ") + "*")
+	}
+	if yields != nil {
+		f.block("**" + strings.Join(f.list(yields, 2), ", ") + "**")
+	}
+	if ingredients != nil {
+		f.block("---")
+		f.ingredients(ingredients)
+	}
+	if instructions != nil {
+		f.block("---")
+		if instructions.HasChildren() {
+			f.block(cleanLines(linesText(instructions.Lines(), source)))
+		}
+	}
+	return f.out.Bytes(), nil
+}
+
+type formatter struct {
+	source []byte
+	out    bytes.Buffer
+}
+
+// block writes a block, separated from the previous one by a blank line.
+func (f *formatter) block(s string) {
+	if f.out.Len() > 0 {
+		f.out.WriteString("\n")
+	}
+	f.out.WriteString(s)
+	f.out.WriteString("\n")
+}
+
+// lines returns the raw lines of n joined by spaces.
+func (f *formatter) lines(n gast.Node) string {
+	var parts []string
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		if s := strings.TrimSpace(string(line.Value(f.source))); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// list returns the raw entries of a tags or yields paragraph, stripping the
+// emphasis delimiters of the given width.
+func (f *formatter) list(n gast.Node, delim int) []string {
+	raw := f.lines(n)
+	if len(raw) >= 2*delim {
+		raw = raw[delim : len(raw)-delim]
+	}
+	entries := amount.SplitList(raw)
+	if n.Kind() == ast.KindTags {
+		entries = strings.Split(raw, ",")
+	}
+	var list []string
+	for _, e := range entries {
+		if e = collapseSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// collapseSpace trims s and replaces runs of whitespace with one space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func (f *formatter) ingredients(container gast.Node) {
+	for c := container.FirstChild(); c != nil; c = c.NextSibling() {
+		switch n := c.(type) {
+		case *ast.IngredientGroup:
+			f.ingredients(n)
+		case *gast.Heading:
+			f.block(strings.Repeat("#", n.Level) + " " + f.lines(n))
+		case *gast.List:
+			// consecutive lists, e.g. with different bullets, become one
+			var b strings.Builder
+			f.writeList(&b, n, "")
+			for next, ok := c.NextSibling().(*gast.List); ok; next, ok = c.NextSibling().(*gast.List) {
+				c = next
+				f.writeList(&b, next, "")
+			}
+			f.block(strings.TrimSuffix(b.String(), "\n"))
+		default:
+			f.block(cleanLines(f.raw(c)))
+		}
+	}
+}
+
+// writeList writes the items of an ingredient list with the given
+// indentation.
+func (f *formatter) writeList(b *strings.Builder, list *gast.List, indent string) {
+	n := list.Start
+	for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+		marker := "-"
+		if list.IsOrdered() {
+			marker = strconv.Itoa(n) + "."
+			n++
+		}
+		b.WriteString(indent + marker)
+		block := c.FirstChild()
+		if block != nil && (block.Kind() == gast.KindTextBlock || block.Kind() == gast.KindParagraph) {
+			if s := f.ingredient(block); s != "" {
+				b.WriteString(" " + s)
+			}
+			block = block.NextSibling()
+		}
+		b.WriteString("\n")
+		inner := indent + strings.Repeat(" ", len(marker)+1)
+		for ; block != nil; block = block.NextSibling() {
+			if l, ok := block.(*gast.List); ok {
+				f.writeList(b, l, inner)
+				continue
+			}
+			// a blank line keeps notes from continuing the ingredient
+			b.WriteString("\n")
+			for _, line := range strings.Split(dedent(cleanLines(f.raw(block))), "\n") {
+				if line != "" {
+					b.WriteString(inner + line)
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+}
+
+// ingredient returns the canonical text of an ingredient's first paragraph.
+func (f *formatter) ingredient(block gast.Node) string {
+	lines := block.Lines()
+	if lines.Len() == 0 {
+		return ""
+	}
+	first := lines.At(0)
+	a, ok := block.FirstChild().(*ast.Amount)
+	// the amount ends at the closing delimiter after its last text segment
+	stop := -1
+	if ok {
+		_ = gast.Walk(a, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+			if t, ok := n.(*gast.Text); ok && entering {
+				stop = t.Segment.Stop
+			}
+			return gast.WalkContinue, nil
+		})
+	}
+	if stop <= first.Start || stop >= first.Stop {
+		return f.lines(block)
+	}
+	var parts []string
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		if i == 0 {
+			line = line.WithStart(stop + 1)
+		}
+		if s := strings.TrimSpace(string(line.Value(f.source))); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	s := "*" + collapseSpace(string(f.source[first.Start+1:stop])) + "*"
+	if len(parts) > 0 {
+		s += " " + strings.Join(parts, " ")
+	}
+	return s
+}
+
+// raw returns the source lines spanned by the block n.
+func (f *formatter) raw(n gast.Node) string {
+	start, stop := -1, -1
+	_ = gast.Walk(n, func(c gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		var segs []text.Segment
+		if c.Type() == gast.TypeBlock {
+			for i := 0; i < c.Lines().Len(); i++ {
+				segs = append(segs, c.Lines().At(i))
+			}
+		} else if t, ok := c.(*gast.Text); ok {
+			segs = append(segs, t.Segment)
+		}
+		for _, s := range segs {
+			if start < 0 || s.Start < start {
+				start = s.Start
+			}
+			if s.Stop > stop {
+				stop = s.Stop
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	if start < 0 {
+		return ""
+	}
+	for start > 0 && f.source[start-1] != '\n' {
+		start--
+	}
+	for stop < len(f.source) && f.source[stop-1] != '\n' {
+		stop++
+	}
+	if n.Kind() == gast.KindFencedCodeBlock {
+		// include the fences, which are not part of the block's lines
+		start = prevLine(f.source, start)
+		stop = nextLine(f.source, stop)
+	}
+	return strings.Trim(string(f.source[start:stop]), "\n")
+}
+
+func prevLine(source []byte, start int) int {
+	if start == 0 {
+		return 0
+	}
+	i := start - 1
+	for i > 0 && source[i-1] != '\n' {
+		i--
+	}
+	return i
+}
+
+func nextLine(source []byte, stop int) int {
+	for stop < len(source) && source[stop] != '\n' {
+		stop++
+	}
+	if stop < len(source) {
+		stop++
+	}
+	return stop
+}
+
+// cleanLines removes trailing whitespace from every line of s. Two or more
+// trailing spaces before a non-blank line mark a hard line break in
+// markdown and are kept as exactly two spaces.
+func cleanLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, " \t")
+		hardBreak := strings.HasSuffix(line, "  ") && trimmed != "" &&
+			i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != ""
+		if hardBreak {
+			trimmed += "  "
+		}
+		lines[i] = trimmed
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dedent removes the indentation common to all non-blank lines of s.
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if common < 0 || n < common {
+			common = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= common && common > 0 {
+			lines[i] = line[common:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,114 @@
+package recipemd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			"canonical",
+			"# Tea\n\n*hot*\n\n**1 cup**\n\n---\n\n- *1* tea bag\n\n---\n\nSteep.\n",
+			"# Tea\n\n*hot*\n\n**1 cup**\n\n---\n\n- *1* tea bag\n\n---\n\nSteep.\n",
+		},
+		{
+			"setext title and bullets",
+			"Tea\n===\n\n*hot*\n\n***\n\n* *1* tea bag\n+ water   \n\n___\n\nSteep.",
+			"# Tea\n\n*hot*\n\n---\n\n- *1* tea bag\n- water\n\n---\n\nSteep.\n",
+		},
+		{
+			"yields before tags",
+			"# Tea\n\n**1 cup**\n\n*hot*\n\n---\n\n- *1* tea bag\n",
+			"# Tea\n\n*hot*\n\n**1 cup**\n\n---\n\n- *1* tea bag\n",
+		},
+		{
+			"ingredient groups",
+			"# Tea\n\n---\n\n## Base\n* *1* tea bag\n### Extras\n* lemon\n",
+			"# Tea\n\n---\n\n## Base\n\n- *1* tea bag\n\n### Extras\n\n- lemon\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format([]byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatIdempotent checks that formatting formatted recipes of the
+// conformance corpus changes nothing.
+func TestFormatIdempotent(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "conformance", "testdata", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no recipes in the conformance corpus")
+	}
+	for _, f := range files {
+		t.Run(filepath.Base(f), func(t *testing.T) {
+			source, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			once, err := Format(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			twice, err := Format(once)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(once) != string(twice) {
+				t.Errorf("formatting again changes\n%s\nto\n%s", once, twice)
+			}
+		})
+	}
+}
+
+func TestFormatError(t *testing.T) {
+	for _, in := range []string{
+		"no title\n",
+		"#\n",
+		"# Tea\n\n---\n\n- *1 cup*\n",
+	} {
+		if out, err := Format([]byte(in)); err == nil {
+			t.Errorf("Format(%q) = %q, want an error", in, out)
+		}
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	source := []byte("# Tea\n\n**1 cup**\n\n*hot*\n\n---\n\n- *1* tea bag\n")
+	tests := []struct {
+		name      string
+		formatted string
+		changed   bool
+	}{
+		{"same recipe", "# Tea\n\n*hot*\n\n**1 cup**\n\n---\n\n- *1* tea bag\n", false},
+		{"amount changed", "# Tea\n\n*hot*\n\n**1 cup**\n\n---\n\n- *2* tea bag\n", true},
+		{"tag dropped", "# Tea\n\n**1 cup**\n\n---\n\n- *1* tea bag\n", true},
+		{"invalid result", "**1 cup**\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckFormat(source, []byte(tt.formatted))
+			if got := errors.Is(err, ErrChangedRecipe); got != tt.changed {
+				t.Errorf("CheckFormat error = %v, want changed %v", err, tt.changed)
+			}
+		})
+	}
+	if err := CheckFormat([]byte("no title\n"), source); err == nil || errors.Is(err, ErrChangedRecipe) {
+		t.Errorf("CheckFormat of an invalid source = %v, want its diagnostic", err)
+	}
+}
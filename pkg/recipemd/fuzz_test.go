@@ -0,0 +1,85 @@
+package recipemd
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+// The seed corpus of these targets in testdata/fuzz is the conformance
+// corpus of pkg/conformance.
+
+func FuzzParse(f *testing.F) {
+	f.Add([]byte(pancakes))
+	f.Fuzz(func(t *testing.T, source []byte) {
+		r, err := Parse(source)
+		if (r == nil) == (err == nil) {
+			t.Fatalf("Parse = %v, %v; want a recipe or an error", r, err)
+		}
+		if err != nil {
+			return
+		}
+		var b bytes.Buffer
+		if err := WriteMarkdown(&b, r); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzParsePartial(f *testing.F) {
+	f.Add([]byte(pancakes))
+	f.Fuzz(func(t *testing.T, source []byte) {
+		r, report := ParsePartial(source)
+		if r == nil || report == nil {
+			t.Fatalf("ParsePartial = %v, %v; want a recipe and a report", r, report)
+		}
+		if _, err := Parse(source); err == nil && !report.Complete() {
+			t.Errorf("report of a valid recipe is incomplete: %v", report.Err())
+		}
+	})
+}
+
+// FuzzFormat checks that formatted documents stay formatted and keep
+// their recipe.
+func FuzzFormat(f *testing.F) {
+	f.Add([]byte(pancakes))
+	f.Fuzz(func(t *testing.T, source []byte) {
+		once, err := Format(source)
+		if err != nil {
+			return
+		}
+		twice, err := Format(once)
+		if err != nil {
+			t.Fatalf("formatted document does not format: %v\n%s", err, once)
+		}
+		if !bytes.Equal(once, twice) {
+			t.Errorf("formatting again changes\n%q\nto\n%q", once, twice)
+		}
+	})
+}
+
+// FuzzUpdate checks that updating a document with its own recipe leaves
+// it as it is, and that updating it with a scaled recipe gives a document
+// that parses.
+func FuzzUpdate(f *testing.F) {
+	f.Add([]byte(pancakes))
+	f.Fuzz(func(t *testing.T, source []byte) {
+		r, err := Parse(source)
+		if err != nil {
+			return
+		}
+		out, err := Update(source, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, source) {
+			t.Errorf("Update with the same recipe changes\n%q\nto\n%q", source, out)
+		}
+		if out, err = Update(source, r.Scale(big.NewRat(2, 1))); err != nil {
+			return
+		}
+		if _, err := Parse(out); err != nil {
+			t.Errorf("updated document does not parse: %v\n%s", err, out)
+		}
+	})
+}
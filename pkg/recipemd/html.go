@@ -0,0 +1,78 @@
+package recipemd
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/yuin/goldmark"
+	gext "github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// htmlMarkdown renders recipes. Unlike the parser of recipes it renders
+// tables and footnotes in the description and instructions.
+var htmlMarkdown = goldmark.New(goldmark.WithExtensions(gext.Table, gext.Footnote, extension.RecipeMD))
+
+// WithHTMLOptions makes WriteHTML render with opts, options of the
+// extension's HTMLRenderer such as extension.WithCheckboxes and goldmark's
+// html options. Other writers ignore them.
+func WithHTMLOptions(opts ...html.Option) WriteOption {
+	return func(c *writeConfig) {
+		c.html = append(c.html, opts...)
+	}
+}
+
+// WithLang writes the section labels of the language lang, a BCP 47 tag,
+// as extension.LabelsFor returns them: the headings of WritePlainText,
+// and the lang attribute and labels of WriteHTML.
+func WithLang(lang string) WriteOption {
+	return func(c *writeConfig) {
+		c.lang = lang
+	}
+}
+
+// WithLabels replaces the section labels WithLang selects, leaving those
+// that are empty in labels.
+func WithLabels(labels extension.Labels) WriteOption {
+	return func(c *writeConfig) {
+		c.labels = labels.Or(c.labels)
+	}
+}
+
+// WriteHTML writes r to w as HTML annotated with schema.org Recipe
+// microdata: the document WriteMarkdown writes, rendered by the RecipeMD
+// extension together with goldmark's table and footnote extensions. Post
+// processors are given the HTML.
+//
+// Like the other functions of this package, WriteHTML may be called from
+// several goroutines at once. The renderer keeps no state between calls
+// and does not modify the documents it renders.
+func WriteHTML(w io.Writer, r *Recipe, opts ...WriteOption) error {
+	c := writeConfig{amount: Amount.String}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	md := htmlMarkdown
+	htmlOpts := c.html
+	if c.lang != "" {
+		htmlOpts = append([]html.Option{extension.WithLang(c.lang)}, htmlOpts...)
+	}
+	if c.labels != (extension.Labels{}) {
+		htmlOpts = append(htmlOpts, extension.WithLabels(c.labels))
+	}
+	if len(htmlOpts) > 0 {
+		md = goldmark.New(goldmark.WithExtensions(gext.Table, gext.Footnote, extension.New(extension.WithHTMLOptions(htmlOpts...))))
+	}
+	var b bytes.Buffer
+	if err := md.Convert(markdownOf(r, &c), &b); err != nil {
+		return err
+	}
+	out, err := PostProcess(b.Bytes(), c.post...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,60 @@
+package recipemd
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteHTML(t *testing.T) {
+	r, err := Parse([]byte(pancakes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := WriteHTML(&b, r, WithLang("de")); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{`<h1 id="pancakes" itemprop="name">Pancakes</h1>`, `lang="de"`, `itemprop="recipeIngredient"`} {
+		if !strings.Contains(b.String(), w) {
+			t.Errorf("output does not contain %s:\n%s", w, &b)
+		}
+	}
+}
+
+// TestConcurrentConvert writes one recipe as HTML, markdown and JSON from
+// 16 goroutines; run it with -race.
+func TestConcurrentConvert(t *testing.T) {
+	r, err := Parse([]byte(pancakes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writers := map[string]func(*bytes.Buffer) error{
+		"html":     func(b *bytes.Buffer) error { return WriteHTML(b, r) },
+		"markdown": func(b *bytes.Buffer) error { return WriteMarkdown(b, r) },
+		"json":     func(b *bytes.Buffer) error { return writeJSON(b, r) },
+	}
+	want := make(map[string]string)
+	for name, write := range writers {
+		var b bytes.Buffer
+		if err := write(&b); err != nil {
+			t.Fatal(err)
+		}
+		want[name] = b.String()
+	}
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			for name, write := range writers {
+				var b bytes.Buffer
+				if err := write(&b); err != nil {
+					t.Error(err)
+				} else if b.String() != want[name] {
+					t.Errorf("concurrent %s differs:\n%s\nwant\n%s", name, &b, want[name])
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
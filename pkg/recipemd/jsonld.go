@@ -0,0 +1,76 @@
+package recipemd
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// jsonLDRecipe is a schema.org Recipe with the properties the HTML
+// renderer writes as microdata.
+type jsonLDRecipe struct {
+	Context      string   `json:"@context"`
+	Type         string   `json:"@type"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Image        []string `json:"image,omitempty"`
+	PrepTime     string   `json:"prepTime,omitempty"`
+	CookTime     string   `json:"cookTime,omitempty"`
+	TotalTime    string   `json:"totalTime,omitempty"`
+	Keywords     string   `json:"keywords,omitempty"`
+	Yield        []string `json:"recipeYield,omitempty"`
+	Ingredients  []string `json:"recipeIngredient,omitempty"`
+	Instructions any      `json:"recipeInstructions,omitempty"`
+}
+
+type jsonLDStep struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Text     string `json:"text"`
+}
+
+// WriteJSONLD writes r to w as a schema.org Recipe in JSON-LD, for the
+// script element search engines read recipe cards from. Texts are plain
+// text; the instructions are a list of HowToStep if they have steps and
+// a single text otherwise. Image URLs are written as they are in the
+// recipe and may need resolving against the page they are published on.
+func WriteJSONLD(w io.Writer, r *Recipe) error {
+	j := jsonLDRecipe{
+		Context:     "https://schema.org",
+		Type:        "Recipe",
+		Name:        r.Title,
+		Description: plainMarkdown(r.Description),
+		PrepTime:    isoDuration(r.PrepTime),
+		CookTime:    isoDuration(r.CookTime),
+		TotalTime:   isoDuration(r.TotalTime),
+		Keywords:    strings.Join(r.Tags, ", "),
+	}
+	for _, img := range r.Images {
+		j.Image = append(j.Image, img.URL)
+	}
+	for _, y := range r.Yields {
+		j.Yield = append(j.Yield, y.String())
+	}
+	for _, in := range r.AllIngredients() {
+		s := in.text()
+		if in.Amount != nil {
+			s = in.Amount.String() + " " + s
+		}
+		j.Ingredients = append(j.Ingredients, s)
+	}
+	if steps := r.Steps(); len(steps) > 0 {
+		list := make([]jsonLDStep, len(steps))
+		for i, s := range steps {
+			list[i] = jsonLDStep{Type: "HowToStep", Position: i + 1, Text: plainMarkdown(s.Text)}
+		}
+		j.Instructions = list
+	} else if r.Instructions != "" {
+		j.Instructions = plainMarkdown(r.Instructions)
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
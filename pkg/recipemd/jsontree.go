@@ -0,0 +1,81 @@
+package recipemd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// A jsonMember is a member of a JSON object decoded by decodeTree.
+type jsonMember struct {
+	key   string
+	value any
+}
+
+// recipeTree returns the recipe JSON of r as a tree of []jsonMember for
+// objects, which keeps the order of their members, []any for arrays,
+// string, json.Number, bool and nil. The TOML and XML writers encode this
+// tree, so they hold the same data as the JSON in the same structure.
+func recipeTree(r *Recipe) ([]jsonMember, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeTree(dec)
+	if err != nil {
+		return nil, err
+	}
+	return v.([]jsonMember), nil
+}
+
+// decodeTree decodes the next JSON value of dec.
+func decodeTree(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := []jsonMember{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeTree(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, jsonMember{key.(string), v})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			v, err := decodeTree(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err := dec.Token()
+		return arr, err
+	}
+	return tok, nil
+}
+
+// singular returns the name of an element of the array key of the recipe
+// JSON: "ingredient_group" for "ingredient_groups" and "appendix" for
+// "appendices".
+func singular(key string) string {
+	switch {
+	case strings.HasSuffix(key, "ices"):
+		return strings.TrimSuffix(key, "ices") + "ix"
+	case strings.HasSuffix(key, "s"):
+		return strings.TrimSuffix(key, "s")
+	}
+	return key + "_item"
+}
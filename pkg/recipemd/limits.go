@@ -0,0 +1,67 @@
+package recipemd
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+)
+
+// WithMaxSize makes Parse, ParsePartial and ExtractRecipe reject documents
+// longer than n bytes with a diag.ErrTooLarge diagnostic, before parsing
+// them, and ParseDir read no more than that of a file. Services parsing
+// uploads should also limit how much of a request they read, for example
+// with http.MaxBytesReader. Zero, the default, means no limit.
+func WithMaxSize(n int) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.maxSize = n
+	}
+}
+
+// WithMaxIngredients limits the recipe to n ingredients, counting those
+// of all groups. Further ingredients are left out and reported with a
+// diag.ErrTooManyIngredients diagnostic. Zero, the default, means no
+// limit.
+func WithMaxIngredients(n int) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.maxIngredients = n
+	}
+}
+
+// WithMaxGroupDepth limits how deeply ingredient groups nest: groups of
+// the ingredient section are at depth 1, their subgroups at depth 2 and
+// so on. Deeper groups are left out, with their ingredients, and reported
+// with a diag.ErrTooDeep diagnostic. Zero, the default, means no limit.
+func WithMaxGroupDepth(n int) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.maxGroupDepth = n
+	}
+}
+
+// checkSize returns a diag.ErrTooLarge diagnostic if source is longer
+// than the maximum size of cfg.
+func (cfg *parseConfig) checkSize(source []byte) *Diagnostic {
+	if cfg.maxSize <= 0 || len(source) <= cfg.maxSize {
+		return nil
+	}
+	return &Diagnostic{
+		Pos:      PositionAt(source, cfg.maxSize),
+		Severity: SeverityError,
+		Code:     diag.ErrTooLarge,
+		Message:  fmt.Sprintf("document is longer than the maximum of %d bytes", cfg.maxSize),
+	}
+}
+
+// groupDepth returns the number of ingredient groups n is nested in,
+// counting n if it is one.
+func groupDepth(n gast.Node) int {
+	depth := 0
+	for ; n != nil; n = n.Parent() {
+		if n.Kind() == ast.KindIngredientGroup {
+			depth++
+		}
+	}
+	return depth
+}
@@ -0,0 +1,112 @@
+package recipemd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// ErrLinkCycle is returned by SubRecipes for recipes that link to
+// themselves through their ingredients.
+var ErrLinkCycle = errors.New("recipemd: recipes link to each other in a cycle")
+
+// A Resolver returns the recipe that link, the link of an ingredient of
+// the recipe at base, points to, and the location of that recipe, the base
+// of its own links. Locations are whatever the resolver understands, such
+// as file paths or URLs. It returns a nil recipe and no error for links
+// that do not point to recipes, such as a link to an article about an
+// ingredient.
+type Resolver func(base, link string) (*Recipe, string, error)
+
+// SubRecipe is a recipe that an ingredient links to, such as the dough of
+// a pizza, scaled to make the amount of the ingredient.
+//
+// Factor is the factor the linked recipe is scaled by. An ingredient
+// amount with a unit is made by the yield of the linked recipe in that
+// unit, converted if need be, so "750 g" of a dough yielding "500 g"
+// scales it by 3/2. An amount without a unit uses a yield without a unit
+// if the linked recipe has one and otherwise counts batches of the
+// recipe. An ingredient without an amount takes one batch.
+type SubRecipe struct {
+	Index      int    // of the linking ingredient in AllIngredients of its recipe
+	Location   string // of the linked recipe, as returned by the Resolver
+	Factor     *big.Rat
+	Recipe     *Recipe // the linked recipe, scaled by Factor
+	SubRecipes []SubRecipe
+}
+
+// SubRecipes resolves the links of the ingredients of r, located at base,
+// and of the recipes they link to, recursively, and returns the linked
+// recipes scaled to the amounts the ingredients ask for. Scale r first to
+// scale its sub-recipes along with it. The error wraps ErrLinkCycle if
+// recipes link to each other in a cycle.
+func (r *Recipe) SubRecipes(base string, resolve Resolver) ([]SubRecipe, error) {
+	return r.subRecipes(base, resolve, []string{base})
+}
+
+func (r *Recipe) subRecipes(base string, resolve Resolver, seen []string) ([]SubRecipe, error) {
+	var subs []SubRecipe
+	for i, in := range r.AllIngredients() {
+		if in.Link == "" {
+			continue
+		}
+		linked, loc, err := resolve(base, in.Link)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", in.Link, err)
+		}
+		if linked == nil {
+			continue
+		}
+		if slices.Contains(seen, loc) {
+			return nil, fmt.Errorf("%w: %s links to %s", ErrLinkCycle, base, loc)
+		}
+		factor, err := linkFactor(in, linked)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", in.Link, err)
+		}
+		s := SubRecipe{Index: i, Location: loc, Factor: factor, Recipe: linked.Scale(factor)}
+		if s.SubRecipes, err = s.Recipe.subRecipes(loc, resolve, append(slices.Clip(seen), loc)); err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, nil
+}
+
+// linkFactor returns the factor by which sub must be scaled to make the
+// amount of the ingredient in, which links to it.
+func linkFactor(in Ingredient, sub *Recipe) (*big.Rat, error) {
+	if in.Amount == nil || in.Amount.Factor == nil {
+		return big.NewRat(1, 1), nil
+	}
+	want := *in.Amount
+	if s := want.Size; s != nil && s.Factor != nil {
+		// "2 x 400 g" is 800 g
+		want = Amount{Factor: new(big.Rat).Mul(want.Factor, s.Factor), Unit: s.Unit}
+	}
+	if want.Unit != "" {
+		return sub.YieldFactor(want)
+	}
+	if f, err := sub.YieldFactor(want); err == nil {
+		return f, nil
+	}
+	return new(big.Rat).Set(want.Factor), nil
+}
+
+// FullIngredients returns the ingredients of r with every ingredient that
+// links to one of subs, the sub-recipes of r, replaced by the full
+// ingredients of the sub-recipe: the ingredients needed to make r from
+// scratch.
+func (r *Recipe) FullIngredients(subs []SubRecipe) []Ingredient {
+	var full []Ingredient
+	for i, in := range r.AllIngredients() {
+		j := slices.IndexFunc(subs, func(s SubRecipe) bool { return s.Index == i })
+		if j < 0 {
+			full = append(full, in)
+			continue
+		}
+		full = append(full, subs[j].Recipe.FullIngredients(subs[j].SubRecipes)...)
+	}
+	return full
+}
@@ -0,0 +1,81 @@
+package recipemd
+
+import (
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// ResolveLink resolves link, the link of an ingredient of the recipe at
+// the slash-separated path from in fsys, to the path in fsys of the file
+// it points to. Links are relative to the directory of from, or to the
+// root of fsys if they start with a slash. A link without an extension
+// points to the file of that name with the extension .md if there is no
+// file without one. The query and fragment of link are ignored. It
+// reports false for links with a scheme or a host, links leading out of
+// fsys and links to files that do not exist.
+func ResolveLink(fsys fs.FS, from, link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return "", false
+	}
+	var p string
+	if strings.HasPrefix(u.Path, "/") {
+		p = path.Clean(u.Path[1:])
+	} else {
+		p = path.Join(path.Dir(from), u.Path)
+	}
+	if !fs.ValidPath(p) || p == "." {
+		return "", false
+	}
+	candidates := []string{p}
+	if path.Ext(p) == "" {
+		candidates = append(candidates, p+".md")
+	}
+	for _, c := range candidates {
+		if fi, err := fs.Stat(fsys, c); err == nil && fi.Mode().IsRegular() {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// WebLinks returns an extension.LinkResolver for web pages of the recipes
+// in fsys, such as a static site. It resolves links with ResolveLink and
+// rewrites them to the URL path that pageURL returns for the linked file,
+// relative to the one it returns for the linking recipe, so the pages can
+// be served from any path. The query and fragment of a link are kept.
+// Links that do not resolve are left as they are.
+func WebLinks(fsys fs.FS, pageURL func(p string) string) extension.LinkResolver {
+	return func(from, link string) (string, bool) {
+		target, ok := ResolveLink(fsys, from, link)
+		if !ok {
+			return "", false
+		}
+		u, _ := url.Parse(link) // parsed by ResolveLink
+		rel := &url.URL{
+			Path:     relativePath(pageURL(from), pageURL(target)),
+			RawQuery: u.RawQuery,
+			Fragment: u.Fragment,
+		}
+		return rel.String(), true
+	}
+}
+
+// relativePath returns the path of the slash-separated path to relative
+// to the directory of from.
+func relativePath(from, to string) string {
+	dir := strings.Split(path.Dir(from), "/")
+	if dir[0] == "." {
+		dir = nil
+	}
+	parts := strings.Split(to, "/")
+	i := 0
+	for i < len(dir) && i < len(parts)-1 && dir[i] == parts[i] {
+		i++
+	}
+	return strings.Repeat("../", len(dir)-i) + strings.Join(parts[i:], "/")
+}
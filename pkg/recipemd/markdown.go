@@ -0,0 +1,236 @@
+package recipemd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// WriteOption configures WriteMarkdown, WritePlainText and WriteHTML.
+type WriteOption func(*writeConfig)
+
+type writeConfig struct {
+	amount func(Amount) string
+	post   []PostProcessor
+	html   []html.Option
+	lang   string
+	labels extension.Labels
+}
+
+// PostProcessor transforms rendered output, for example to minify it,
+// extract a search index from it or insert a banner.
+type PostProcessor func([]byte) ([]byte, error)
+
+// PostProcess passes data through fns in order and returns the result of
+// the last one. It stops at the first error.
+func PostProcess(data []byte, fns ...PostProcessor) ([]byte, error) {
+	for _, fn := range fns {
+		var err error
+		if data, err = fn(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// WithPostProcessors passes the document through fns before it is
+// written.
+func WithPostProcessors(fns ...PostProcessor) WriteOption {
+	return func(c *writeConfig) {
+		c.post = append(c.post, fns...)
+	}
+}
+
+// UnicodeFractions writes the fractional parts of amounts with unicode
+// vulgar fraction characters where there is one, e.g. "1½ cups".
+func UnicodeFractions() WriteOption {
+	return func(c *writeConfig) {
+		c.amount = Amount.UnicodeString
+	}
+}
+
+// WriteMarkdown writes r to w as a RecipeMD document laid out like the
+// output of Format. Pinned ingredients are written with the pin marker so
+// the document parses back to an equal recipe. Dividers are written where
+// the content needs them and where r.Sections has an empty ingredients or
+// instructions section.
+func WriteMarkdown(w io.Writer, r *Recipe, opts ...WriteOption) error {
+	c := writeConfig{amount: Amount.String}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	out, err := PostProcess(markdownOf(r, &c), c.post...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// markdownOf returns the document WriteMarkdown writes for r, before post
+// processing.
+func markdownOf(r *Recipe, c *writeConfig) []byte {
+	var b bytes.Buffer
+	block := func(s string) {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(s)
+		b.WriteString("\n")
+	}
+	block("# " + escapeMarkdown(r.Title))
+	if r.Description != "" {
+		block(r.Description)
+	}
+	if len(r.Tags) > 0 {
+		tags := make([]string, len(r.Tags))
+		for i, t := range r.Tags {
+			tags[i] = escapeMarkdown(t)
+		}
+		block("*" + strings.Join(tags, ", ") + "*")
+	}
+	if len(r.Yields) > 0 {
+		yields := make([]string, len(r.Yields))
+		for i, y := range r.Yields {
+			yields[i] = escapeMarkdown(c.amount(y))
+		}
+		block("**" + strings.Join(yields, ", ") + "**")
+	}
+	instructions := r.fullInstructions()
+	hasInstructions := instructions != "" || r.HasSection(SectionInstructions)
+	if len(r.Ingredients) > 0 || len(r.IngredientNotes) > 0 || len(r.IngredientGroups) > 0 || hasInstructions || r.HasSection(SectionIngredients) {
+		block("---")
+		writeIngredients(block, r.Ingredients, r.IngredientNotes, r.IngredientGroups, 2, c.amount)
+	}
+	if hasInstructions {
+		block("---")
+		if instructions != "" {
+			block(instructions)
+		}
+	}
+	return b.Bytes()
+}
+
+func writeIngredients(block func(string), ingredients []Ingredient, notes []Note, groups []IngredientGroup, level int, amount func(Amount) string) {
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			block(b.String())
+			b.Reset()
+		}
+	}
+	for i := 0; i <= len(ingredients); i++ {
+		for _, n := range notes {
+			if n.Index == i || (i == len(ingredients) && n.Index > i) {
+				flush()
+				block(n.Text)
+			}
+		}
+		if i == len(ingredients) {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- " + ingredientMarkdown(ingredients[i], amount))
+		if note := ingredients[i].Note; note != "" {
+			b.WriteString("\n\n  " + strings.ReplaceAll(note, "\n", "\n  "))
+		}
+		if img := ingredients[i].Image; img != nil {
+			b.WriteString("\n\n  " + imageMarkdown(*img))
+		}
+	}
+	flush()
+	for _, g := range groups {
+		block(strings.Repeat("#", min(level, 6)) + " " + escapeMarkdown(g.Title))
+		if g.Image != nil {
+			block(imageMarkdown(*g.Image))
+		}
+		writeIngredients(block, g.Ingredients, g.Notes, g.IngredientGroups, level+1, amount)
+	}
+}
+
+func ingredientMarkdown(in Ingredient, amount func(Amount) string) string {
+	var s string
+	if in.Amount != nil || in.Pinned {
+		a := ""
+		if in.Amount != nil {
+			a = amount(*in.Amount)
+		}
+		if in.Pinned {
+			a = extension.PinMarker + a
+		}
+		s = "*" + escapeMarkdown(a) + "* "
+	}
+	if md, ok := in.markdown(); ok {
+		return s + md
+	}
+	name := escapeMarkdown(in.text())
+	if in.Link != "" {
+		link := in.Link
+		if strings.ContainsAny(link, " ()") {
+			link = "<" + link + ">"
+		}
+		name = "[" + name + "](" + link + ")"
+	}
+	return s + name
+}
+
+// markdown returns the Markdown of in and whether it can be written: its
+// text and link still match in, and, without an amount, it does not
+// start with an emphasis that would be read as one.
+func (in Ingredient) markdown() (string, bool) {
+	if in.Markdown == "" {
+		return "", false
+	}
+	source := []byte(in.Markdown)
+	p := plain.Parser().Parse(text.NewReader(source)).FirstChild()
+	if p == nil || p.Kind() != gast.KindParagraph || p.NextSibling() != nil {
+		return "", false
+	}
+	if e, ok := p.FirstChild().(*gast.Emphasis); ok && e.Level == 1 && in.Amount == nil && !in.Pinned {
+		return "", false
+	}
+	name, preparation, optional := extension.SplitName(extension.PlainText(p, source))
+	if name != in.Name || preparation != in.Preparation || optional != in.Optional {
+		return "", false
+	}
+	link := ""
+	if l, ok := p.FirstChild().(*gast.Link); ok && l.NextSibling() == nil {
+		link = string(l.Destination)
+	}
+	return in.Markdown, link == in.Link
+}
+
+func imageMarkdown(img Image) string {
+	url := img.URL
+	if strings.ContainsAny(url, " ()") {
+		url = "<" + url + ">"
+	}
+	if img.Title != "" {
+		url += ` "` + strings.ReplaceAll(img.Title, `"`, `\"`) + `"`
+	}
+	return "![" + escapeMarkdown(img.Alt) + "](" + url + ")"
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	"`", "\\`",
+	`<`, `\<`,
+)
+
+// escapeMarkdown escapes the characters of plain text that would otherwise
+// be read as inline markup.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
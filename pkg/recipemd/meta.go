@@ -0,0 +1,62 @@
+package recipemd
+
+import (
+	"bytes"
+	"html"
+	"io"
+	"net/url"
+)
+
+// socialSummaryLength is the maximum length of the summary in
+// og:description.
+const socialSummaryLength = 160
+
+// WriteSocialMeta writes the Open Graph and Twitter card meta elements for
+// the head of a page showing r, so links to it unfurl with its title,
+// summary and first image: og:type, og:title, og:description, og:image
+// with og:image:alt, og:url and twitter:card, a large image card if r has
+// an image. Twitter takes the title, description and image from the Open
+// Graph elements.
+//
+// pageURL is the address the page is published at. The protocols require
+// absolute URLs, so relative image paths are resolved against it. If it
+// is empty, og:url is left out and the image is written as it is.
+func WriteSocialMeta(w io.Writer, r *Recipe, pageURL string) error {
+	var b bytes.Buffer
+	meta := func(attr, name, content string) {
+		if content == "" {
+			return
+		}
+		b.WriteString(`<meta ` + attr + `="` + name + `" content="`)
+		b.WriteString(html.EscapeString(content))
+		b.WriteString("\">\n")
+	}
+	meta("property", "og:type", "article")
+	meta("property", "og:title", r.Title)
+	meta("property", "og:description", r.Summary(socialSummaryLength))
+	var base *url.URL
+	if pageURL != "" {
+		u, err := url.Parse(pageURL)
+		if err != nil {
+			return err
+		}
+		base = u
+		meta("property", "og:url", pageURL)
+	}
+	card := "summary"
+	if len(r.Images) > 0 {
+		img := r.Images[0]
+		src := img.URL
+		if base != nil {
+			if u, err := url.Parse(src); err == nil {
+				src = base.ResolveReference(u).String()
+			}
+		}
+		meta("property", "og:image", src)
+		meta("property", "og:image:alt", img.Alt)
+		card = "summary_large_image"
+	}
+	meta("name", "twitter:card", card)
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,85 @@
+package recipemd
+
+import (
+	"bytes"
+	"errors"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Split splits a document holding several recipes, such as an archive of
+// recipes concatenated into one file, into the source of each of them.
+// Every first-level heading with text, outside lists, quotes and code
+// blocks, starts a recipe, so the recipes cannot have appendices. Text
+// before the first heading is a part of its own unless it is blank. The
+// parts are slices of source.
+func Split(source []byte) [][]byte {
+	starts := recipeStarts(source)
+	parts := make([][]byte, len(starts))
+	for i, start := range starts {
+		end := len(source)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		parts[i] = source[start:end]
+	}
+	return parts
+}
+
+// recipeStarts returns the offsets of the parts Split splits source into.
+func recipeStarts(source []byte) []int {
+	doc := plain.Parser().Parse(text.NewReader(source))
+	var starts []int
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		h, ok := c.(*gast.Heading)
+		if !ok || h.Level != 1 || h.Lines().Len() == 0 {
+			continue
+		}
+		start := lineStart(source, h.Lines().At(0).Start)
+		if len(starts) == 0 && len(bytes.TrimSpace(source[:start])) > 0 {
+			starts = append(starts, 0)
+		}
+		starts = append(starts, start)
+	}
+	if len(starts) == 0 && len(bytes.TrimSpace(source)) > 0 {
+		starts = append(starts, 0)
+	}
+	return starts
+}
+
+// ParseAll parses the recipes of a document holding several of them, as
+// split by Split, in document order. Recipes that fail to parse are left
+// out and their errors returned, one per recipe; the positions of the
+// *Diagnostic errors are positions in source. WithMaxSize limits the size
+// of source, the other options apply to each recipe.
+func ParseAll(source []byte, opts ...ParseOption) ([]*Recipe, []error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.checkSize(source); err != nil {
+		return nil, []error{err}
+	}
+	opts = append(opts[:len(opts):len(opts)], WithMaxSize(0))
+	var recipes []*Recipe
+	var errs []error
+	starts := recipeStarts(source)
+	for i, start := range starts {
+		end := len(source)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		r, err := Parse(source[start:end], opts...)
+		if err != nil {
+			var d *Diagnostic
+			if errors.As(err, &d) {
+				d.Pos = PositionAt(source, start+d.Pos.Offset)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		recipes = append(recipes, r)
+	}
+	return recipes, errs
+}
@@ -0,0 +1,99 @@
+package recipemd
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// Output is what md.Convert writes for a document with an Extension.
+type Output int
+
+const (
+	HTML     Output = iota // schema.org microdata, as the RecipeMD extension renders it
+	JSON                   // the recipe JSON of the json format
+	Markdown               // the canonical markdown of WriteMarkdown
+)
+
+// ExtensionOption configures the extension Extension returns.
+type ExtensionOption func(*extensionConfig)
+
+type extensionConfig struct {
+	output Output
+	opts   []extension.Option
+}
+
+// WithOutput makes md.Convert write the recipe of the document as output
+// instead of HTML.
+func WithOutput(output Output) ExtensionOption {
+	return func(c *extensionConfig) {
+		c.output = output
+	}
+}
+
+// WithExtensionOptions configures the RecipeMD extension that Extension
+// wraps with opts.
+func WithExtensionOptions(opts ...extension.Option) ExtensionOption {
+	return func(c *extensionConfig) {
+		c.opts = append(c.opts, opts...)
+	}
+}
+
+// Extension returns the RecipeMD extension of extension.New, whose output
+// WithOutput selects:
+//
+//	md := goldmark.New(goldmark.WithExtensions(recipemd.Extension(recipemd.WithOutput(recipemd.JSON))))
+//	err := md.Convert(source, w) // writes the JSON of the recipe
+//
+// For JSON and Markdown, the whole document is rendered at once from the
+// Recipe that ExtractRecipe builds, and Convert returns the *Diagnostic of
+// a document that is not a valid recipe. Like the HTML renderer, that
+// renderer keeps no state between calls.
+func Extension(opts ...ExtensionOption) goldmark.Extender {
+	var c extensionConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+func (c *extensionConfig) Extend(m goldmark.Markdown) {
+	extension.New(c.opts...).Extend(m)
+	if c.output == HTML {
+		return
+	}
+	m.Renderer().AddOptions(
+		// takes precedence over the renderers of the extension (500) and
+		// goldmark (1000)
+		renderer.WithNodeRenderers(util.Prioritized(documentRenderer{c.output}, 100)),
+	)
+}
+
+// documentRenderer renders a whole document as the JSON or markdown of its
+// recipe.
+type documentRenderer struct {
+	output Output
+}
+
+func (r documentRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(gast.KindDocument, r.renderDocument)
+}
+
+func (r documentRenderer) renderDocument(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	recipe, err := ExtractRecipe(n, source)
+	if err != nil {
+		return gast.WalkStop, err
+	}
+	if r.output == Markdown {
+		err = WriteMarkdown(w, recipe)
+	} else {
+		err = writeJSON(w, recipe)
+	}
+	return gast.WalkStop, err
+}
@@ -0,0 +1,56 @@
+package recipemd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+)
+
+func TestExtensionOutput(t *testing.T) {
+	r, err := Parse([]byte(pancakes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var markdown bytes.Buffer
+	if err := WriteMarkdown(&markdown, r); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		output Output
+		check  func(out string) bool
+	}{
+		{"html", HTML, func(out string) bool { return strings.Contains(out, `<h1 id="pancakes" itemprop="name">Pancakes</h1>`) }},
+		{"json", JSON, func(out string) bool {
+			var got Recipe
+			return json.Unmarshal([]byte(out), &got) == nil && got.Title == "Pancakes" && len(got.AllIngredients()) == 5
+		}},
+		{"markdown", Markdown, func(out string) bool { return out == markdown.String() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := goldmark.New(goldmark.WithExtensions(Extension(WithOutput(tt.output))))
+			var b bytes.Buffer
+			if err := md.Convert([]byte(pancakes), &b); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(b.String()) {
+				t.Errorf("unexpected output:\n%s", &b)
+			}
+		})
+	}
+}
+
+func TestExtensionOutputError(t *testing.T) {
+	md := goldmark.New(goldmark.WithExtensions(Extension(WithOutput(JSON))))
+	var b bytes.Buffer
+	if err := md.Convert([]byte("Just text.\n"), &b); !errors.Is(err, diag.ErrMissingTitle) {
+		t.Errorf("Convert error = %v, want %s", err, diag.ErrMissingTitle)
+	}
+}
@@ -0,0 +1,395 @@
+package recipemd
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+var markdown = goldmark.New(goldmark.WithExtensions(extension.RecipeMD))
+
+// ParseOption configures Parse and ExtractRecipe.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	nameCase       NameCase
+	appendices     bool
+	steps          bool
+	titleFallback  string
+	maxSize        int
+	maxIngredients int
+	maxGroupDepth  int
+}
+
+// NameCase selects how ingredient names are normalized.
+type NameCase int
+
+// Ingredient name casings.
+const (
+	PreserveCase NameCase = iota // names as written
+	LowerCase                    // "Brown Sugar" becomes "brown sugar"
+	SentenceCase                 // "brown SUGAR" becomes "Brown sugar"
+)
+
+// WithNameCase normalizes the casing of ingredient names so names that
+// differ only in case, like "Butter" and "butter", are equal when recipes
+// are aggregated. The source document is not changed.
+func WithNameCase(c NameCase) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.nameCase = c
+	}
+}
+
+// apply returns name in the configured casing.
+func (c NameCase) apply(name string) string {
+	switch c {
+	case LowerCase:
+		return strings.ToLower(name)
+	case SentenceCase:
+		if name == "" {
+			return name
+		}
+		r, size := utf8.DecodeRuneInString(name)
+		return strings.ToUpper(string(r)) + strings.ToLower(name[size:])
+	}
+	return name
+}
+
+// Parse parses a RecipeMD document.
+func Parse(source []byte, opts ...ParseOption) (*Recipe, error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.checkSize(source); err != nil {
+		return nil, err
+	}
+	source, _ = InsertTitle(source, cfg.titleFallback)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	return ExtractRecipe(doc, source, opts...)
+}
+
+// ExtractRecipe builds a Recipe from a document parsed from source. A
+// document parsed without the RecipeMD extension, or with it in
+// non-destructive mode, has no recipe nodes; source is then parsed again
+// with the extension. If the document does not follow the RecipeMD
+// structure the returned error is a *Diagnostic locating the first
+// problem.
+func ExtractRecipe(doc gast.Node, source []byte, opts ...ParseOption) (*Recipe, error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.checkSize(source); err != nil {
+		return nil, err
+	}
+	if _, ok := doc.FirstChild().(*ast.Recipe); !ok {
+		doc = markdown.Parser().Parse(text.NewReader(source))
+	}
+	d := &diagnostics{source: source}
+	r := extract(doc, d, &cfg)
+	if err := d.firstError(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate parses source and returns every problem found, in document
+// order. A document without error diagnostics parses successfully.
+func Validate(source []byte) []Diagnostic {
+	_, d := validate(source, &parseConfig{})
+	return d.sorted()
+}
+
+// validate parses source and extracts the recipe, collecting every
+// diagnostic instead of stopping at the first error.
+func validate(source []byte, cfg *parseConfig) (*Recipe, *diagnostics) {
+	pc := parser.NewContext()
+	doc := markdown.Parser().Parse(text.NewReader(source), parser.WithContext(pc))
+	d := &diagnostics{source: source}
+	for _, b := range extension.DroppedBlocks(pc) {
+		d.report(b, diag.WarnDroppedBlock, "%s is lost in parsing", kindName(b))
+	}
+	return extract(doc, d, cfg), d
+}
+
+func extract(doc gast.Node, d *diagnostics, cfg *parseConfig) *Recipe {
+	node, ok := doc.FirstChild().(*ast.Recipe)
+	if !ok {
+		d.report(doc.FirstChild(), diag.ErrMissingTitle, "missing title: a recipe must start with a first-level heading")
+		return nil
+	}
+	r := &Recipe{}
+	var hasTags, hasYields bool
+	count := 0
+	for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Title:
+			r.Sections = append(r.Sections, SectionTitle)
+			r.Title = c.Title
+			if r.Title == "" {
+				d.report(c, diag.ErrEmptyTitle, "missing title: the first-level heading is empty")
+			}
+		case *ast.Description:
+			r.Sections = append(r.Sections, SectionDescription)
+			r.Description = linesText(c.Lines(), d.source)
+			t := extension.ParseTimes(c, d.source)
+			r.PrepTime, r.CookTime, r.TotalTime = t.Prep, t.Cook, t.Total
+			r.Images = append(r.Images, images(c, d.source)...)
+		case *ast.Tags:
+			switch {
+			case hasTags:
+				d.report(c, diag.ErrDuplicateTags, "tags given more than once")
+			case hasYields:
+				d.report(c, diag.ErrYieldsBeforeTags, "tags must come before yields")
+			}
+			if !hasTags {
+				r.Sections = append(r.Sections, SectionTags)
+			}
+			hasTags = true
+			r.Tags = append(r.Tags, c.Tags...)
+		case *ast.Yields:
+			if hasYields {
+				d.report(c, diag.ErrDuplicateYields, "yields given more than once")
+			}
+			if !hasYields {
+				r.Sections = append(r.Sections, SectionYields)
+			}
+			hasYields = true
+			for _, y := range c.Yields {
+				if y.Factor == nil {
+					d.report(c, diag.WarnYieldWithoutAmount, "yield %q has no amount", y.Unit)
+				}
+			}
+			r.Yields = append(r.Yields, c.Yields...)
+		case *ast.Ingredients:
+			r.Sections = append(r.Sections, SectionIngredients)
+			extractIngredients(c, &r.Ingredients, &r.IngredientNotes, &r.IngredientGroups, &count, d, cfg)
+		case *ast.Instructions:
+			r.Sections = append(r.Sections, SectionInstructions)
+			if cfg.appendices {
+				r.Instructions, r.Appendices = splitAppendices(c, d.source)
+			} else {
+				r.Instructions = linesText(c.Lines(), d.source)
+			}
+			r.Images = append(r.Images, images(c, d.source)...)
+		default:
+			if c.Kind() == gast.KindThematicBreak {
+				continue
+			}
+			d.report(c, diag.ErrMissingDivider, "unexpected %s after tags and yields, expected a divider", kindName(c))
+		}
+	}
+	if cfg.steps {
+		r.InstructionSteps = r.Steps()
+	}
+	return r
+}
+
+// extractIngredients appends the ingredients, notes and groups of n;
+// count is the number of ingredients of the recipe so far.
+func extractIngredients(n gast.Node, ingredients *[]Ingredient, notes *[]Note, groups *[]IngredientGroup, count *int, d *diagnostics, cfg *parseConfig) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.IngredientGroup:
+			if cfg.maxGroupDepth > 0 && groupDepth(c) > cfg.maxGroupDepth {
+				d.report(c, diag.ErrTooDeep, "ingredient group %q is nested deeper than %d levels", c.Title, cfg.maxGroupDepth)
+				continue
+			}
+			g := IngredientGroup{Title: c.Title}
+			if img, ok := extension.ImageParagraph(c.FirstChild().NextSibling()); ok {
+				g.Image = imageOf(img, d.source)
+			}
+			extractIngredients(c, &g.Ingredients, &g.Notes, &g.IngredientGroups, count, d, cfg)
+			if len(g.Ingredients) == 0 && len(g.IngredientGroups) == 0 {
+				d.report(c, diag.WarnEmptyGroup, "ingredient group %q is empty", g.Title)
+			}
+			*groups = append(*groups, g)
+		case *ast.Ingredient:
+			if cfg.maxIngredients > 0 && *count >= cfg.maxIngredients {
+				if *count == cfg.maxIngredients {
+					d.report(c, diag.ErrTooManyIngredients, "more than %d ingredients", cfg.maxIngredients)
+				}
+				*count++
+				continue
+			}
+			*count++
+			i := Ingredient{Link: c.Link, Pinned: c.Pinned, Markdown: formattedText(c, d.source)}
+			i.setText(c.Name)
+			i.Name = cfg.nameCase.apply(i.Name)
+			if c.Amount != nil {
+				a := *c.Amount
+				i.Amount = &a
+				if a.Factor == nil {
+					d.report(c, diag.WarnUnparseableAmount, "amount %q of %q is not a number", a.Unit, c.Name)
+				}
+			}
+			if i.Name == "" {
+				d.report(c, diag.ErrEmptyIngredientName, "ingredient has no name")
+			}
+			*ingredients = append(*ingredients, i)
+			idx := len(*ingredients) - 1
+			// further paragraphs and nested lists; the first block is the
+			// ingredient's text
+			var paras []string
+			for b := c.FirstChild(); b != nil; b = b.NextSibling() {
+				switch {
+				case b.Kind() == gast.KindList:
+					extractIngredients(b, ingredients, notes, groups, count, d, cfg)
+				case b == c.FirstChild():
+				case (*ingredients)[idx].Image == nil && isImageParagraph(b):
+					img, _ := extension.ImageParagraph(b)
+					(*ingredients)[idx].Image = imageOf(img, d.source)
+				case b.Kind() == gast.KindParagraph || b.Kind() == gast.KindTextBlock:
+					paras = append(paras, paragraph(b, d.source))
+				default:
+					d.report(b, diag.WarnIgnoredBlock, "%s in an ingredient is ignored", kindName(b))
+				}
+			}
+			(*ingredients)[idx].Note = strings.Join(paras, "\n\n")
+		case *gast.List:
+			extractIngredients(c, ingredients, notes, groups, count, d, cfg)
+		case *gast.Paragraph:
+			if n.Kind() == ast.KindIngredientGroup && c.PreviousSibling() == n.FirstChild() && isImageParagraph(c) {
+				continue // the image of the group
+			}
+			*notes = append(*notes, Note{Text: paragraph(c, d.source), Index: len(*ingredients)})
+		default:
+			// the heading of a group
+			if n.Kind() == ast.KindIngredientGroup && c == n.FirstChild() {
+				continue
+			}
+			d.report(c, diag.WarnIgnoredBlock, "%s in the ingredient section is ignored", kindName(c))
+		}
+	}
+}
+
+// images returns the images in n.
+func images(n gast.Node, source []byte) []Image {
+	var list []Image
+	_ = gast.Walk(n, func(c gast.Node, entering bool) (gast.WalkStatus, error) {
+		if img, ok := c.(*gast.Image); ok && entering {
+			list = append(list, *imageOf(img, source))
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	return list
+}
+
+// paragraph returns the markdown of the paragraph n.
+func paragraph(n gast.Node, source []byte) string {
+	var lines []string
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		lines = append(lines, strings.TrimSpace(string(line.Value(source))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formattedText returns the markdown of the text of the ingredient n
+// after its amount, on one line, if it has inline formatting other than a
+// link of the whole name, or "" otherwise.
+func formattedText(n *ast.Ingredient, source []byte) string {
+	block := n.FirstChild()
+	if block == nil || (block.Kind() != gast.KindParagraph && block.Kind() != gast.KindTextBlock) || block.Lines().Len() == 0 {
+		return ""
+	}
+	start := block.Lines().At(0).Start
+	formatted := false
+	for c := block.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Amount:
+			_, end := inlineSpan(c)
+			if end < 0 {
+				return ""
+			}
+			start = end + 1 // past the closing delimiter
+		case *gast.Text, *gast.String:
+		case *gast.Link:
+			formatted = formatted || n.Link == "" || len(c.Title) > 0 || !plainInline(c)
+		default:
+			formatted = true
+		}
+	}
+	if !formatted {
+		return ""
+	}
+	var parts []string
+	lines := block.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		if line.Stop <= start {
+			continue
+		}
+		line.Start = max(line.Start, start)
+		if s := strings.TrimSpace(string(line.Value(source))); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// plainInline reports whether the children of the inline node n are text
+// only.
+func plainInline(n gast.Node) bool {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if c.Kind() != gast.KindText && c.Kind() != gast.KindString {
+			return false
+		}
+	}
+	return true
+}
+
+// kindName returns a lower case name for the kind of n.
+func kindName(n gast.Node) string {
+	var b strings.Builder
+	for i, r := range n.Kind().String() {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strings.ToLower(string(r)))
+	}
+	return b.String()
+}
+
+// imageOf returns the Image of the image node img.
+func imageOf(img *gast.Image, source []byte) *Image {
+	return &Image{
+		URL:   string(img.Destination),
+		Alt:   extension.PlainText(img, source),
+		Title: string(img.Title),
+	}
+}
+
+// isImageParagraph reports whether n is a paragraph of just an image.
+func isImageParagraph(n gast.Node) bool {
+	_, ok := extension.ImageParagraph(n)
+	return ok
+}
+
+// linesText returns the text of lines in source. Unlike
+// string(lines.Value(source)), which copies a long section twice, it
+// copies it once.
+func linesText(lines *text.Segments, source []byte) string {
+	size := 0
+	for i := range lines.Len() {
+		seg := lines.At(i)
+		size += seg.Len() + 1
+	}
+	var b strings.Builder
+	b.Grow(size)
+	for i := range lines.Len() {
+		seg := lines.At(i)
+		b.Write(seg.Value(source))
+	}
+	return b.String()
+}
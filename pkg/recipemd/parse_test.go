@@ -0,0 +1,141 @@
+package recipemd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+)
+
+const pancakes = `# Pancakes
+
+Fluffy pancakes for a *lazy* Sunday.
+
+*breakfast, sweet*
+
+**4 servings, 12 pancakes**
+
+---
+
+- *200 g* flour
+- *2* eggs
+- *1 1/2 cups* milk, warm
+- salt
+
+## Topping
+
+- *2 tbsp* [maple syrup](syrup.md)
+
+---
+
+Whisk everything and fry in a hot pan.
+`
+
+func TestParse(t *testing.T) {
+	r, err := Parse([]byte(pancakes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Title != "Pancakes" {
+		t.Errorf("Title = %q, want %q", r.Title, "Pancakes")
+	}
+	if want := "Fluffy pancakes for a *lazy* Sunday."; r.Description != want {
+		t.Errorf("Description = %q, want %q", r.Description, want)
+	}
+	if want := []string{"breakfast", "sweet"}; !slices.Equal(r.Tags, want) {
+		t.Errorf("Tags = %q, want %q", r.Tags, want)
+	}
+	var yields []string
+	for _, y := range r.Yields {
+		yields = append(yields, y.String())
+	}
+	if want := []string{"4 servings", "12 pancakes"}; !slices.Equal(yields, want) {
+		t.Errorf("Yields = %q, want %q", yields, want)
+	}
+	if want := "Whisk everything and fry in a hot pan."; r.Instructions != want {
+		t.Errorf("Instructions = %q, want %q", r.Instructions, want)
+	}
+
+	tests := []struct {
+		name, amount, link, preparation string
+	}{
+		{"flour", "200 g", "", ""},
+		{"eggs", "2", "", ""},
+		{"milk", "1.5 cups", "", "warm"},
+		{"salt", "", "", ""},
+		{"maple syrup", "2 tbsp", "syrup.md", ""},
+	}
+	all := r.AllIngredients()
+	if len(all) != len(tests) {
+		t.Fatalf("got %d ingredients, want %d", len(all), len(tests))
+	}
+	for i, tt := range tests {
+		in := all[i]
+		amount := ""
+		if in.Amount != nil {
+			amount = in.Amount.String()
+		}
+		if in.Name != tt.name || amount != tt.amount || in.Link != tt.link || in.Preparation != tt.preparation {
+			t.Errorf("ingredient %d = %q %q %q %q, want %q %q %q %q", i,
+				in.Name, amount, in.Link, in.Preparation, tt.name, tt.amount, tt.link, tt.preparation)
+		}
+	}
+	if len(r.IngredientGroups) != 1 || r.IngredientGroups[0].Title != "Topping" {
+		t.Errorf("IngredientGroups = %+v, want the group Topping", r.IngredientGroups)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		code   diag.Code
+		line   int
+	}{
+		{"no title", "Just text.\n", diag.ErrMissingTitle, 1},
+		{"empty title", "#\n\n---\n", diag.ErrEmptyTitle, 1},
+		{"yields before tags", "# T\n\n**2 servings**\n\n*tag*\n", diag.ErrYieldsBeforeTags, 5},
+		{"duplicate tags", "# T\n\n*a*\n\n*b*\n", diag.ErrDuplicateTags, 5},
+		{"empty ingredient name", "# T\n\n---\n\n- *1 cup*\n", diag.ErrEmptyIngredientName, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse([]byte(tt.source))
+			if err == nil {
+				t.Fatalf("Parse succeeded with %+v, want an error", r)
+			}
+			if !errors.Is(err, tt.code) {
+				t.Errorf("Parse error = %v, want %s", err, tt.code)
+			}
+			var d *Diagnostic
+			if errors.As(err, &d) && d.Pos.Line != tt.line {
+				t.Errorf("error on line %d, want %d", d.Pos.Line, tt.line)
+			}
+		})
+	}
+}
+
+func TestParseNameCase(t *testing.T) {
+	source := []byte("# T\n\n---\n\n- *1* Brown SUGAR\n")
+	tests := []struct {
+		c    NameCase
+		want string
+	}{
+		{PreserveCase, "Brown SUGAR"},
+		{LowerCase, "brown sugar"},
+		{SentenceCase, "Brown sugar"},
+	}
+	for _, tt := range tests {
+		r, err := Parse(source, WithNameCase(tt.c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Ingredients[0].Name; got != tt.want {
+			t.Errorf("name with case %d = %q, want %q", tt.c, got, tt.want)
+		}
+		if _, err := Parse([]byte("# T\n\n---\n\n- *1 cup*\n"), WithNameCase(tt.c)); !errors.Is(err, diag.ErrEmptyIngredientName) {
+			t.Errorf("empty name with case %d: error %v, want %s", tt.c, err, diag.ErrEmptyIngredientName)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package recipemd
+
+import (
+	"fmt"
+
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+)
+
+// Section is a part of a recipe document.
+type Section string
+
+// Sections of a recipe, in document order.
+const (
+	SectionTitle        Section = "title"
+	SectionDescription  Section = "description"
+	SectionTags         Section = "tags"
+	SectionYields       Section = "yields"
+	SectionIngredients  Section = "ingredients"
+	SectionInstructions Section = "instructions"
+)
+
+var sections = []Section{SectionTitle, SectionDescription, SectionTags, SectionYields, SectionIngredients, SectionInstructions}
+
+// sectionOf maps the codes of diagnostics to the section they affect.
+var sectionOf = map[diag.Code]Section{
+	diag.ErrMissingTitle:        SectionTitle,
+	diag.ErrEmptyTitle:          SectionTitle,
+	diag.ErrMissingDivider:      SectionDescription,
+	diag.ErrDuplicateTags:       SectionTags,
+	diag.ErrYieldsBeforeTags:    SectionTags,
+	diag.ErrDuplicateYields:     SectionYields,
+	diag.WarnYieldWithoutAmount: SectionYields,
+	diag.ErrEmptyIngredientName: SectionIngredients,
+	diag.WarnUnparseableAmount:  SectionIngredients,
+	diag.WarnEmptyGroup:         SectionIngredients,
+	diag.WarnIgnoredBlock:       SectionIngredients,
+	diag.ErrTooManyIngredients:  SectionIngredients,
+	diag.ErrTooDeep:             SectionIngredients,
+}
+
+// Status is how completely a section was extracted.
+type Status string
+
+// Statuses of sections.
+const (
+	StatusComplete   Status = "complete"   // no problems
+	StatusWarning    Status = "warning"    // extracted, with warnings
+	StatusIncomplete Status = "incomplete" // errors; content may be missing or misplaced
+)
+
+// SectionReport is the status of one section and the diagnostics that
+// affect it.
+type SectionReport struct {
+	Section     Section      `json:"section"`
+	Status      Status       `json:"status"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// Fix is a change ParsePartial made to a document before extracting the
+// recipe, such as a title inserted by WithTitleFallback.
+type Fix struct {
+	Section Section `json:"section"`
+	Message string  `json:"message"`
+}
+
+// Completeness reports how much of a document ParsePartial extracted.
+// Sections lists every section in document order; Diagnostics holds all
+// problems, including those not tied to a section. Fixes lists the changes
+// applied to the document; the positions of diagnostics refer to the
+// changed document.
+type Completeness struct {
+	Sections    []SectionReport `json:"sections"`
+	Diagnostics []Diagnostic    `json:"diagnostics"`
+	Fixes       []Fix           `json:"fixes,omitempty"`
+}
+
+// Complete reports whether no section has errors, that is whether Parse
+// would have succeeded.
+func (c *Completeness) Complete() bool {
+	for _, s := range c.Sections {
+		if s.Status == StatusIncomplete {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns the first error diagnostic, the error Parse would have
+// returned, or nil.
+func (c *Completeness) Err() error {
+	for i := range c.Diagnostics {
+		if c.Diagnostics[i].Severity == SeverityError {
+			return &c.Diagnostics[i]
+		}
+	}
+	return nil
+}
+
+// Usable reports whether the recipe has a title, the least an index needs
+// to list it.
+func (c *Completeness) Usable() bool {
+	return c.Section(SectionTitle).Status != StatusIncomplete
+}
+
+// Section returns the report of the section s.
+func (c *Completeness) Section(s Section) SectionReport {
+	for _, r := range c.Sections {
+		if r.Section == s {
+			return r
+		}
+	}
+	return SectionReport{Section: s, Status: StatusComplete}
+}
+
+// ParsePartial parses a RecipeMD document like Parse but does not give up
+// on errors: it returns whatever could be extracted together with a report
+// of which sections are complete. A document with malformed yields still
+// yields its title and ingredients, for example. The recipe is empty, but
+// not nil, if the document has no title or is larger than WithMaxSize
+// allows.
+func ParsePartial(source []byte, opts ...ParseOption) (*Recipe, *Completeness) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.checkSize(source); err != nil {
+		// nothing is parsed, so no section is complete
+		c := &Completeness{Diagnostics: []Diagnostic{*err}}
+		for _, s := range sections {
+			c.Sections = append(c.Sections, SectionReport{Section: s, Status: StatusIncomplete, Diagnostics: c.Diagnostics})
+		}
+		return &Recipe{}, c
+	}
+	var fixes []Fix
+	if fixed, ok := InsertTitle(source, cfg.titleFallback); ok {
+		source = fixed
+		fixes = append(fixes, Fix{Section: SectionTitle, Message: fmt.Sprintf("inserted missing title %q", cfg.titleFallback)})
+	}
+	r, d := validate(source, &cfg)
+	if r == nil {
+		r = &Recipe{}
+	}
+	c := &Completeness{Diagnostics: d.sorted(), Fixes: fixes}
+	for _, s := range sections {
+		report := SectionReport{Section: s, Status: StatusComplete}
+		for _, dg := range c.Diagnostics {
+			if sectionOf[dg.Code] != s {
+				continue
+			}
+			report.Diagnostics = append(report.Diagnostics, dg)
+			if dg.Severity == SeverityError {
+				report.Status = StatusIncomplete
+			} else if report.Status == StatusComplete {
+				report.Status = StatusWarning
+			}
+		}
+		c.Sections = append(c.Sections, report)
+	}
+	return r, c
+}
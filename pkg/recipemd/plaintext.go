@@ -0,0 +1,149 @@
+package recipemd
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+	"github.com/xcapaldi/recipemd-go/pkg/textwidth"
+)
+
+// WritePlainText writes r to w as plain text without markup, for printing
+// or pasting into places that do not render markdown. The title and
+// sections are underlined, ingredients are listed with their amounts and
+// the markdown of the texts is reduced to its words, keeping paragraphs,
+// list items and code blocks apart. The section labels are English unless
+// WithLang or WithLabels is given.
+func WritePlainText(w io.Writer, r *Recipe, opts ...WriteOption) error {
+	c := writeConfig{amount: Amount.String}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	var b bytes.Buffer
+	block := func(s string) {
+		if s == "" {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(s)
+		b.WriteString("\n")
+	}
+	labels := c.labels.Or(extension.LabelsFor(c.lang))
+	block(underline(r.Title, "="))
+	block(plainMarkdown(r.Description))
+	var meta []string
+	if len(r.Tags) > 0 {
+		meta = append(meta, labels.Tags+": "+strings.Join(r.Tags, ", "))
+	}
+	if len(r.Yields) > 0 {
+		yields := make([]string, len(r.Yields))
+		for i, y := range r.Yields {
+			yields[i] = c.amount(y)
+		}
+		meta = append(meta, labels.Yields+": "+strings.Join(yields, ", "))
+	}
+	block(strings.Join(meta, "\n"))
+	if len(r.Ingredients) > 0 || len(r.IngredientNotes) > 0 || len(r.IngredientGroups) > 0 {
+		block(underline(labels.Ingredients, "-"))
+		writePlainIngredients(block, r.Ingredients, r.IngredientNotes, r.IngredientGroups, c.amount)
+	}
+	if r.Instructions != "" {
+		block(underline(labels.Instructions, "-"))
+		block(plainMarkdown(r.Instructions))
+	}
+	for _, a := range r.Appendices {
+		block(underline(a.Title, "-"))
+		block(plainMarkdown(a.Text))
+	}
+	out, err := PostProcess(b.Bytes(), c.post...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// underline returns s followed by a line of c as wide as s.
+func underline(s, c string) string {
+	return s + "\n" + strings.Repeat(c, max(textwidth.Width(s), 1))
+}
+
+func writePlainIngredients(block func(string), ingredients []Ingredient, notes []Note, groups []IngredientGroup, amount func(Amount) string) {
+	var b strings.Builder
+	flush := func() {
+		block(b.String())
+		b.Reset()
+	}
+	for i := 0; i <= len(ingredients); i++ {
+		for _, n := range notes {
+			if n.Index == i || (i == len(ingredients) && n.Index > i) {
+				flush()
+				block(plainMarkdown(n.Text))
+			}
+		}
+		if i == len(ingredients) {
+			break
+		}
+		in := ingredients[i]
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- ")
+		if in.Amount != nil {
+			b.WriteString(amount(*in.Amount) + " ")
+		}
+		b.WriteString(in.text())
+		if note := plainMarkdown(in.Note); note != "" {
+			b.WriteString("\n  " + strings.ReplaceAll(note, "\n", "\n  "))
+		}
+	}
+	flush()
+	for _, g := range groups {
+		block(g.Title + ":")
+		writePlainIngredients(block, g.Ingredients, g.Notes, g.IngredientGroups, amount)
+	}
+}
+
+// plainMarkdown returns the text of the markdown s without markup, one
+// block per paragraph, list item or code block.
+func plainMarkdown(s string) string {
+	source := []byte(s)
+	doc := plain.Parser().Parse(text.NewReader(source))
+	var blocks []string
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *gast.List:
+			var items []string
+			for i, item := 0, c.FirstChild(); item != nil; i, item = i+1, item.NextSibling() {
+				marker := "- "
+				if c.IsOrdered() {
+					marker = strconv.Itoa(c.Start+i) + ". "
+				}
+				items = append(items, marker+extension.PlainText(item, source))
+			}
+			blocks = append(blocks, strings.Join(items, "\n"))
+		case *gast.CodeBlock, *gast.FencedCodeBlock:
+			var b strings.Builder
+			lines := c.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				b.WriteString("    ")
+				seg := lines.At(i)
+				b.Write(seg.Value(source))
+			}
+			blocks = append(blocks, strings.TrimRight(b.String(), "\n"))
+		case *gast.ThematicBreak:
+		default:
+			if t := extension.PlainText(c, source); t != "" {
+				blocks = append(blocks, t)
+			}
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
@@ -0,0 +1,306 @@
+// Package recipemd provides the RecipeMD recipe model and parses RecipeMD
+// documents into it.
+package recipemd
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// Amount is a quantity consisting of an optional factor and unit.
+type Amount = amount.Amount
+
+// Recipe is the structured content of a RecipeMD document. PrepTime,
+// CookTime and TotalTime are read from lines of the description like
+// "Prep time: 15 min" and are zero if it has none. Images are the images
+// of the description and instructions in document order. Both are
+// derived from the markdown: writing a recipe does not add them to it.
+type Recipe struct {
+	Title            string
+	Description      string
+	PrepTime         time.Duration
+	CookTime         time.Duration
+	TotalTime        time.Duration
+	Images           []Image
+	Tags             []string
+	Yields           []Amount
+	Ingredients      []Ingredient
+	IngredientNotes  []Note // prose between the ingredients
+	IngredientGroups []IngredientGroup
+	Instructions     string
+	Appendices       []Appendix // only parsed WithAppendices
+	InstructionSteps []Step     // only set WithInstructionSteps
+
+	// Sections lists the sections of the parsed document in document
+	// order. A document has the ingredients section if it has a divider
+	// and the instructions section if it has two, even if they are empty;
+	// writing the recipe keeps their dividers. It is nil for recipes that
+	// were not parsed and is not part of the JSON.
+	Sections []Section
+}
+
+// HasSection reports whether the document r was parsed from has the
+// section s.
+func (r *Recipe) HasSection(s Section) bool {
+	return slices.Contains(r.Sections, s)
+}
+
+// Image is an image of a recipe. URL is the destination as written, which
+// may be relative to the recipe file, Alt the plain text of its
+// description and Title its title, if any.
+type Image struct {
+	URL   string `json:"url"`
+	Alt   string `json:"alt"`
+	Title string `json:"title,omitempty"`
+}
+
+// Ingredient is a single entry of an ingredient list. Amount is nil if the
+// ingredient has no amount and Link is empty if its name is not a link.
+// Pinned ingredients keep their amount when the recipe is scaled. Note
+// holds the markdown of further paragraphs of the list item, except a
+// paragraph of just an image, which is the ingredient's Image.
+//
+// The text after the amount is split into the Name, a Preparation note
+// after the first comma and whether it is marked Optional: "butter,
+// softened (optional)" is the name "butter" with the preparation
+// "softened". Text keeps it as written and is written back as long as
+// Name, Preparation and Optional still match it.
+//
+// Markdown is the text after the amount as markdown, if it has inline
+// formatting such as "**dark** chocolate" or a link that is not the whole
+// name. It is written back, keeping the formatting, as long as its text
+// still matches Name, Preparation and Optional and its link Link.
+type Ingredient struct {
+	Name        string
+	Amount      *Amount
+	Link        string
+	Pinned      bool
+	Note        string
+	Preparation string
+	Optional    bool
+	Text        string
+	Markdown    string
+	Image       *Image
+}
+
+// setText sets the text of in and the name, preparation and optional
+// marker split from it.
+func (in *Ingredient) setText(text string) {
+	in.Text = text
+	in.Name, in.Preparation, in.Optional = extension.SplitName(text)
+}
+
+// text returns the text of in as it is written in a document.
+func (in Ingredient) text() string {
+	if in.Text != "" {
+		if n, p, o := extension.SplitName(in.Text); n == in.Name && p == in.Preparation && o == in.Optional {
+			return in.Text
+		}
+	}
+	s := in.Name
+	if in.Preparation != "" {
+		s += ", " + in.Preparation
+	}
+	if in.Optional {
+		s += " (optional)"
+	}
+	return s
+}
+
+// IngredientGroup is a titled group of ingredients which may contain
+// further groups.
+type IngredientGroup struct {
+	Title            string
+	Ingredients      []Ingredient
+	Notes            []Note // prose between the ingredients
+	IngredientGroups []IngredientGroup
+	Image            *Image // a paragraph of just an image after the title
+}
+
+// Note is a paragraph in an ingredient section outside the lists, such as
+// "For the topping you can also use:". Text is its markdown and Index the
+// number of ingredients of the recipe or group before it.
+type Note struct {
+	Text  string `json:"text"`
+	Index int    `json:"index"`
+}
+
+// AllIngredients returns the ingredients of r and of all its groups in
+// document order.
+func (r *Recipe) AllIngredients() []Ingredient {
+	ingredients := append([]Ingredient(nil), r.Ingredients...)
+	for _, g := range r.IngredientGroups {
+		ingredients = append(ingredients, g.AllIngredients()...)
+	}
+	return ingredients
+}
+
+// AllIngredients returns the ingredients of g and of all its subgroups in
+// document order.
+func (g *IngredientGroup) AllIngredients() []Ingredient {
+	ingredients := append([]Ingredient(nil), g.Ingredients...)
+	for _, sub := range g.IngredientGroups {
+		ingredients = append(ingredients, sub.AllIngredients()...)
+	}
+	return ingredients
+}
+
+// IngredientImages returns the images of the ingredient groups and
+// ingredients of r in document order.
+func (r *Recipe) IngredientImages() []Image {
+	return ingredientImages(r.Ingredients, r.IngredientGroups, nil)
+}
+
+func ingredientImages(ingredients []Ingredient, groups []IngredientGroup, images []Image) []Image {
+	for _, in := range ingredients {
+		if in.Image != nil {
+			images = append(images, *in.Image)
+		}
+	}
+	for _, g := range groups {
+		if g.Image != nil {
+			images = append(images, *g.Image)
+		}
+		images = ingredientImages(g.Ingredients, g.IngredientGroups, images)
+	}
+	return images
+}
+
+type jsonAmount struct {
+	Factor *string     `json:"factor"`
+	Max    *string     `json:"max,omitempty"`
+	Unit   *string     `json:"unit"`
+	Approx bool        `json:"approximate,omitempty"`
+	Size   *jsonAmount `json:"size,omitempty"`
+}
+
+func toJSONAmount(a Amount) jsonAmount {
+	j := jsonAmount{Approx: a.Approx}
+	if a.Factor != nil {
+		f := amount.Decimal(a.Factor)
+		j.Factor = &f
+	}
+	if a.Max != nil {
+		m := amount.Decimal(a.Max)
+		j.Max = &m
+	}
+	if a.Unit != "" {
+		u := a.Unit
+		j.Unit = &u
+	}
+	if a.Size != nil {
+		size := toJSONAmount(*a.Size)
+		j.Size = &size
+	}
+	return j
+}
+
+// isoDuration returns d as an ISO 8601 duration, or "" if it is zero.
+func isoDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return extension.ISODuration(d)
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// MarshalJSON encodes r in the JSON format of the RecipeMD reference
+// implementation.
+func (r Recipe) MarshalJSON() ([]byte, error) {
+	yields := make([]jsonAmount, len(r.Yields))
+	for i, y := range r.Yields {
+		yields[i] = toJSONAmount(y)
+	}
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return json.Marshal(struct {
+		Title            string            `json:"title"`
+		Description      *string           `json:"description"`
+		PrepTime         string            `json:"prep_time,omitempty"`
+		CookTime         string            `json:"cook_time,omitempty"`
+		TotalTime        string            `json:"total_time,omitempty"`
+		Images           []Image           `json:"images,omitempty"`
+		Yields           []jsonAmount      `json:"yields"`
+		Tags             []string          `json:"tags"`
+		Ingredients      []Ingredient      `json:"ingredients"`
+		IngredientNotes  []Note            `json:"ingredient_notes,omitempty"`
+		IngredientGroups []IngredientGroup `json:"ingredient_groups"`
+		Instructions     *string           `json:"instructions"`
+		Appendices       []Appendix        `json:"appendices,omitempty"`
+		InstructionSteps []Step            `json:"instruction_steps,omitempty"`
+	}{
+		Title:            r.Title,
+		Description:      nullable(r.Description),
+		PrepTime:         isoDuration(r.PrepTime),
+		CookTime:         isoDuration(r.CookTime),
+		TotalTime:        isoDuration(r.TotalTime),
+		Images:           r.Images,
+		Yields:           yields,
+		Tags:             tags,
+		Ingredients:      nonNilIngredients(r.Ingredients),
+		IngredientNotes:  r.IngredientNotes,
+		IngredientGroups: nonNilGroups(r.IngredientGroups),
+		Instructions:     nullable(r.Instructions),
+		Appendices:       r.Appendices,
+		InstructionSteps: r.InstructionSteps,
+	})
+}
+
+// MarshalJSON encodes i in the JSON format of the RecipeMD reference
+// implementation.
+func (i Ingredient) MarshalJSON() ([]byte, error) {
+	var a *jsonAmount
+	if i.Amount != nil {
+		j := toJSONAmount(*i.Amount)
+		a = &j
+	}
+	return json.Marshal(struct {
+		Name        string      `json:"name"`
+		Amount      *jsonAmount `json:"amount"`
+		Link        *string     `json:"link"`
+		Pinned      bool        `json:"pinned,omitempty"`
+		Preparation string      `json:"preparation,omitempty"`
+		Optional    bool        `json:"optional,omitempty"`
+		Note        string      `json:"note,omitempty"`
+		Markdown    string      `json:"markdown,omitempty"`
+		Image       *Image      `json:"image,omitempty"`
+	}{i.Name, a, nullable(i.Link), i.Pinned, i.Preparation, i.Optional, i.Note, i.Markdown, i.Image})
+}
+
+// MarshalJSON encodes g in the JSON format of the RecipeMD reference
+// implementation.
+func (g IngredientGroup) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Title            string            `json:"title"`
+		Ingredients      []Ingredient      `json:"ingredients"`
+		Notes            []Note            `json:"notes,omitempty"`
+		IngredientGroups []IngredientGroup `json:"ingredient_groups"`
+		Image            *Image            `json:"image,omitempty"`
+	}{g.Title, nonNilIngredients(g.Ingredients), g.Notes, nonNilGroups(g.IngredientGroups), g.Image})
+}
+
+func nonNilIngredients(s []Ingredient) []Ingredient {
+	if s == nil {
+		return []Ingredient{}
+	}
+	return s
+}
+
+func nonNilGroups(s []IngredientGroup) []IngredientGroup {
+	if s == nil {
+		return []IngredientGroup{}
+	}
+	return s
+}
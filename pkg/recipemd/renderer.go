@@ -0,0 +1,112 @@
+package recipemd
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A Renderer writes a recipe in an output format.
+type Renderer interface {
+	Render(w io.Writer, r *Recipe) error
+}
+
+// RendererFunc adapts a function to a Renderer.
+type RendererFunc func(w io.Writer, r *Recipe) error
+
+// Render calls f(w, r).
+func (f RendererFunc) Render(w io.Writer, r *Recipe) error {
+	return f(w, r)
+}
+
+// outputFormat is a registered output format.
+type outputFormat struct {
+	ext    string
+	r      Renderer
+	stream bool
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]outputFormat{
+		"html":     {".html", RendererFunc(func(w io.Writer, r *Recipe) error { return WriteHTML(w, r) }), true},
+		"json":     {".json", RendererFunc(writeJSON), true},
+		"jsonld":   {".jsonld", RendererFunc(WriteJSONLD), true},
+		"markdown": {".md", RendererFunc(func(w io.Writer, r *Recipe) error { return WriteMarkdown(w, r) }), true},
+		"text":     {".txt", RendererFunc(func(w io.Writer, r *Recipe) error { return WritePlainText(w, r) }), true},
+		"toml":     {".toml", RendererFunc(WriteTOML), false},
+		"xml":      {".xml", RendererFunc(WriteXML), false},
+	}
+)
+
+// writeJSON writes r as indented recipe JSON.
+func writeJSON(w io.Writer, r *Recipe) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// RegisterFormat registers r as the renderer of the output format name,
+// whose files have the extension ext, such as ".toml". Formats are looked
+// up by commands that write recipes, such as recipemd export, and are
+// usually registered in an init function. The formats html, json, jsonld,
+// markdown, text, toml and xml are registered by this package. RegisterFormat panics
+// if name is empty or already registered, or r is nil.
+//
+// The renderings of a format registered with RegisterFormat are complete
+// documents that cannot be concatenated, like those of toml and xml, so
+// commands write them to a file per recipe. Use RegisterStreamFormat for
+// formats whose renderings form a valid stream when concatenated.
+func RegisterFormat(name, ext string, r Renderer) {
+	register(name, ext, r, false)
+}
+
+// RegisterStreamFormat registers r like RegisterFormat as the renderer of
+// a format whose renderings of several recipes, concatenated, are still
+// valid, like the JSON values of json or the markdown of markdown, so that
+// commands may write them all to standard output.
+func RegisterStreamFormat(name, ext string, r Renderer) {
+	register(name, ext, r, true)
+}
+
+func register(name, ext string, r Renderer, stream bool) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if name == "" || r == nil {
+		panic("recipemd: RegisterFormat with an empty name or a nil renderer")
+	}
+	if _, dup := formats[name]; dup {
+		panic("recipemd: RegisterFormat called twice for format " + name)
+	}
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	formats[name] = outputFormat{ext, r, stream}
+}
+
+// LookupFormat returns the renderer of the output format name and the
+// extension of its files.
+func LookupFormat(name string) (r Renderer, ext string, ok bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	f, ok := formats[name]
+	return f.r, f.ext, ok
+}
+
+// Streamable reports whether the output format name is registered and its
+// renderings of several recipes may be concatenated.
+func Streamable(name string) bool {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	return formats[name].stream
+}
+
+// Formats returns the names of the registered output formats, sorted.
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	return slices.Sorted(maps.Keys(formats))
+}
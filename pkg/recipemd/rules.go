@@ -0,0 +1,103 @@
+package recipemd
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// ScalingRule makes a class of ingredients scale less than linearly, the
+// way cooks adjust seasoning and leavening when changing batch sizes. The
+// change in amount is damped by Rate: an ingredient matched by a rule with
+// rate 0.8 grows by 80% when the recipe doubles (factor 1.8) and shrinks to
+// 60% when it is halved. MaxFactor, if set, caps the resulting factor.
+type ScalingRule struct {
+	Class     string
+	Keywords  []string // whole words or phrases matched against ingredient names
+	Rate      *big.Rat
+	MaxFactor *big.Rat
+}
+
+// Matches reports whether the ingredient name contains one of the rule's
+// keywords as whole words, ignoring case.
+func (r ScalingRule) Matches(name string) bool {
+	name = strings.ToLower(name)
+	for _, k := range r.Keywords {
+		pattern := `\b` + regexp.QuoteMeta(strings.ToLower(k)) + `\b`
+		if regexp.MustCompile(pattern).MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Factor returns the factor an ingredient matched by r is scaled by when
+// the recipe is scaled by factor.
+func (r ScalingRule) Factor(factor *big.Rat) *big.Rat {
+	f := new(big.Rat).Set(factor)
+	if r.Rate != nil {
+		// 1 + (factor - 1) * rate
+		one := big.NewRat(1, 1)
+		f.Sub(f, one)
+		f.Mul(f, r.Rate)
+		f.Add(f, one)
+	}
+	if f.Sign() < 0 {
+		f.SetInt64(0)
+	}
+	if r.MaxFactor != nil && f.Cmp(r.MaxFactor) > 0 {
+		f.Set(r.MaxFactor)
+	}
+	return f
+}
+
+// DefaultScalingRules damp spices to 0.8, leavening agents to 0.9 and cap
+// salt at one and a half times the original amount.
+var DefaultScalingRules = []ScalingRule{
+	{
+		Class: "salt",
+		Keywords: []string{
+			"salt",
+		},
+		MaxFactor: big.NewRat(3, 2),
+	},
+	{
+		Class: "leavening",
+		Keywords: []string{
+			"yeast", "baking powder", "baking soda", "bicarbonate of soda",
+			"sodium bicarbonate", "cream of tartar", "sourdough starter",
+		},
+		Rate: big.NewRat(9, 10),
+	},
+	{
+		Class: "spice",
+		Keywords: []string{
+			"black pepper", "white pepper", "ground pepper", "peppercorns",
+			"cayenne", "chili powder", "chili flakes", "chilli flakes",
+			"pepper flakes", "paprika", "cumin", "cinnamon", "nutmeg",
+			"cloves", "allspice", "cardamom", "turmeric", "curry powder",
+			"garam masala", "coriander seeds", "ground coriander",
+			"ground ginger", "mace", "star anise", "fennel seeds",
+		},
+		Rate: big.NewRat(4, 5),
+	},
+}
+
+// WithRules enables nonlinear scaling: ingredients matched by one of the
+// rules scale by the factor of the first matching rule instead of the
+// recipe's factor.
+func WithRules(rules ...ScalingRule) ScaleOption {
+	return func(c *scaleConfig) {
+		c.rules = append(c.rules, rules...)
+	}
+}
+
+// factor returns the factor the ingredient i is scaled by.
+func (c *scaleConfig) factor(i Ingredient, factor *big.Rat) *big.Rat {
+	for _, r := range c.rules {
+		if r.Matches(i.Name) {
+			return r.Factor(factor)
+		}
+	}
+	return factor
+}
@@ -0,0 +1,186 @@
+package recipemd
+
+import (
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+// ScaleOption configures Scale.
+type ScaleOption func(*scaleConfig)
+
+type scaleConfig struct {
+	pinned       []string
+	rules        []ScalingRule
+	units        *units.Registry
+	instructions bool
+}
+
+// WithUnits makes Scale inflect units with the spellings of reg instead
+// of the built-in ones, for example to add regional units.
+func WithUnits(reg *units.Registry) ScaleOption {
+	return func(c *scaleConfig) {
+		c.units = reg
+	}
+}
+
+// scale returns a multiplied by f, with its unit in the singular or plural
+// form the new amount takes.
+func (c *scaleConfig) scale(a Amount, f *big.Rat) Amount {
+	s := a.Scale(f)
+	if s.Factor == nil {
+		return s
+	}
+	inflect := units.Inflect
+	if c.units != nil {
+		inflect = c.units.Inflect
+	}
+	if u, conv, ok := amount.Conversion(s.Unit); ok {
+		s.Unit = inflect(u, s.Upper()) + " (" + conv.String() + ")"
+	} else {
+		s.Unit = inflect(s.Unit, s.Upper())
+	}
+	return s
+}
+
+// Pin keeps the amounts of the ingredients with the given names unchanged
+// during scaling and marks them as pinned. Names are compared ignoring
+// case.
+func Pin(names ...string) ScaleOption {
+	return func(c *scaleConfig) {
+		c.pinned = append(c.pinned, names...)
+	}
+}
+
+func (c *scaleConfig) isPinned(i Ingredient) bool {
+	if i.Pinned {
+		return true
+	}
+	for _, name := range c.pinned {
+		if strings.EqualFold(name, i.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scale returns a copy of r with the amounts of its yields and ingredients
+// multiplied by factor. Pinned ingredients keep their amounts and
+// ingredients matched by a rule given with WithRules scale by the factor
+// the rule derives. Known units take the form that fits the new amount,
+// so "1 cup" doubles to "2 cups". With ScaleInstructions, quantities of
+// ingredients in the instructions are scaled as well.
+func (r *Recipe) Scale(factor *big.Rat, opts ...ScaleOption) *Recipe {
+	var c scaleConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	s := r.Clone()
+	for i, y := range s.Yields {
+		s.Yields[i] = c.scale(y, factor)
+	}
+	scaleIngredients(s.Ingredients, factor, &c)
+	scaleGroups(s.IngredientGroups, factor, &c)
+	if c.instructions {
+		s.Instructions = scaleText(r.Instructions, r.AllIngredients(), factor, &c)
+	}
+	if s.InstructionSteps != nil {
+		s.InstructionSteps = s.Steps()
+	}
+	return s
+}
+
+func scaleIngredients(ingredients []Ingredient, factor *big.Rat, c *scaleConfig) {
+	for i := range ingredients {
+		in := &ingredients[i]
+		if c.isPinned(*in) {
+			in.Pinned = true
+			continue
+		}
+		if in.Amount != nil {
+			a := c.scale(*in.Amount, c.factor(*in, factor))
+			in.Amount = &a
+		}
+	}
+}
+
+func scaleGroups(groups []IngredientGroup, factor *big.Rat, c *scaleConfig) {
+	for i := range groups {
+		scaleIngredients(groups[i].Ingredients, factor, c)
+		scaleGroups(groups[i].IngredientGroups, factor, c)
+	}
+}
+
+// Clone returns a deep copy of r.
+func (r *Recipe) Clone() *Recipe {
+	c := *r
+	c.Tags = append([]string(nil), r.Tags...)
+	c.Yields = nil
+	for _, y := range r.Yields {
+		c.Yields = append(c.Yields, cloneAmount(y))
+	}
+	c.Ingredients = cloneIngredients(r.Ingredients)
+	c.IngredientNotes = slices.Clone(r.IngredientNotes)
+	c.Images = slices.Clone(r.Images)
+	c.Appendices = slices.Clone(r.Appendices)
+	c.InstructionSteps = slices.Clone(r.InstructionSteps)
+	c.Sections = slices.Clone(r.Sections)
+	c.IngredientGroups = cloneGroups(r.IngredientGroups)
+	return &c
+}
+
+func cloneAmount(a Amount) Amount {
+	if a.Factor != nil {
+		a.Factor = new(big.Rat).Set(a.Factor)
+	}
+	if a.Max != nil {
+		a.Max = new(big.Rat).Set(a.Max)
+	}
+	if a.Size != nil {
+		size := cloneAmount(*a.Size)
+		a.Size = &size
+	}
+	return a
+}
+
+func cloneIngredients(ingredients []Ingredient) []Ingredient {
+	if ingredients == nil {
+		return nil
+	}
+	c := make([]Ingredient, len(ingredients))
+	for i, in := range ingredients {
+		if in.Amount != nil {
+			a := cloneAmount(*in.Amount)
+			in.Amount = &a
+		}
+		if in.Image != nil {
+			img := *in.Image
+			in.Image = &img
+		}
+		c[i] = in
+	}
+	return c
+}
+
+func cloneGroups(groups []IngredientGroup) []IngredientGroup {
+	if groups == nil {
+		return nil
+	}
+	c := make([]IngredientGroup, len(groups))
+	for i, g := range groups {
+		c[i] = IngredientGroup{
+			Title:            g.Title,
+			Ingredients:      cloneIngredients(g.Ingredients),
+			Notes:            slices.Clone(g.Notes),
+			IngredientGroups: cloneGroups(g.IngredientGroups),
+		}
+		if g.Image != nil {
+			img := *g.Image
+			c[i].Image = &img
+		}
+	}
+	return c
+}
@@ -0,0 +1,61 @@
+package recipemd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		amount string
+		factor *big.Rat
+		want   string
+	}{
+		{"200 g", big.NewRat(2, 1), "400 g"},
+		{"1 cup", big.NewRat(2, 1), "2 cups"},
+		{"2 cups", big.NewRat(1, 2), "1 cup"},
+		{"1 clove", big.NewRat(3, 1), "3 cloves"},
+		{"1 cup (240 ml)", big.NewRat(2, 1), "2 cups (480 ml)"},
+		{"2 cups (480 ml)", big.NewRat(1, 4), "0.5 cup (120 ml)"},
+		{"1 can (400 g)", big.NewRat(2, 1), "2 cans (400 g)"},
+		{"2 x 400 g", big.NewRat(2, 1), "4 x 400 g"},
+	}
+	for _, tt := range tests {
+		r, err := Parse([]byte("# T\n\n---\n\n- *" + tt.amount + "* milk\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Scale(tt.factor).Ingredients[0].Amount.String(); got != tt.want {
+			t.Errorf("%s scaled by %s = %q, want %q", tt.amount, tt.factor.RatString(), got, tt.want)
+		}
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   string
+	}{
+		{"1 cup", "1 cup (240 ml)"},
+		{"200 g", "200 g (7 oz)"},
+		{"1 cup (240 ml)", "1 cup (240 ml)"},
+		{"1 can (400 g)", "1 can (400 g)"},
+		{"2 x 400 g", "2 x 400 g"},
+		{"1 clove", "1 clove"},
+	}
+	for _, tt := range tests {
+		r, err := Parse([]byte("# T\n\n---\n\n- *" + tt.amount + "* milk\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := r.Annotate(units.Classes...)
+		if got := a.Ingredients[0].Amount.String(); got != tt.want {
+			t.Errorf("%s annotated = %q, want %q", tt.amount, got, tt.want)
+		}
+		if got := a.Annotate().Ingredients[0].Amount.String(); got != tt.want {
+			t.Errorf("%s annotated twice = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package recipemd
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+)
+
+// ScaleInstructions makes Scale also rewrite quantities in the
+// instructions. It is a heuristic and therefore opt-in: a number is only
+// scaled if it is directly followed by an ingredient name, optionally
+// with a unit and "of" or "of the" in between, and its unit is the unit of
+// that ingredient's amount. "Add 100 g of the flour" is rewritten when
+// the flour is measured in grams; "bake for 20 minutes" and "2 cm thick"
+// never are. Such quantities scale like the ingredient, so pinned
+// ingredients and scaling rules apply.
+func ScaleInstructions() ScaleOption {
+	return func(c *scaleConfig) {
+		c.instructions = true
+	}
+}
+
+// quantityRe matches a number as it is written in prose, followed by an
+// optional unit word.
+var quantityRe = regexp.MustCompile(`(\d+\s+\d+/\d+|\d+/\d+|\d+[.,]\d+|\d+[½⅓⅔¼¾⅛⅜⅝⅞]?|[½⅓⅔¼¾⅛⅜⅝⅞])(\s*\pL+\.?)?`)
+
+// connectorRe matches the words allowed between a quantity and the name
+// of its ingredient.
+var connectorRe = regexp.MustCompile(`^\s+(?:of\s+(?:the\s+)?)?`)
+
+// scaleText returns text with the quantities of the ingredients scaled by
+// factor as described for ScaleInstructions.
+func scaleText(text string, ingredients []Ingredient, factor *big.Rat, c *scaleConfig) string {
+	phrases := ingredientPhrases(ingredients)
+	var b strings.Builder
+	last := 0
+	for _, m := range quantityRe.FindAllStringSubmatchIndex(text, -1) {
+		start := m[0]
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '/') {
+			continue
+		}
+		// the unit word, if any, may instead be the start of the name
+		ends := []int{m[1]}
+		if m[4] >= 0 {
+			ends = append(ends, m[3])
+		}
+		for _, end := range ends {
+			in, ok := mentionAt(text[end:], ingredients, phrases)
+			if !ok || in.Amount == nil || in.Amount.Factor == nil || c.isPinned(in) {
+				continue
+			}
+			a := amount.Parse(text[start:end])
+			if a.Factor == nil || !sameUnit(a.Unit, in.Amount.Unit) {
+				continue
+			}
+			b.WriteString(text[last:start])
+			b.WriteString(c.scale(a, c.factor(in, factor)).String())
+			last = end
+			break
+		}
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// mentionAt returns the ingredient whose name starts s, after the words
+// allowed between a quantity and a name.
+func mentionAt(s string, ingredients []Ingredient, phrases []phrase) (Ingredient, bool) {
+	loc := connectorRe.FindStringIndex(s)
+	if loc == nil {
+		return Ingredient{}, false
+	}
+	words := stems(firstWords(s[loc[1]:], 8))
+	for _, p := range phrases {
+		if len(p.words) <= len(words) && equalWords(p.words, words[:len(p.words)]) {
+			return ingredients[p.ingredient], true
+		}
+	}
+	return Ingredient{}, false
+}
+
+// firstWords returns the start of s up to the end of its n-th word or the
+// end of the sentence, whichever comes first.
+func firstWords(s string, n int) string {
+	if i := strings.IndexAny(s, ".;:!?\n"); i >= 0 {
+		s = s[:i]
+	}
+	fields := strings.Fields(s)
+	return strings.Join(fields[:min(n, len(fields))], " ")
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
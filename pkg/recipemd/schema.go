@@ -0,0 +1,173 @@
+package recipemd
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaVersion is the version of the JSON format of recipes. It changes
+// when a change of the format could break consumers, such as a removed or
+// renamed field; new optional fields keep the version.
+const SchemaVersion = 1
+
+//go:embed recipe.schema.json
+var schemaDocument []byte
+
+// Schema returns the JSON Schema of the JSON encoding of recipes, version
+// SchemaVersion.
+func Schema() []byte {
+	return bytes.Clone(schemaDocument)
+}
+
+var schema = func() map[string]any {
+	var s map[string]any
+	if err := json.Unmarshal(schemaDocument, &s); err != nil {
+		panic(err)
+	}
+	return s
+}()
+
+// ValidateJSON checks that data is a recipe in the JSON format described
+// by Schema. The error names the first offending value by its path, e.g.
+// "ingredients[2].amount.factor".
+func ValidateJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("recipemd: invalid JSON: %w", err)
+	}
+	if err := validateValue(schema, v, ""); err != nil {
+		return fmt.Errorf("recipemd: JSON does not match schema version %d: %w", SchemaVersion, err)
+	}
+	return nil
+}
+
+// validateValue validates v against the subset of JSON Schema that
+// recipe.schema.json uses.
+func validateValue(s map[string]any, v any, path string) error {
+	where := path
+	if where == "" {
+		where = "recipe"
+	}
+	if ref, ok := s["$ref"].(string); ok {
+		name, _ := strings.CutPrefix(ref, "#/$defs/")
+		def, ok := schema["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("unknown schema reference %q", ref)
+		}
+		if err := validateValue(def, v, path); err != nil {
+			return err
+		}
+	}
+	if alternatives, ok := s["oneOf"].([]any); ok {
+		matches := 0
+		var first error
+		for _, a := range alternatives {
+			if err := validateValue(a.(map[string]any), v, path); err == nil {
+				matches++
+			} else if first == nil {
+				first = err
+			}
+		}
+		if matches != 1 {
+			return first
+		}
+	}
+	if t, ok := s["type"]; ok {
+		var types []string
+		switch t := t.(type) {
+		case string:
+			types = []string{t}
+		case []any:
+			for _, t := range t {
+				if t, ok := t.(string); ok {
+					types = append(types, t)
+				}
+			}
+		}
+		if !slices.Contains(types, jsonType(v)) && !(jsonType(v) == "integer" && slices.Contains(types, "number")) {
+			return fmt.Errorf("%s: want %s, got %s", where, strings.Join(types, " or "), jsonType(v))
+		}
+	}
+	switch v := v.(type) {
+	case string:
+		if min, ok := s["minLength"].(float64); ok && utf8.RuneCountInString(v) < int(min) {
+			return fmt.Errorf("%s: must not be empty", where)
+		}
+		if p, ok := s["pattern"].(string); ok && !regexp.MustCompile(p).MatchString(v) {
+			return fmt.Errorf("%s: %q does not match %s", where, v, p)
+		}
+	case json.Number:
+		if min, ok := s["minimum"].(float64); ok {
+			if f, err := v.Float64(); err != nil || f < min {
+				return fmt.Errorf("%s: must be at least %v", where, min)
+			}
+		}
+	case []any:
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateValue(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		props, _ := s["properties"].(map[string]any)
+		if required, ok := s["required"].([]any); ok {
+			for _, r := range required {
+				if _, ok := v[r.(string)]; !ok {
+					return fmt.Errorf("%s: missing %q", where, r)
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			p, ok := props[k].(map[string]any)
+			if !ok {
+				if s["additionalProperties"] == false {
+					return fmt.Errorf("%s: unknown field %q", where, k)
+				}
+				continue
+			}
+			sub := k
+			if path != "" {
+				sub = path + "." + k
+			}
+			if err := validateValue(p, v[k], sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonType returns the JSON Schema type of a value decoded with UseNumber.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
@@ -0,0 +1,62 @@
+package recipemd
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxFragment bounds the markdown a fragment may expand to, so that a
+// short link cannot make DecodeFragment allocate without limit.
+const maxFragment = 1 << 20
+
+// EncodeFragment packs r into a compact string for the fragment or a query
+// parameter of a URL, so that a link carries the whole recipe: its
+// markdown, compressed with DEFLATE and encoded as unpadded URL-safe
+// base64. DecodeFragment unpacks it.
+func EncodeFragment(r *Recipe) (string, error) {
+	var md bytes.Buffer
+	if err := WriteMarkdown(&md, r); err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	w, err := flate.NewWriter(&b, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	w.Write(md.Bytes())
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b.Bytes()), nil
+}
+
+// DecodeFragment parses a recipe packed by EncodeFragment. A leading "#",
+// as in the fragment of a URL, is ignored.
+func DecodeFragment(s string) (*Recipe, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, "#"))
+	if err != nil {
+		return nil, fmt.Errorf("recipemd: invalid fragment: %w", err)
+	}
+	md, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(data)), maxFragment+1))
+	if err != nil {
+		return nil, fmt.Errorf("recipemd: invalid fragment: %w", err)
+	}
+	if len(md) > maxFragment {
+		return nil, fmt.Errorf("recipemd: invalid fragment: recipe larger than %d bytes", maxFragment)
+	}
+	return Parse(md)
+}
+
+// DataURI returns the markdown of r as a data URI, which browsers open as
+// a file without a server.
+func DataURI(r *Recipe) (string, error) {
+	var md bytes.Buffer
+	if err := WriteMarkdown(&md, r); err != nil {
+		return "", err
+	}
+	return "data:text/markdown;charset=utf-8;base64," + base64.StdEncoding.EncodeToString(md.Bytes()), nil
+}
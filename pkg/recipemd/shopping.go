@@ -0,0 +1,195 @@
+package recipemd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+// ShoppingItem is an entry of a ShoppingList: an ingredient with the
+// amounts needed of it. Amounts with the same unit are summed, so Amounts
+// holds one entry per unit. It is empty if none of the merged ingredients
+// had an amount. Category is set by SortByCategory.
+type ShoppingItem struct {
+	Name     string
+	Amounts  []Amount
+	Category string
+
+	seq int // position in the order of appearance
+}
+
+// ShoppingList merges the ingredients of several recipes. Ingredients are
+// de-duplicated by name, ignoring case, and listed in the order they were
+// first added until the list is sorted. The zero value is an empty list
+// ready to use.
+type ShoppingList struct {
+	Items []ShoppingItem
+	index map[string]int
+}
+
+// Add adds all ingredients of r, including those in groups.
+func (l *ShoppingList) Add(r *Recipe) {
+	for _, i := range r.AllIngredients() {
+		l.AddIngredient(i)
+	}
+}
+
+// AddIngredient adds a single ingredient to the list.
+func (l *ShoppingList) AddIngredient(i Ingredient) {
+	key := ingredientKey(i.Name)
+	n, ok := l.index[key]
+	if !ok {
+		if l.index == nil {
+			l.index = make(map[string]int)
+		}
+		n = len(l.Items)
+		l.index[key] = n
+		l.Items = append(l.Items, ShoppingItem{Name: i.Name, seq: n})
+	}
+	if i.Amount != nil {
+		l.Items[n].add(*i.Amount)
+	}
+}
+
+// SortByAppearance restores the order in which the ingredients were first
+// added.
+func (l *ShoppingList) SortByAppearance() {
+	l.sort(func(a, b ShoppingItem) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+}
+
+// SortByName sorts the items alphabetically, ignoring case.
+func (l *ShoppingList) SortByName() {
+	l.sort(compareNames)
+}
+
+// SortByCategory groups the items by category in the order of categories,
+// for example a StoreLayout, with the items of no category last under
+// "other". Items within a category are sorted by name. If categories is
+// nil, DefaultCategories are used.
+func (l *ShoppingList) SortByCategory(categories []Category) {
+	if categories == nil {
+		categories = DefaultCategories
+	}
+	rank := make(map[string]int)
+	for i := range l.Items {
+		it := &l.Items[i]
+		n := categoryOf(it.Name, categories)
+		if n < 0 {
+			it.Category = "other"
+			n = len(categories)
+		} else {
+			it.Category = categories[n].Name
+		}
+		rank[it.Name] = n
+	}
+	l.sort(func(a, b ShoppingItem) int {
+		if n := cmp.Compare(rank[a.Name], rank[b.Name]); n != 0 {
+			return n
+		}
+		return compareNames(a, b)
+	})
+}
+
+func compareNames(a, b ShoppingItem) int {
+	if n := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); n != 0 {
+		return n
+	}
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// sort sorts the items with compare and updates the index.
+func (l *ShoppingList) sort(compare func(a, b ShoppingItem) int) {
+	slices.SortStableFunc(l.Items, compare)
+	for i, it := range l.Items {
+		l.index[ingredientKey(it.Name)] = i
+	}
+}
+
+// add sums a into the amount of it with the same unit.
+func (it *ShoppingItem) add(a Amount) {
+	for k, b := range it.Amounts {
+		if !sameUnit(a.Unit, b.Unit) || !sameSize(a, b) {
+			continue
+		}
+		switch {
+		case a.Factor == nil && b.Factor == nil:
+			// "to taste" twice is still to taste
+			return
+		case a.Factor != nil && b.Factor != nil:
+			sum := &it.Amounts[k]
+			sum.Factor = new(big.Rat).Add(a.Factor, b.Factor)
+			sum.Approx = a.Approx || b.Approx
+			if a.Max != nil || b.Max != nil {
+				sum.Max = new(big.Rat).Add(a.Upper(), b.Upper())
+			}
+			sum.Unit = units.Inflect(sum.Unit, sum.Upper())
+			return
+		}
+	}
+	it.Amounts = append(it.Amounts, cloneAmount(a))
+}
+
+// sameSize reports whether a and b are packages of the same size or both
+// no package amounts.
+func sameSize(a, b Amount) bool {
+	if a.Size == nil || b.Size == nil {
+		return a.Size == b.Size
+	}
+	return strings.EqualFold(a.Size.String(), b.Size.String())
+}
+
+// WriteMarkdown writes l to w as a markdown task list, one item per line.
+// A list sorted by category has a heading before each category.
+func (l *ShoppingList) WriteMarkdown(w io.Writer) error {
+	var b bytes.Buffer
+	for i, it := range l.Items {
+		if it.Category != "" && (i == 0 || it.Category != l.Items[i-1].Category) {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString("## " + escapeMarkdown(SentenceCase.apply(it.Category)) + "\n\n")
+		}
+		b.WriteString("- [ ] ")
+		if len(it.Amounts) > 0 {
+			amounts := make([]string, len(it.Amounts))
+			for i, a := range it.Amounts {
+				amounts[i] = escapeMarkdown(a.String())
+			}
+			b.WriteString("*" + strings.Join(amounts, ", ") + "* ")
+		}
+		b.WriteString(escapeMarkdown(it.Name))
+		b.WriteString("\n")
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// MarshalJSON encodes l as an array of its items.
+func (l ShoppingList) MarshalJSON() ([]byte, error) {
+	items := l.Items
+	if items == nil {
+		items = []ShoppingItem{}
+	}
+	return json.Marshal(items)
+}
+
+// MarshalJSON encodes it with its amounts in the format used for recipes.
+func (it ShoppingItem) MarshalJSON() ([]byte, error) {
+	amounts := make([]jsonAmount, len(it.Amounts))
+	for i, a := range it.Amounts {
+		amounts[i] = toJSONAmount(a)
+	}
+	return json.Marshal(struct {
+		Name     string       `json:"name"`
+		Amounts  []jsonAmount `json:"amounts"`
+		Category string       `json:"category,omitempty"`
+	}{it.Name, amounts, it.Category})
+}
@@ -0,0 +1,43 @@
+package recipemd
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Value implements driver.Valuer, so recipes can be stored in database
+// columns as their JSON. The JSON is a string, which suits TEXT columns
+// as well as the json and jsonb columns of PostgreSQL and the JSON
+// functions of SQLite.
+func (r Recipe) Value() (driver.Value, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner for columns holding the JSON written by
+// Value, decoding it as UnmarshalJSON does. NULL is an error; scan
+// nullable columns into a sql.Null[Recipe].
+func (r *Recipe) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	case nil:
+		return errors.New("recipemd: cannot scan NULL into a Recipe")
+	default:
+		return fmt.Errorf("recipemd: cannot scan %T into a Recipe", src)
+	}
+	var scanned Recipe
+	if err := json.Unmarshal(data, &scanned); err != nil {
+		return err
+	}
+	*r = scanned
+	return nil
+}
@@ -0,0 +1,85 @@
+package recipemd
+
+import (
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// Step is a step of the instructions of a recipe. Number is the number of
+// the step as written in an ordered list, or its position if the steps
+// are paragraphs. Text is the markdown of the step.
+//
+// Ingredients are the ingredients of the recipe that the step mentions by
+// name, ignoring case and plural endings, in document order. A mention of
+// the last word of a name counts if no other ingredient ends in the same
+// word, so "all-purpose flour" is used by "sift the flour". Cook mode
+// views can show their amounts next to the step, and offer its Timers.
+type Step struct {
+	Number      int          `json:"number"`
+	Text        string       `json:"text"`
+	Ingredients []Ingredient `json:"ingredients,omitempty"`
+	Timers      []Timer      `json:"timers,omitempty"`
+}
+
+// WithInstructionSteps sets Recipe.InstructionSteps to the steps of the
+// instructions, so that they are part of the JSON of the recipe.
+func WithInstructionSteps() ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.steps = true
+	}
+}
+
+// Steps splits the instructions into steps. If the instructions contain
+// ordered lists, the steps are their items, keeping the numbering of the
+// lists; the paragraphs around the lists are not steps. Otherwise every
+// paragraph is a step. Headings, code blocks, tables, footnotes and other
+// blocks are never steps.
+func (r *Recipe) Steps() []Step {
+	source := []byte(r.Instructions)
+	doc := gfm.Parser().Parse(text.NewReader(source))
+	ingredients := r.AllIngredients()
+	phrases := ingredientPhrases(ingredients)
+	var steps []Step
+	for _, n := range extension.Steps(doc) {
+		s := Step{Number: extension.StepNumber(n)}
+		for _, i := range mentions(nodeWords(n, source), phrases) {
+			s.Ingredients = append(s.Ingredients, ingredients[i])
+		}
+		if item, ok := n.(*gast.ListItem); ok {
+			s.Text = itemMarkdown(item, source)
+		} else {
+			s.Text = paragraph(n, source)
+		}
+		s.Timers = Timers(s.Text)
+		steps = append(steps, s)
+	}
+	return steps
+}
+
+// itemMarkdown returns the markdown of the content of a list item, without
+// the list marker and the indentation of its continuation lines.
+func itemMarkdown(item *gast.ListItem, source []byte) string {
+	start, end := -1, -1
+	_ = gast.Walk(item, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && n.Type() == gast.TypeBlock && n.Lines().Len() > 0 {
+			if start < 0 {
+				start = n.Lines().At(0).Start
+			}
+			end = max(end, n.Lines().At(n.Lines().Len()-1).Stop)
+		}
+		return gast.WalkContinue, nil
+	})
+	if start < 0 {
+		return ""
+	}
+	lines := strings.Split(string(source[start:end]), "\n")
+	indent := strings.Repeat(" ", item.Offset)
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.TrimPrefix(lines[i], indent)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,66 @@
+package recipemd
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	gext "github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// plain parses descriptions, which are CommonMark without RecipeMD
+// structure.
+var plain = goldmark.New()
+
+// gfm parses descriptions and instructions like plain, but reads GitHub
+// Flavored Markdown tables and footnotes as such rather than as
+// paragraphs.
+var gfm = goldmark.New(goldmark.WithExtensions(gext.Table, gext.Footnote))
+
+// Summary returns the description of r as plain text of at most max
+// characters, for recipe cards, meta descriptions and feeds. It consists
+// of as many whole sentences as fit; if not even the first one fits, it is
+// cut at a word boundary and ends with "…". Markup is removed and white
+// space collapsed; tables and footnotes are left out.
+func (r *Recipe) Summary(max int) string {
+	source := []byte(r.Description)
+	doc := gfm.Parser().Parse(text.NewReader(source))
+	var blocks []string
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c.Kind() {
+		case east.KindTable, east.KindFootnoteList:
+			continue
+		}
+		if t := extension.PlainText(c, source); t != "" {
+			blocks = append(blocks, t)
+		}
+	}
+	s := strings.Join(strings.Fields(strings.Join(blocks, " ")), " ")
+	rs := []rune(s)
+	if len(rs) <= max {
+		return s
+	}
+	end := 0
+	for i, c := range rs[:max+1] {
+		if i < max && strings.ContainsRune(".!?。！？", c) && (i+1 == len(rs) || unicode.IsSpace(rs[i+1]) || c >= 0x3000) {
+			end = i + 1
+		}
+	}
+	if end > 0 {
+		return string(rs[:end])
+	}
+	cut := max - 1
+	for i := cut; i > 0; i-- {
+		if unicode.IsSpace(rs[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(rs[:cut]), func(c rune) bool {
+		return unicode.IsSpace(c) || unicode.IsPunct(c)
+	}) + "…"
+}
@@ -0,0 +1,54 @@
+package recipemd
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// Timer is a time given in a step of the instructions, like "bake for
+// 35-40 minutes" or "rest 1 hour 30 min", that a cook mode view can
+// offer to start. Text is the time as written, Duration its length and
+// Max the upper end of a range, zero for single times.
+type Timer struct {
+	Text     string
+	Duration time.Duration
+	Max      time.Duration
+}
+
+// timerRe matches times in instructions: a number or range of numbers
+// followed by a unit, and further such parts, as in "1 hour 30 min".
+// Single-letter units other than "h" are too ambiguous in prose.
+var timerRe = regexp.MustCompile(`(?i)\b\d+(?:[.,]\d+)?(?:\s*(?:-|–|to)\s*\d+(?:[.,]\d+)?)?\s*(?:days?|hours?|hrs?|h|minutes?|mins?|seconds?|secs?)\b(?:(?:\s+|\s*,\s*|\s+and\s+)\d+(?:[.,]\d+)?\s*(?:hours?|hrs?|h|minutes?|mins?|seconds?|secs?)\b)*`)
+
+// rangeRe splits a time matched by timerRe at its range separator.
+var rangeRe = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)\s*(?:-|–|to)\s*(\d+(?:[.,]\d+)?)(\s*.*)$`)
+
+// Timers returns the times given in the markdown text s in order.
+func Timers(s string) []Timer {
+	var timers []Timer
+	for _, m := range timerRe.FindAllString(s, -1) {
+		t := Timer{Text: m}
+		if r := rangeRe.FindStringSubmatch(m); r != nil {
+			t.Duration = extension.ParseDuration(r[1] + r[3])
+			t.Max = extension.ParseDuration(r[2] + r[3])
+		} else {
+			t.Duration = extension.ParseDuration(m)
+		}
+		if t.Duration > 0 {
+			timers = append(timers, t)
+		}
+	}
+	return timers
+}
+
+// MarshalJSON encodes t with its durations in ISO 8601.
+func (t Timer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Text     string `json:"text"`
+		Duration string `json:"duration"`
+		Max      string `json:"max,omitempty"`
+	}{t.Text, extension.ISODuration(t.Duration), isoDuration(t.Max)})
+}
@@ -0,0 +1,59 @@
+package recipemd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xcapaldi/recipemd-go/pkg/diag"
+)
+
+// WithTitleFallback makes Parse and ParsePartial give a document without
+// a title the title given, as if it started with it as a first-level
+// heading; see InsertTitle. ParsePartial lists the insertion in
+// Completeness.Fixes. Documents that have a title are not affected. An
+// empty title disables the fallback.
+func WithTitleFallback(title string) ParseOption {
+	return func(cfg *parseConfig) {
+		cfg.titleFallback = title
+	}
+}
+
+// TitleFromFilename returns a title for the recipe in the file at path:
+// the base name without its extension, with hyphens, underscores and
+// dots read as spaces and the first letter upper-cased, so
+// "chocolate-chip_cookies.md" becomes "Chocolate chip cookies".
+func TitleFromFilename(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+	}), " ")
+	if name == "" {
+		return ""
+	}
+	r, n := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[n:]
+}
+
+// InsertTitle returns source with title inserted as a first-level
+// heading at the start if source has no title, and whether it did. The
+// rest of the document is kept as it is, so a description that used to
+// come first now follows the title.
+func InsertTitle(source []byte, title string) ([]byte, bool) {
+	if title == "" || !missingTitle(source) {
+		return source, false
+	}
+	heading := "# " + escapeMarkdown(title) + "\n"
+	if len(strings.TrimSpace(string(source))) > 0 {
+		heading += "\n"
+	}
+	return append([]byte(heading), source...), true
+}
+
+// missingTitle reports whether source does not start with a title.
+func missingTitle(source []byte) bool {
+	_, err := Parse(source)
+	return errors.Is(err, diag.ErrMissingTitle)
+}
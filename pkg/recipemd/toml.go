@@ -0,0 +1,166 @@
+package recipemd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// WriteTOML writes r to w as a TOML document with the structure of the
+// recipe JSON, for tools that read TOML but not JSON. Objects become
+// tables and arrays of objects arrays of tables, such as
+// [[ingredient_groups.ingredients]]. TOML has no null, so null members
+// are left out, and the values of a table come before its subtables.
+func WriteTOML(w io.Writer, r *Recipe) error {
+	tree, err := recipeTree(r)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	writeTOMLTable(&b, nil, tree)
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// writeTOMLTable writes the members of the table at path, its values and
+// then its subtables.
+func writeTOMLTable(b *bytes.Buffer, path []string, table []jsonMember) {
+	for _, m := range table {
+		if m.value == nil || isTOMLTable(m.value) {
+			continue
+		}
+		b.WriteString(tomlKey(m.key))
+		b.WriteString(" = ")
+		writeTOMLValue(b, m.value)
+		b.WriteByte('\n')
+	}
+	for _, m := range table {
+		if !isTOMLTable(m.value) {
+			continue
+		}
+		p := append(slices.Clone(path), m.key)
+		header := make([]string, len(p))
+		for i, k := range p {
+			header[i] = tomlKey(k)
+		}
+		switch v := m.value.(type) {
+		case []jsonMember:
+			fmt.Fprintf(b, "\n[%s]\n", strings.Join(header, "."))
+			writeTOMLTable(b, p, v)
+		case []any:
+			for _, e := range v {
+				fmt.Fprintf(b, "\n[[%s]]\n", strings.Join(header, "."))
+				writeTOMLTable(b, p, e.([]jsonMember))
+			}
+		}
+	}
+}
+
+// isTOMLTable reports whether v is written as a table or an array of
+// tables rather than a value: an object, or an array of objects.
+func isTOMLTable(v any) bool {
+	switch v := v.(type) {
+	case []jsonMember:
+		return true
+	case []any:
+		if len(v) == 0 {
+			return false
+		}
+		for _, e := range v {
+			if _, ok := e.([]jsonMember); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// writeTOMLValue writes v as an inline TOML value.
+func writeTOMLValue(b *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case string:
+		writeTOMLString(b, v)
+	case json.Number:
+		b.WriteString(v.String())
+	case bool:
+		fmt.Fprint(b, v)
+	case []any:
+		b.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeTOMLValue(b, e)
+		}
+		b.WriteByte(']')
+	case []jsonMember:
+		b.WriteByte('{')
+		first := true
+		for _, m := range v {
+			if m.value == nil {
+				continue
+			}
+			if !first {
+				b.WriteByte(',')
+			}
+			first = false
+			b.WriteString(" " + tomlKey(m.key) + " = ")
+			writeTOMLValue(b, m.value)
+		}
+		b.WriteString(" }")
+	}
+}
+
+// writeTOMLString writes s as a basic string, or as a multi-line basic
+// string if it has several lines.
+func writeTOMLString(b *bytes.Buffer, s string) {
+	multiline := strings.Contains(s, "\n")
+	if multiline {
+		b.WriteString(`"""` + "\n")
+	} else {
+		b.WriteByte('"')
+	}
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(c)
+		case c == '\n' && multiline, c == '\t':
+			b.WriteRune(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(b, `\u%04X`, c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	if multiline {
+		b.WriteString(`"""`)
+	} else {
+		b.WriteByte('"')
+	}
+}
+
+// tomlKey returns k as a bare key if it can be one and quoted otherwise.
+func tomlKey(k string) string {
+	bare := k != ""
+	for _, c := range k {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			bare = false
+			break
+		}
+	}
+	if bare {
+		return k
+	}
+	var b bytes.Buffer
+	writeTOMLString(&b, strings.ReplaceAll(k, "\n", " "))
+	return b.String()
+}
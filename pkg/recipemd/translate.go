@@ -0,0 +1,162 @@
+package recipemd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Translation is a recipe split into its translatable text and its
+// structure, for translation workflows. Messages hold the title,
+// description, tags, group titles, ingredient names, notes and
+// instructions; Skeleton is the RecipeMD document with every message
+// replaced by a "{{key}}" placeholder, keeping amounts, units, links and
+// the layout. Translators change the text of the messages and Recipe puts
+// the recipe back together.
+type Translation struct {
+	Skeleton string    `json:"skeleton"`
+	Messages []Message `json:"messages"`
+}
+
+// Message is a piece of translatable text. Description, notes and
+// instructions are markdown, the other texts plain text.
+type Message struct {
+	Key  string `json:"key"`
+	Text string `json:"text"`
+}
+
+func placeholder(key string) string {
+	return "{{" + key + "}}"
+}
+
+// Translation returns the translatable text of r apart from its structure.
+// Empty texts are left out.
+func (r *Recipe) Translation() (*Translation, error) {
+	t := &Translation{}
+	skeleton := r.Clone()
+	eachText(skeleton, func(key string, s *string) error {
+		if *s != "" {
+			t.Messages = append(t.Messages, Message{Key: key, Text: *s})
+			*s = placeholder(key)
+		}
+		return nil
+	})
+	var b bytes.Buffer
+	if err := WriteMarkdown(&b, skeleton); err != nil {
+		return nil, err
+	}
+	t.Skeleton = b.String()
+	return t, nil
+}
+
+// Recipe rebuilds the recipe from the skeleton and the messages of t. It
+// reports an error if a placeholder of the skeleton has no message, a
+// message has no placeholder or is empty, or a tag contains a comma.
+func (t *Translation) Recipe() (*Recipe, error) {
+	r, err := Parse([]byte(t.Skeleton))
+	if err != nil {
+		return nil, fmt.Errorf("recipemd: invalid skeleton: %w", err)
+	}
+	messages := make(map[string]string, len(t.Messages))
+	for _, m := range t.Messages {
+		if _, ok := messages[m.Key]; ok {
+			return nil, fmt.Errorf("recipemd: message %q given more than once", m.Key)
+		}
+		messages[m.Key] = m.Text
+	}
+	err = eachText(r, func(key string, s *string) error {
+		if *s == "" {
+			return nil
+		}
+		if *s != placeholder(key) {
+			return fmt.Errorf("recipemd: skeleton has %q where placeholder %s belongs", *s, placeholder(key))
+		}
+		text, ok := messages[key]
+		if !ok {
+			return fmt.Errorf("recipemd: no message for %s", placeholder(key))
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return fmt.Errorf("recipemd: message %q is empty", key)
+		}
+		if strings.HasPrefix(key, "tags.") && strings.Contains(text, ",") {
+			return fmt.Errorf("recipemd: tag %q of message %q contains a comma", text, key)
+		}
+		*s = text
+		delete(messages, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range t.Messages {
+		if _, ok := messages[m.Key]; ok {
+			return nil, fmt.Errorf("recipemd: message %q has no placeholder in the skeleton", m.Key)
+		}
+	}
+	return r, nil
+}
+
+// eachText calls fn with the key and a pointer to every translatable text
+// of r, in a fixed order, and stops at the first error.
+func eachText(r *Recipe, fn func(key string, s *string) error) error {
+	if err := fn("title", &r.Title); err != nil {
+		return err
+	}
+	if err := fn("description", &r.Description); err != nil {
+		return err
+	}
+	for i := range r.Tags {
+		if err := fn("tags."+strconv.Itoa(i), &r.Tags[i]); err != nil {
+			return err
+		}
+	}
+	if err := eachIngredientText("", r.Ingredients, r.IngredientNotes, r.IngredientGroups, fn); err != nil {
+		return err
+	}
+	if err := fn("instructions", &r.Instructions); err != nil {
+		return err
+	}
+	for i := range r.Appendices {
+		key := "appendices." + strconv.Itoa(i)
+		if err := fn(key+".title", &r.Appendices[i].Title); err != nil {
+			return err
+		}
+		if err := fn(key+".text", &r.Appendices[i].Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eachIngredientText(prefix string, ingredients []Ingredient, notes []Note, groups []IngredientGroup, fn func(string, *string) error) error {
+	for i := range ingredients {
+		key := prefix + "ingredients." + strconv.Itoa(i)
+		if err := fn(key+".name", &ingredients[i].Name); err != nil {
+			return err
+		}
+		if err := fn(key+".preparation", &ingredients[i].Preparation); err != nil {
+			return err
+		}
+		if err := fn(key+".note", &ingredients[i].Note); err != nil {
+			return err
+		}
+	}
+	for i := range notes {
+		if err := fn(prefix+"notes."+strconv.Itoa(i), &notes[i].Text); err != nil {
+			return err
+		}
+	}
+	for i := range groups {
+		g := &groups[i]
+		key := prefix + "groups." + strconv.Itoa(i)
+		if err := fn(key+".title", &g.Title); err != nil {
+			return err
+		}
+		if err := eachIngredientText(key+".", g.Ingredients, g.Notes, g.IngredientGroups, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
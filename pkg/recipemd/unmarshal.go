@@ -0,0 +1,172 @@
+package recipemd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// UnmarshalJSON decodes r from the JSON format written by MarshalJSON,
+// which includes that of the RecipeMD reference implementation. The data
+// is first validated against Schema.
+func (r *Recipe) UnmarshalJSON(data []byte) error {
+	if err := ValidateJSON(data); err != nil {
+		return err
+	}
+	var j struct {
+		Title            string            `json:"title"`
+		Description      *string           `json:"description"`
+		PrepTime         string            `json:"prep_time"`
+		CookTime         string            `json:"cook_time"`
+		TotalTime        string            `json:"total_time"`
+		Images           []Image           `json:"images"`
+		Yields           []jsonAmount      `json:"yields"`
+		Tags             []string          `json:"tags"`
+		Ingredients      []Ingredient      `json:"ingredients"`
+		IngredientNotes  []Note            `json:"ingredient_notes"`
+		IngredientGroups []IngredientGroup `json:"ingredient_groups"`
+		Instructions     *string           `json:"instructions"`
+		Appendices       []Appendix        `json:"appendices"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*r = Recipe{
+		Title:            j.Title,
+		Description:      deref(j.Description),
+		Images:           j.Images,
+		Ingredients:      j.Ingredients,
+		IngredientNotes:  j.IngredientNotes,
+		IngredientGroups: j.IngredientGroups,
+		Instructions:     deref(j.Instructions),
+		Appendices:       j.Appendices,
+	}
+	if len(j.Tags) > 0 {
+		r.Tags = j.Tags
+	}
+	for _, t := range []struct {
+		d *time.Duration
+		s string
+	}{{&r.PrepTime, j.PrepTime}, {&r.CookTime, j.CookTime}, {&r.TotalTime, j.TotalTime}} {
+		if t.s != "" {
+			*t.d = parseISODuration(t.s)
+		}
+	}
+	for _, y := range j.Yields {
+		a, err := fromJSONAmount(y)
+		if err != nil {
+			return err
+		}
+		r.Yields = append(r.Yields, a)
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes i from the JSON format written by MarshalJSON.
+func (i *Ingredient) UnmarshalJSON(data []byte) error {
+	var j struct {
+		Name        string      `json:"name"`
+		Amount      *jsonAmount `json:"amount"`
+		Link        *string     `json:"link"`
+		Pinned      bool        `json:"pinned"`
+		Preparation string      `json:"preparation"`
+		Optional    bool        `json:"optional"`
+		Note        string      `json:"note"`
+		Markdown    string      `json:"markdown"`
+		Image       *Image      `json:"image"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*i = Ingredient{
+		Image:       j.Image,
+		Name:        j.Name,
+		Link:        deref(j.Link),
+		Pinned:      j.Pinned,
+		Preparation: j.Preparation,
+		Optional:    j.Optional,
+		Note:        j.Note,
+		Markdown:    j.Markdown,
+	}
+	if j.Amount != nil {
+		a, err := fromJSONAmount(*j.Amount)
+		if err != nil {
+			return fmt.Errorf("recipemd: amount of %q: %w", j.Name, err)
+		}
+		i.Amount = &a
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes g from the JSON format written by MarshalJSON.
+func (g *IngredientGroup) UnmarshalJSON(data []byte) error {
+	var j struct {
+		Title            string            `json:"title"`
+		Ingredients      []Ingredient      `json:"ingredients"`
+		Notes            []Note            `json:"notes"`
+		IngredientGroups []IngredientGroup `json:"ingredient_groups"`
+		Image            *Image            `json:"image"`
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*g = IngredientGroup(j)
+	return nil
+}
+
+func fromJSONAmount(j jsonAmount) (Amount, error) {
+	a := Amount{Unit: deref(j.Unit), Approx: j.Approx}
+	var err error
+	if a.Factor, err = rat(j.Factor); err != nil {
+		return Amount{}, err
+	}
+	if a.Max, err = rat(j.Max); err != nil {
+		return Amount{}, err
+	}
+	if j.Size != nil {
+		size, err := fromJSONAmount(*j.Size)
+		if err != nil {
+			return Amount{}, err
+		}
+		a.Size = &size
+	}
+	return a, nil
+}
+
+// isoDurationRe matches the ISO 8601 durations allowed by Schema.
+var isoDurationRe = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseISODuration returns the duration s, which ValidateJSON checked
+// against isoDurationRe.
+func parseISODuration(s string) time.Duration {
+	var d time.Duration
+	m := isoDurationRe.FindStringSubmatch(s)
+	for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if i+1 < len(m) && m[i+1] != "" {
+			n, _ := strconv.Atoi(m[i+1])
+			d += time.Duration(n) * unit
+		}
+	}
+	return d
+}
+
+func rat(s *string) (*big.Rat, error) {
+	if s == nil {
+		return nil, nil
+	}
+	r, ok := new(big.Rat).SetString(*s)
+	if !ok {
+		return nil, fmt.Errorf("recipemd: invalid number %q", *s)
+	}
+	return r, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,416 @@
+package recipemd
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/xcapaldi/recipemd-go/pkg/ast"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+)
+
+// Edit replaces the bytes from Start up to End of a document with Text.
+type Edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// ApplyEdits returns a copy of source with the edits applied. The edits
+// must be sorted and must not overlap.
+func ApplyEdits(source []byte, edits []Edit) []byte {
+	var b bytes.Buffer
+	prev := 0
+	for _, e := range edits {
+		b.Write(source[prev:e.Start])
+		b.WriteString(e.Text)
+		prev = e.End
+	}
+	b.Write(source[prev:])
+	return b.Bytes()
+}
+
+// Update returns source with the Edits for r applied.
+func Update(source []byte, r *Recipe) ([]byte, error) {
+	edits, err := Edits(source, r)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyEdits(source, edits), nil
+}
+
+// Edits returns the edits that turn the RecipeMD document source into a
+// document of r while keeping the formatting of everything r leaves
+// unchanged. A changed title, description, tags, yields or instructions
+// replace their block. Changed amounts and group titles are edited in
+// place as long as the ingredients keep their names, links and order;
+// otherwise the ingredient section is rewritten as by WriteMarkdown. If a
+// divider needed for the new content is missing, the edit rewrites the
+// whole document. The edits are sorted and do not overlap.
+func Edits(source []byte, r *Recipe) ([]Edit, error) {
+	if r.Title == "" {
+		return nil, errors.New("recipemd: missing title: the recipe has no title")
+	}
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	d := &diagnostics{source: source}
+	old := extract(doc, d, &parseConfig{})
+	if err := d.firstError(); err != nil {
+		return nil, err
+	}
+	u := &updater{source: source}
+	var title, desc, tags, yields, ingredients, instructions gast.Node
+	var dividers []gast.Node
+	for c := doc.FirstChild().FirstChild(); c != nil; c = c.NextSibling() {
+		switch c.Kind() {
+		case ast.KindTitle:
+			title = c
+		case ast.KindDescription:
+			desc = c
+		case ast.KindTags:
+			tags = c
+		case ast.KindYields:
+			yields = c
+		case ast.KindIngredients:
+			ingredients = c
+		case ast.KindInstructions:
+			instructions = c
+		default:
+			dividers = append(dividers, c)
+		}
+	}
+
+	hasIngredients := len(r.Ingredients) > 0 || len(r.IngredientNotes) > 0 || len(r.IngredientGroups) > 0
+	instructionsMD := r.fullInstructions()
+	if (len(dividers) == 0 && (hasIngredients || instructionsMD != "")) ||
+		(hasIngredients && isEmpty(ingredients)) {
+		return u.rewrite(r), nil
+	}
+
+	if r.Title != old.Title {
+		u.replaceLines(title, escapeMarkdown(r.Title))
+	}
+	// blocks are inserted before the next block present in the source
+	next := func(nodes ...gast.Node) int {
+		for _, n := range nodes {
+			if n != nil && n.Lines().Len() > 0 {
+				return lineStart(source, n.Lines().At(0).Start)
+			}
+		}
+		return len(source)
+	}
+	var firstDivider gast.Node
+	if len(dividers) > 0 {
+		firstDivider = dividers[0]
+	}
+	if r.Description != old.Description {
+		u.block(desc, r.Description, next(tags, yields, firstDivider))
+	}
+	if !slices.Equal(r.Tags, old.Tags) {
+		u.block(tags, tagsMarkdown(r.Tags), next(yields, firstDivider))
+	}
+	if !slices.EqualFunc(r.Yields, old.Yields, func(a, b Amount) bool { return a.String() == b.String() }) {
+		u.block(yields, yieldsMarkdown(r.Yields), next(firstDivider))
+	}
+	if ingredients != nil {
+		u.ingredients(ingredients, old, r)
+	}
+	if instructionsMD != old.Instructions {
+		switch {
+		case instructions == nil:
+			u.append("---\n\n" + instructionsMD)
+		case isEmpty(instructions):
+			u.insertAfter(dividers[1], instructionsMD)
+		case instructionsMD == "":
+			_, end := u.span(dividers[1])
+			u.add(end, len(source), "\n")
+		default:
+			u.replaceLines(instructions, instructionsMD)
+		}
+	}
+	slices.SortStableFunc(u.edits, func(a, b Edit) int { return a.Start - b.Start })
+	return u.edits, nil
+}
+
+type updater struct {
+	source []byte
+	edits  []Edit
+}
+
+func (u *updater) add(start, end int, text string) {
+	u.edits = append(u.edits, Edit{Start: start, End: end, Text: text})
+}
+
+// rewrite returns a single edit replacing the document with r.
+func (u *updater) rewrite(r *Recipe) []Edit {
+	var b bytes.Buffer
+	_ = WriteMarkdown(&b, r)
+	return []Edit{{Start: 0, End: len(u.source), Text: b.String()}}
+}
+
+// span returns the range of the content of n without surrounding white
+// space.
+func (u *updater) span(n gast.Node) (int, int) {
+	lines := n.Lines()
+	s := text.NewSegment(lines.At(0).Start, lines.At(lines.Len()-1).Stop)
+	s = s.TrimLeftSpace(u.source)
+	s = s.TrimRightSpace(u.source)
+	return s.Start, s.Stop
+}
+
+// isEmpty reports whether the section n has no content.
+func isEmpty(n gast.Node) bool {
+	s := n.Lines().At(0)
+	return s.Len() == 0
+}
+
+// replaceLines replaces the content of n with s.
+func (u *updater) replaceLines(n gast.Node, s string) {
+	start, end := u.span(n)
+	u.add(start, end, s)
+}
+
+// block replaces the block n with s, removes it if s is empty or, if n is
+// absent, inserts s before the offset at.
+func (u *updater) block(n gast.Node, s string, at int) {
+	switch {
+	case n != nil && s == "":
+		start, end := u.span(n)
+		u.add(lineStart(u.source, start), u.skipSpace(end), "")
+	case n != nil:
+		u.replaceLines(n, s)
+	case s == "":
+	case at == len(u.source):
+		u.append(s)
+	default:
+		u.add(at, at, s+"\n\n")
+	}
+}
+
+// insertAfter inserts s as a block after the divider n.
+func (u *updater) insertAfter(n gast.Node, s string) {
+	_, end := u.span(n)
+	u.add(end, end, "\n\n"+s)
+}
+
+// append adds s as a block at the end of the document.
+func (u *updater) append(s string) {
+	sep := "\n\n"
+	switch {
+	case bytes.HasSuffix(u.source, []byte("\n\n")):
+		sep = ""
+	case bytes.HasSuffix(u.source, []byte("\n")):
+		sep = "\n"
+	}
+	u.add(len(u.source), len(u.source), sep+s+"\n")
+}
+
+// skipSpace returns the offset of the line of the first non-space byte at
+// or after i, or the end of the source.
+func (u *updater) skipSpace(i int) int {
+	for i < len(u.source) && strings.ContainsRune(" \t\r\n", rune(u.source[i])) {
+		i++
+	}
+	if i == len(u.source) {
+		return i
+	}
+	return lineStart(u.source, i)
+}
+
+// ingredients edits the ingredient section n from old to r.
+func (u *updater) ingredients(n gast.Node, old, r *Recipe) {
+	oldShape := ingredientShape(old.Ingredients, old.IngredientNotes, old.IngredientGroups, nil)
+	newShape := ingredientShape(r.Ingredients, r.IngredientNotes, r.IngredientGroups, nil)
+	var nodes []gast.Node
+	collectIngredientNodes(n, &nodes)
+	oldItems := flattenIngredients(old.Ingredients, old.IngredientGroups, nil)
+	newItems := flattenIngredients(r.Ingredients, r.IngredientGroups, nil)
+	if !slices.Equal(oldShape, newShape) || !aligned(nodes, oldItems, newItems) {
+		var parts []string
+		writeIngredients(func(s string) { parts = append(parts, s) }, r.Ingredients, r.IngredientNotes, r.IngredientGroups, 2, Amount.String)
+		s := strings.Join(parts, "\n\n")
+		if isEmpty(n) && s == "" {
+			return
+		}
+		u.replaceLines(n, s)
+		return
+	}
+	for i, node := range nodes {
+		switch node := node.(type) {
+		case *ast.IngredientGroup:
+			if t := newItems[i].(*IngredientGroup).Title; t != oldItems[i].(*IngredientGroup).Title {
+				u.replaceLines(node.FirstChild(), escapeMarkdown(t))
+			}
+		case *ast.Ingredient:
+			u.amount(node, *oldItems[i].(*Ingredient), *newItems[i].(*Ingredient))
+		}
+	}
+}
+
+// aligned reports whether the ingredient and group nodes of a section
+// correspond one to one to the flattened items of the recipe parsed from
+// it and of the edited recipe, so they can be edited in place.
+func aligned(nodes []gast.Node, oldItems, newItems []any) bool {
+	if len(nodes) != len(oldItems) || len(nodes) != len(newItems) {
+		return false
+	}
+	for i, node := range nodes {
+		switch node.(type) {
+		case *ast.IngredientGroup:
+			_, oldOK := oldItems[i].(*IngredientGroup)
+			_, newOK := newItems[i].(*IngredientGroup)
+			if !oldOK || !newOK {
+				return false
+			}
+		case *ast.Ingredient:
+			_, oldOK := oldItems[i].(*Ingredient)
+			_, newOK := newItems[i].(*Ingredient)
+			if !oldOK || !newOK {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// amount edits the amount of the ingredient node n from old to in.
+func (u *updater) amount(n *ast.Ingredient, old, in Ingredient) {
+	s := amountMarkdown(in)
+	if s == amountMarkdown(old) {
+		return
+	}
+	block := n.FirstChild()
+	a, ok := block.FirstChild().(*ast.Amount)
+	start, end := -1, -1
+	if ok && a.FirstChild() != nil {
+		start, end = inlineSpan(a)
+	}
+	switch {
+	case start < 0:
+		u.add(block.Lines().At(0).Start, block.Lines().At(0).Start, "*"+s+"* ")
+	case s == "":
+		end++
+		for end < len(u.source) && (u.source[end] == ' ' || u.source[end] == '\t') {
+			end++
+		}
+		u.add(start-1, end, "")
+	default:
+		u.add(start, end, s)
+	}
+}
+
+// inlineSpan returns the range of the text of the inline node n.
+func inlineSpan(n gast.Node) (int, int) {
+	start, end := -1, -1
+	_ = gast.Walk(n, func(c gast.Node, entering bool) (gast.WalkStatus, error) {
+		if t, ok := c.(*gast.Text); ok && entering {
+			if start < 0 {
+				start = t.Segment.Start
+			}
+			end = t.Segment.Stop
+		}
+		return gast.WalkContinue, nil
+	})
+	return start, end
+}
+
+// amountMarkdown returns the escaped text of the amount emphasis of in, or
+// the empty string if in has none.
+func amountMarkdown(in Ingredient) string {
+	if in.Amount == nil && !in.Pinned {
+		return ""
+	}
+	a := ""
+	if in.Amount != nil {
+		a = in.Amount.String()
+	}
+	if in.Pinned {
+		a = extension.PinMarker + a
+	}
+	return escapeMarkdown(a)
+}
+
+func tagsMarkdown(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	escaped := make([]string, len(tags))
+	for i, t := range tags {
+		escaped[i] = escapeMarkdown(t)
+	}
+	return "*" + strings.Join(escaped, ", ") + "*"
+}
+
+func yieldsMarkdown(yields []Amount) string {
+	if len(yields) == 0 {
+		return ""
+	}
+	escaped := make([]string, len(yields))
+	for i, y := range yields {
+		escaped[i] = escapeMarkdown(y.String())
+	}
+	return "**" + strings.Join(escaped, ", ") + "**"
+}
+
+// ingredientShape describes the ingredients, notes and groups in document
+// order without group titles and amounts, the parts edited in place.
+func ingredientShape(ingredients []Ingredient, notes []Note, groups []IngredientGroup, shape []string) []string {
+	for _, in := range ingredients {
+		shape = append(shape, "ingredient\x00"+in.text()+"\x00"+in.Link+"\x00"+in.Markdown+"\x00"+in.Note+"\x00"+imageShape(in.Image))
+	}
+	for _, n := range notes {
+		shape = append(shape, "note\x00"+strconv.Itoa(n.Index)+"\x00"+n.Text)
+	}
+	for _, g := range groups {
+		shape = append(shape, "group\x00"+imageShape(g.Image))
+		shape = ingredientShape(g.Ingredients, g.Notes, g.IngredientGroups, shape)
+		shape = append(shape, "end")
+	}
+	return shape
+}
+
+// flattenIngredients returns pointers to the ingredients and groups in
+// document order.
+func flattenIngredients(ingredients []Ingredient, groups []IngredientGroup, items []any) []any {
+	for i := range ingredients {
+		items = append(items, &ingredients[i])
+	}
+	for i := range groups {
+		items = append(items, &groups[i])
+		items = flattenIngredients(groups[i].Ingredients, groups[i].IngredientGroups, items)
+	}
+	return items
+}
+
+// collectIngredientNodes appends the ingredient and group nodes below n in
+// the order extraction visits them.
+func collectIngredientNodes(n gast.Node, nodes *[]gast.Node) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c.(type) {
+		case *ast.IngredientGroup, *ast.Ingredient:
+			*nodes = append(*nodes, c)
+			collectIngredientNodes(c, nodes)
+		case *gast.List:
+			collectIngredientNodes(c, nodes)
+		}
+	}
+}
+
+// lineStart returns the offset of the beginning of the line containing i.
+func lineStart(source []byte, i int) int {
+	for i > 0 && source[i-1] != '\n' {
+		i--
+	}
+	return i
+}
+
+func imageShape(img *Image) string {
+	if img == nil {
+		return ""
+	}
+	return img.URL + "\x00" + img.Alt + "\x00" + img.Title
+}
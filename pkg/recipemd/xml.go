@@ -0,0 +1,67 @@
+package recipemd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteXML writes r to w as an XML document with the structure of the
+// recipe JSON, for XSLT pipelines and other XML tooling. The root element
+// is recipe and every member is an element named after its key, in the
+// same order. The items of an array are elements named after the
+// singular of the key, such as the ingredient elements of ingredients.
+// Null members are left out and empty arrays are empty elements.
+func WriteXML(w io.Writer, r *Recipe) error {
+	tree, err := recipeTree(r)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	writeXMLElement(&b, 0, "recipe", tree)
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// writeXMLElement writes v as the element name, indented for its depth.
+func writeXMLElement(b *bytes.Buffer, depth int, name string, v any) {
+	indent := strings.Repeat("  ", depth)
+	var text string
+	switch v := v.(type) {
+	case nil:
+		return
+	case []jsonMember:
+		b.WriteString(indent + "<" + name + ">\n")
+		for _, m := range v {
+			writeXMLElement(b, depth+1, m.key, m.value)
+		}
+		b.WriteString(indent + "</" + name + ">\n")
+		return
+	case []any:
+		if len(v) == 0 {
+			b.WriteString(indent + "<" + name + "/>\n")
+			return
+		}
+		b.WriteString(indent + "<" + name + ">\n")
+		for _, e := range v {
+			writeXMLElement(b, depth+1, singular(name), e)
+		}
+		b.WriteString(indent + "</" + name + ">\n")
+		return
+	case string:
+		text = v
+	case json.Number:
+		text = v.String()
+	case bool:
+		text = strconv.FormatBool(v)
+	}
+	// EscapeText escapes line breaks and tabs, which XML keeps in text
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(text))
+	text = strings.NewReplacer("&#xA;", "\n", "&#x9;", "\t").Replace(escaped.String())
+	b.WriteString(indent + "<" + name + ">" + text + "</" + name + ">\n")
+}
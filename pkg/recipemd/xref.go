@@ -0,0 +1,115 @@
+package recipemd
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// phrase is a sequence of words that names an ingredient in instructions.
+type phrase struct {
+	words      []string // stemmed
+	ingredient int      // index in AllIngredients
+}
+
+// ingredientPhrases returns the phrases by which the ingredients can be
+// mentioned, longest first: each full name and, for names of several
+// words, the last word if no other ingredient ends in it, so "all-purpose
+// flour" is found in "sift the flour".
+func ingredientPhrases(ingredients []Ingredient) []phrase {
+	var phrases []phrase
+	lastWords := make(map[string]map[string]bool) // last word to full names
+	for i, in := range ingredients {
+		words := stems(in.Name)
+		if len(words) == 0 {
+			continue
+		}
+		phrases = append(phrases, phrase{words, i})
+		last := words[len(words)-1]
+		if lastWords[last] == nil {
+			lastWords[last] = make(map[string]bool)
+		}
+		lastWords[last][strings.Join(words, " ")] = true
+	}
+	for i, in := range ingredients {
+		words := stems(in.Name)
+		if len(words) < 2 {
+			continue
+		}
+		last := words[len(words)-1]
+		if len(lastWords[last]) == 1 && len([]rune(last)) >= 3 {
+			phrases = append(phrases, phrase{[]string{last}, i})
+		}
+	}
+	slices.SortStableFunc(phrases, func(a, b phrase) int {
+		return cmp.Compare(len(b.words), len(a.words))
+	})
+	return phrases
+}
+
+// mentions returns the indexes of the ingredients mentioned in the words,
+// in ascending order. Longer phrases win: "brown sugar" is a mention of
+// brown sugar and not of sugar as well.
+func mentions(words []string, phrases []phrase) []int {
+	used := make([]bool, len(words))
+	var found []int
+	for _, p := range phrases {
+	search:
+		for start := 0; start+len(p.words) <= len(words); start++ {
+			for k, w := range p.words {
+				if used[start+k] || words[start+k] != w {
+					continue search
+				}
+			}
+			for k := range p.words {
+				used[start+k] = true
+			}
+			if !slices.Contains(found, p.ingredient) {
+				found = append(found, p.ingredient)
+			}
+		}
+	}
+	slices.Sort(found)
+	return found
+}
+
+// nodeWords returns the stemmed words of the text in n.
+func nodeWords(n gast.Node, source []byte) []string {
+	var b strings.Builder
+	_ = gast.Walk(n, func(c gast.Node, entering bool) (gast.WalkStatus, error) {
+		if t, ok := c.(*gast.Text); ok && entering {
+			b.Write(util.UnescapePunctuations(t.Segment.Value(source)))
+			b.WriteByte(' ')
+		}
+		return gast.WalkContinue, nil
+	})
+	return stems(b.String())
+}
+
+// stems splits s into lower case words and strips plural endings, so
+// "Eggs" and "egg" and "tomatoes" and "tomato" compare equal.
+func stems(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = stem(w)
+	}
+	return words
+}
+
+func stem(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case len(w) > 3 && strings.HasSuffix(w, "oes"):
+		return w[:len(w)-2]
+	case len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		return w[:len(w)-1]
+	}
+	return w
+}
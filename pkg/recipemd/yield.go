@@ -0,0 +1,78 @@
+package recipemd
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/units"
+)
+
+// PrimaryYield returns the first yield of r with a non-zero factor, the
+// one features like per-serving values are based on.
+func (r *Recipe) PrimaryYield() (Amount, bool) {
+	for _, y := range r.Yields {
+		if y.Factor != nil && y.Factor.Sign() != 0 {
+			return y, true
+		}
+	}
+	return Amount{}, false
+}
+
+// YieldValue returns how much of unit r yields. Units match ignoring case
+// and a plural "s", so "serving" finds "4 Servings". If r has no yield in
+// unit but one in a known unit of the same class, it is converted, so
+// "kg" finds "500 g" as 1/2.
+func (r *Recipe) YieldValue(unit string) (*big.Rat, bool) {
+	for _, y := range r.Yields {
+		if y.Factor != nil && y.Factor.Sign() != 0 && sameUnit(y.Unit, unit) {
+			return new(big.Rat).Set(y.Factor), true
+		}
+	}
+	for _, y := range r.Yields {
+		if y.Factor == nil || y.Factor.Sign() == 0 {
+			continue
+		}
+		if ratio, ok := units.Ratio(y.Unit, unit); ok {
+			return ratio.Mul(ratio, y.Factor), true
+		}
+	}
+	return nil, false
+}
+
+// ConvertYield converts an amount of one of r's yields into unit, another
+// of its yields: for a recipe yielding "4 servings, 600 g", 1 serving
+// converts to 150 g.
+func (r *Recipe) ConvertYield(a Amount, unit string) (Amount, error) {
+	f, err := r.YieldFactor(a)
+	if err != nil {
+		return Amount{}, err
+	}
+	v, ok := r.YieldValue(unit)
+	if !ok {
+		return Amount{}, fmt.Errorf("recipemd: recipe has no yield in %q", unit)
+	}
+	return Amount{Factor: v.Mul(v, f), Unit: unit}, nil
+}
+
+// YieldFactor returns the factor by which r must be scaled to produce want.
+// The yield of r in the unit of want is found as by YieldValue.
+func (r *Recipe) YieldFactor(want Amount) (*big.Rat, error) {
+	if want.Factor == nil {
+		return nil, fmt.Errorf("recipemd: yield %q has no amount", want.Unit)
+	}
+	v, ok := r.YieldValue(want.Unit)
+	if !ok {
+		return nil, fmt.Errorf("recipemd: recipe has no yield in %q", want.Unit)
+	}
+	return v.Quo(want.Factor, v), nil
+}
+
+// sameUnit reports whether the units a and b are equal ignoring case,
+// white space and their plural, known or formed with "s".
+func sameUnit(a, b string) bool {
+	norm := func(s string) string {
+		return strings.TrimSuffix(strings.ToLower(strings.Join(strings.Fields(units.Singular(s)), " ")), "s")
+	}
+	return norm(a) == norm(b)
+}
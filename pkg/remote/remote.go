@@ -0,0 +1,276 @@
+// Package remote fetches RecipeMD files from http and https URLs, so that
+// an ingredient can link to a recipe in another repository, as in
+// "[pizza dough](https://example.org/recipes/dough.md)".
+//
+// A Fetcher limits how many requests run at a time, gives each a timeout,
+// fetches every URL once however many callers ask for it concurrently and
+// keeps what it fetched, in memory and optionally in a store.Store, so
+// later runs only ask the server whether a file changed.
+package remote
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/store"
+)
+
+// ErrNotRemote is returned by Source and Fetch for links that are not
+// http or https URLs.
+var ErrNotRemote = errors.New("remote: not an http or https URL")
+
+// Fetcher fetches and parses remote RecipeMD files. The zero value is
+// ready to use; it is safe for concurrent use and must not be copied
+// after first use.
+type Fetcher struct {
+	Client   *http.Client  // defaults to http.DefaultClient
+	Timeout  time.Duration // of each request, defaults to 10 seconds
+	Parallel int           // requests at a time, defaults to 4
+	MaxAge   time.Duration // use a fetched file this long without asking again, defaults to an hour
+	MaxSize  int64         // of a file in bytes, defaults to 1 MiB
+	Cache    store.Store   // keeps fetched files across runs if set
+
+	once  sync.Once
+	sem   chan struct{}
+	mu    sync.Mutex
+	mem   map[string]*entry
+	calls map[string]*call
+}
+
+// entry is a fetched file with the validators to ask the server whether
+// it changed.
+type entry struct {
+	Source       []byte    `json:"source"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Fetched      time.Time `json:"fetched"`
+}
+
+// call is a fetch in progress that other callers of the URL wait for.
+type call struct {
+	done   chan struct{}
+	source []byte
+	err    error
+}
+
+// IsRemote reports whether link is an http or https URL.
+func IsRemote(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (f *Fetcher) init() {
+	f.once.Do(func() {
+		f.sem = make(chan struct{}, cmp.Or(f.Parallel, 4))
+		f.mem = make(map[string]*entry)
+		f.calls = make(map[string]*call)
+	})
+}
+
+// Fetch returns the recipe at the URL. The fragment of the URL is ignored.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*recipemd.Recipe, error) {
+	source, err := f.Source(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	r, err := recipemd.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("remote: %s: %w", rawURL, err)
+	}
+	return r, nil
+}
+
+// Source returns the file at the URL. A file fetched less than MaxAge ago
+// is returned without a request; an older one is revalidated with the
+// server. If that fails, the old file is returned anyway, so that a
+// server that is down does not break recipes that were fetched before.
+func (f *Fetcher) Source(ctx context.Context, rawURL string) ([]byte, error) {
+	f.init()
+	u, err := url.Parse(rawURL)
+	if err != nil || !IsRemote(rawURL) {
+		return nil, fmt.Errorf("%w: %q", ErrNotRemote, rawURL)
+	}
+	u.Fragment = ""
+	key := u.String()
+
+	f.mu.Lock()
+	if c, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.source, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	f.calls[key] = c
+	f.mu.Unlock()
+
+	c.source, c.err = f.source(ctx, key)
+	f.mu.Lock()
+	delete(f.calls, key)
+	f.mu.Unlock()
+	close(c.done)
+	return c.source, c.err
+}
+
+func (f *Fetcher) source(ctx context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	e := f.mem[key]
+	f.mu.Unlock()
+	if e == nil {
+		e = f.load(key)
+	}
+	if e != nil && time.Since(e.Fetched) < cmp.Or(f.MaxAge, time.Hour) {
+		return e.Source, nil
+	}
+	fresh, err := f.get(ctx, key, e)
+	if err != nil {
+		if e != nil {
+			return e.Source, nil
+		}
+		return nil, err
+	}
+	f.mu.Lock()
+	f.mem[key] = fresh
+	f.mu.Unlock()
+	f.save(key, fresh)
+	return fresh.Source, nil
+}
+
+// get requests the URL, conditionally if old is not nil.
+func (f *Fetcher) get(ctx context.Context, rawURL string, old *entry) (*entry, error) {
+	select {
+	case f.sem <- struct{}{}:
+		defer func() { <-f.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	ctx, cancel := context.WithTimeout(ctx, cmp.Or(f.Timeout, 10*time.Second))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/markdown, text/plain;q=0.9, */*;q=0.1")
+	if old != nil {
+		if old.ETag != "" {
+			req.Header.Set("If-None-Match", old.ETag)
+		}
+		if old.LastModified != "" {
+			req.Header.Set("If-Modified-Since", old.LastModified)
+		}
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && old != nil:
+		e := *old
+		e.Fetched = time.Now()
+		return &e, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("remote: %s: %s", rawURL, resp.Status)
+	}
+	limit := cmp.Or(f.MaxSize, 1<<20)
+	source, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("remote: %s: %w", rawURL, err)
+	}
+	if int64(len(source)) > limit {
+		return nil, fmt.Errorf("remote: %s: larger than %d bytes", rawURL, limit)
+	}
+	return &entry{
+		Source:       source,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Fetched:      time.Now(),
+	}, nil
+}
+
+// cacheKey returns the key of the URL in the Cache.
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return "remote/" + hex.EncodeToString(sum[:]) + ".json"
+}
+
+// load returns the entry of the URL in the Cache, or nil.
+func (f *Fetcher) load(rawURL string) *entry {
+	if f.Cache == nil {
+		return nil
+	}
+	data, err := f.Cache.Load(cacheKey(rawURL))
+	if err != nil {
+		return nil
+	}
+	var e entry
+	if json.Unmarshal(data, &e) != nil {
+		return nil
+	}
+	return &e
+}
+
+// save stores e in the Cache. Failing to is not an error: the file is
+// fetched again next time.
+func (f *Fetcher) save(rawURL string, e *entry) {
+	if f.Cache == nil {
+		return
+	}
+	if data, err := json.Marshal(e); err == nil {
+		f.Cache.Save(cacheKey(rawURL), data)
+	}
+}
+
+// Result is the outcome of fetching the recipe of one link.
+type Result struct {
+	Recipe *recipemd.Recipe
+	Err    error
+}
+
+// Linked fetches the recipes that the ingredients of r link to by http
+// or https URLs, concurrently within the limits of f, and returns them by
+// link. Local links are left to the caller. Linked recipes are not
+// followed further.
+func (f *Fetcher) Linked(ctx context.Context, r *recipemd.Recipe) map[string]Result {
+	var links []string
+	for _, in := range r.AllIngredients() {
+		if IsRemote(in.Link) && !slices.Contains(links, in.Link) {
+			links = append(links, in.Link)
+		}
+	}
+	results := make([]Result, len(links))
+	var wg sync.WaitGroup
+	for i, link := range links {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			linked, err := f.Fetch(ctx, link)
+			results[i] = Result{Recipe: linked, Err: err}
+		}()
+	}
+	wg.Wait()
+	byLink := make(map[string]Result, len(links))
+	for i, link := range links {
+		byLink[link] = results[i]
+	}
+	return byLink
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"io/fs"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// summaryLength is the maximum length of recipe summaries.
+const summaryLength = 160
+
+// summary is the JSON representation of a recipe in lists.
+type summary struct {
+	Slug       string   `json:"slug"`
+	Title      string   `json:"title"`
+	Summary    string   `json:"summary"`
+	Tags       []string `json:"tags"`
+	URL        string   `json:"url"`
+	Incomplete bool     `json:"incomplete,omitempty"` // parsed despite errors
+}
+
+func summaries(recipes []*collection.Recipe) []summary {
+	s := make([]summary, len(recipes))
+	for i, e := range recipes {
+		tags := e.Recipe.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		s[i] = summary{Slug: e.Slug, Title: e.Recipe.Title, Summary: e.Summary(summaryLength), Tags: tags, URL: "/api/recipes/" + e.Slug, Incomplete: !e.Report.Complete()}
+	}
+	return s
+}
+
+func (h *Handler) serveAPIRecipes(w http.ResponseWriter, r *http.Request) {
+	recipes, err := h.search("")
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, summaries(recipes))
+}
+
+func (h *Handler) serveAPIRecipe(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+	if !fs.ValidPath(slug + ".md") {
+		writeJSONError(w, http.StatusNotFound, "recipe not found")
+		return
+	}
+	r.Header.Set("Accept", "application/json")
+	h.serveEntry(w, r, slug+".md")
+}
+
+func (h *Handler) serveAPITags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tags()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, tags)
+}
+
+func (h *Handler) serveAPISearch(w http.ResponseWriter, r *http.Request) {
+	r.Header.Set("Accept", "application/json")
+	h.serveIndex(w, r)
+}
+
+func (h *Handler) serveAPIDecode(w http.ResponseWriter, r *http.Request) {
+	r.Header.Set("Accept", "application/json")
+	h.serveDecode(w, r)
+}
+
+func (h *Handler) serveAPICompare(w http.ResponseWriter, r *http.Request) {
+	r.Header.Set("Accept", "application/json")
+	h.serveCompare(w, r)
+}
+
+func (h *Handler) serveAPISchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	_, _ = w.Write(recipemd.Schema())
+}
+
+// wantsJSON reports whether the Accept header of r prefers JSON to HTML.
+// Of the two, the media type listed first with the higher quality wins.
+func wantsJSON(r *http.Request) bool {
+	best, bestQ := "", -1.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || (mt != "application/json" && mt != "text/html") {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = mt, q
+		}
+	}
+	return best == "application/json" && bestQ > 0
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(b, '\n'))
+}
+
+func writeJSONError(w http.ResponseWriter, code int, msg string) {
+	b, _ := json.Marshal(struct {
+		Error string `json:"error"`
+	}{msg})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(append(b, '\n'))
+}
+
+// notFound responds with 404 in the format the request prefers.
+func notFound(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		writeJSONError(w, http.StatusNotFound, "not found")
+		return
+	}
+	http.NotFound(w, r)
+}
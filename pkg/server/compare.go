@@ -0,0 +1,228 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// Revisions returns the source of the file at the slash-separated path p,
+// relative to the collection, as of the revision rev, for example a git
+// commit.
+type Revisions func(rev, p string) ([]byte, error)
+
+// WithRevisions lets the comparison page compare revisions of recipes
+// read with fn, such as earlier commits of a git repository.
+func WithRevisions(fn Revisions) Option {
+	return func(h *Handler) {
+		h.revisions = fn
+	}
+}
+
+// errRecipeNotFound is returned by load for paths not in the collection.
+var errRecipeNotFound = errors.New("recipe not found")
+
+// comparison is the data of the comparison page.
+type comparison struct {
+	Paths       []string // of all recipes, for the form
+	Revisions   bool     // whether revisions can be compared
+	Old, New    side
+	Factor      string // set if the new version is a rescaling
+	Changes     []string
+	Fields      []compareRow
+	Ingredients []compareRow
+}
+
+// side is one of the two recipes compared.
+type side struct {
+	Path string
+	Rev  string
+}
+
+// Label returns the path of s with its revision, if any.
+func (s side) Label() string {
+	if s.Rev == "" {
+		return s.Path
+	}
+	return s.Path + " @ " + s.Rev
+}
+
+// compareRow is a line of the side-by-side view. Class is "", "changed",
+// "added" or "removed".
+type compareRow struct {
+	Label    string
+	Old, New string
+	Class    string
+}
+
+func (h *Handler) serveCompare(w http.ResponseWriter, r *http.Request) {
+	snap, err := h.snapshot()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	c := &comparison{
+		Revisions: h.revisions != nil,
+		Old:       side{Path: r.FormValue("old"), Rev: strings.TrimSpace(r.FormValue("oldrev"))},
+		New:       side{Path: r.FormValue("new"), Rev: strings.TrimSpace(r.FormValue("newrev"))},
+	}
+	if c.New.Path == "" {
+		c.New.Path = c.Old.Path
+	}
+	for _, e := range snap.Recipes() {
+		c.Paths = append(c.Paths, e.Path)
+	}
+	data := page{Title: "Compare", Compare: c}
+	if c.Old.Path == "" {
+		if wantsJSON(r) {
+			writeJSONError(w, http.StatusBadRequest, "missing the query parameter old")
+			return
+		}
+		h.render(w, compareTemplate, data)
+		return
+	}
+	old, err := h.load(snap, c.Old)
+	if err == nil {
+		var cur *recipemd.Recipe
+		if cur, err = h.load(snap, c.New); err == nil {
+			c.compare(old, cur)
+			data.Title = "Compare " + c.Old.Label() + " and " + c.New.Label()
+		}
+	}
+	switch {
+	case errors.Is(err, errRecipeNotFound):
+		notFound(w, r)
+	case err != nil && wantsJSON(r):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	case wantsJSON(r):
+		writeJSON(w, struct {
+			Old     string   `json:"old"`
+			New     string   `json:"new"`
+			Factor  *string  `json:"factor"`
+			Changes []string `json:"changes"`
+		}{c.Old.Label(), c.New.Label(), nullable(c.Factor), c.Changes})
+	default:
+		if err != nil {
+			data.Error = err.Error()
+		}
+		h.render(w, compareTemplate, data)
+	}
+}
+
+// load returns the recipe of one side of a comparison, from the snapshot
+// or, for a revision, read with h.revisions.
+func (h *Handler) load(snap *collection.Snapshot, s side) (*recipemd.Recipe, error) {
+	if !fs.ValidPath(s.Path) || !strings.EqualFold(path.Ext(s.Path), ".md") {
+		return nil, errRecipeNotFound
+	}
+	if s.Rev == "" {
+		e, ok := snap.Get(strings.TrimSuffix(s.Path, path.Ext(s.Path)))
+		if !ok || e.Path != s.Path {
+			return nil, errRecipeNotFound
+		}
+		return e.Recipe, nil
+	}
+	if h.revisions == nil {
+		return nil, errors.New("comparing revisions is not enabled")
+	}
+	source, err := h.revisions(s.Rev, s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Label(), err)
+	}
+	r, err := recipemd.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Label(), err)
+	}
+	return r, nil
+}
+
+// compare fills c with the differences between old and cur.
+func (c *comparison) compare(old, cur *recipemd.Recipe) {
+	d := recipemd.Diff(old, cur)
+	if d.Factor != nil {
+		c.Factor = amount.Format(d.Factor)
+	}
+	for _, ch := range d.Changes {
+		c.Changes = append(c.Changes, ch.String())
+	}
+	field := func(label, o, n string) {
+		row := compareRow{Label: label, Old: o, New: n}
+		if o != n {
+			row.Class = "changed"
+		}
+		c.Fields = append(c.Fields, row)
+	}
+	field("Title", old.Title, cur.Title)
+	field("Tags", strings.Join(old.Tags, ", "), strings.Join(cur.Tags, ", "))
+	field("Yields", amounts(old.Yields), amounts(cur.Yields))
+	field("Description", old.Description, cur.Description)
+	field("Instructions", old.Instructions, cur.Instructions)
+	c.Ingredients = ingredientRows(old.AllIngredients(), cur.AllIngredients())
+}
+
+// ingredientRows lines up the ingredients of two versions by name,
+// ignoring case, the way Diff matches them: the ingredients of the old
+// version in order, each next to its match, followed by the added ones.
+func ingredientRows(old, cur []recipemd.Ingredient) []compareRow {
+	unmatched := make(map[string][]int)
+	for i, in := range cur {
+		key := nameKey(in.Name)
+		unmatched[key] = append(unmatched[key], i)
+	}
+	matched := make([]bool, len(cur))
+	var rows []compareRow
+	for _, o := range old {
+		key := nameKey(o.Name)
+		if len(unmatched[key]) == 0 {
+			rows = append(rows, compareRow{Old: ingredientLine(o), Class: "removed"})
+			continue
+		}
+		n := cur[unmatched[key][0]]
+		matched[unmatched[key][0]] = true
+		unmatched[key] = unmatched[key][1:]
+		row := compareRow{Old: ingredientLine(o), New: ingredientLine(n)}
+		if row.Old != row.New {
+			row.Class = "changed"
+		}
+		rows = append(rows, row)
+	}
+	for i, n := range cur {
+		if !matched[i] {
+			rows = append(rows, compareRow{New: ingredientLine(n), Class: "added"})
+		}
+	}
+	return rows
+}
+
+func nameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func ingredientLine(in recipemd.Ingredient) string {
+	if in.Amount == nil {
+		return in.Name
+	}
+	return in.Amount.String() + " " + in.Name
+}
+
+func amounts(list []recipemd.Amount) string {
+	s := make([]string, len(list))
+	for i, a := range list {
+		s[i] = a.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+)
+
+// serveDecode renders the recipe packed by recipemd.EncodeFragment into
+// the query parameter r. Browsers do not send the fragment of a URL, so
+// without r the page moves the fragment of a shared link such as
+// /decode#... into the query.
+func (h *Handler) serveDecode(w http.ResponseWriter, r *http.Request) {
+	packed := r.FormValue("r")
+	if packed == "" {
+		if wantsJSON(r) {
+			writeJSONError(w, http.StatusBadRequest, "missing parameter r")
+			return
+		}
+		h.render(w, decodeTemplate, page{Title: "Shared recipe"})
+		return
+	}
+	rec, err := recipemd.DecodeFragment(packed)
+	if err != nil {
+		if wantsJSON(r) {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+	if wantsJSON(r) {
+		writeJSON(w, rec)
+		return
+	}
+	var md, b bytes.Buffer
+	if err := recipemd.WriteMarkdown(&md, rec); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := h.md.Convert(md.Bytes(), &b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.render(w, recipeTemplate, page{Title: rec.Title, Description: rec.Summary(summaryLength), HTML: template.HTML(b.String())})
+}
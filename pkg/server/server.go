@@ -0,0 +1,374 @@
+// Package server serves a collection of RecipeMD files as a website with
+// an index page, tag pages and search.
+//
+// The collection is refreshed on every request, or in the background by
+// Collection.Watch if the handler is created with Watched, and recipes are
+// rendered when they are requested, so edits show up on the next page load
+// without a build step. Every request is answered from a single snapshot
+// of the collection.
+package server
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gext "github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+	"github.com/xcapaldi/recipemd-go/pkg/filter"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/slug"
+)
+
+// Handler is an http.Handler serving the recipes of a collection:
+//
+//	/               index of all recipes, filtered by the query parameter q
+//	/tags/          list of all tags
+//	/tags/{slug}    recipes with the tag, identified by its slug
+//	/r/{path}       a recipe rendered as HTML
+//	/compare        two recipes side by side, the paths given by the query
+//	                parameters old and new, with the changes highlighted;
+//	                with WithRevisions, oldrev and newrev select revisions
+//	/decode         a recipe packed into the query parameter r, or the
+//	                fragment, by recipemd.EncodeFragment
+//
+// The query q is a filter expression as understood by package filter; a
+// recipe whose title contains the query also matches.
+//
+// These pages respond with JSON instead of HTML if the request's Accept
+// header prefers application/json. The same data is always available as
+// JSON under /api:
+//
+//	/api/recipes          summaries of all recipes
+//	/api/recipes/{slug}   a recipe in the JSON format of the RecipeMD
+//	                      reference implementation; the slug is its path
+//	                      without the .md extension; with the parameter
+//	                      steps, its instruction_steps too
+//	/api/tags             all tags
+//	/api/search?q=        summaries of the recipes matching q
+//	/api/compare?old=     the changes between two recipes
+//	/api/schema           the JSON Schema of recipes
+//	/api/decode?r=        a recipe packed by recipemd.EncodeFragment
+type Handler struct {
+	c         *collection.Collection
+	watched   bool
+	revisions Revisions
+	post      []recipemd.PostProcessor
+	md        goldmark.Markdown
+	mux       *http.ServeMux
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// Watched makes the handler serve the current snapshot of the collection
+// without refreshing it first, for collections that are kept up to date
+// by Collection.Watch. A refresh then never delays a request and a failing
+// one leaves the last good snapshot in service.
+func Watched() Option {
+	return func(h *Handler) {
+		h.watched = true
+	}
+}
+
+// WithPostProcessors passes every HTML page through fns before it is
+// sent. JSON responses are sent as they are.
+func WithPostProcessors(fns ...recipemd.PostProcessor) Option {
+	return func(h *Handler) {
+		h.post = append(h.post, fns...)
+	}
+}
+
+// NewHandler returns a Handler serving the recipes of c. Unless the Watched
+// option is given, the collection is refreshed on every request.
+func NewHandler(c *collection.Collection, opts ...Option) *Handler {
+	h := &Handler{
+		c:   c,
+		mux: http.NewServeMux(),
+	}
+	// recipes are served at their paths, so the linked file is the page
+	recipeMD := extension.New(extension.WithLinkResolver(recipemd.WebLinks(c.FS(), func(p string) string { return p })))
+	h.md = goldmark.New(goldmark.WithExtensions(gext.Table, gext.Footnote, recipeMD))
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("GET /{$}", h.serveIndex)
+	h.mux.HandleFunc("GET /tags/{$}", h.serveTags)
+	h.mux.HandleFunc("GET /tags/{tag}", h.serveTag)
+	h.mux.HandleFunc("GET /r/{path...}", h.serveRecipe)
+	h.mux.HandleFunc("GET /compare", h.serveCompare)
+	h.mux.HandleFunc("GET /decode", h.serveDecode)
+	h.mux.HandleFunc("GET /api/recipes", h.serveAPIRecipes)
+	h.mux.HandleFunc("GET /api/recipes/{slug...}", h.serveAPIRecipe)
+	h.mux.HandleFunc("GET /api/tags", h.serveAPITags)
+	h.mux.HandleFunc("GET /api/search", h.serveAPISearch)
+	h.mux.HandleFunc("GET /api/compare", h.serveAPICompare)
+	h.mux.HandleFunc("GET /api/schema", h.serveAPISchema)
+	h.mux.HandleFunc("GET /api/decode", h.serveAPIDecode)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// pages, their errors and 304 responses depend on whether the
+	// request prefers JSON
+	w.Header().Set("Vary", "Accept")
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.FormValue("q"))
+	recipes, err := h.search(q)
+	var syntax *filter.SyntaxError
+	if err != nil && !errors.As(err, &syntax) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if wantsJSON(r) {
+		if syntax != nil {
+			writeJSONError(w, http.StatusBadRequest, syntax.Error())
+			return
+		}
+		writeJSON(w, summaries(recipes))
+		return
+	}
+	data := page{Title: "Recipes", Query: q, Recipes: recipes}
+	if q != "" {
+		data.Title = "Search: " + q
+	}
+	if syntax != nil {
+		data.Error = syntax.Error()
+	}
+	h.render(w, indexTemplate, data)
+}
+
+func (h *Handler) serveTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tags()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if wantsJSON(r) {
+		writeJSON(w, tags)
+		return
+	}
+	h.render(w, tagsTemplate, page{Title: "Tags", Tags: tags})
+}
+
+func (h *Handler) serveTag(w http.ResponseWriter, r *http.Request) {
+	tag, recipes, err := h.tagged(r.PathValue("tag"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(recipes) == 0 {
+		notFound(w, r)
+		return
+	}
+	if wantsJSON(r) {
+		writeJSON(w, summaries(recipes))
+		return
+	}
+	h.render(w, indexTemplate, page{Title: "Tag: " + tag, Recipes: recipes})
+}
+
+func (h *Handler) serveRecipe(w http.ResponseWriter, r *http.Request) {
+	p := r.PathValue("path")
+	if !fs.ValidPath(p) || !strings.EqualFold(path.Ext(p), ".md") {
+		notFound(w, r)
+		return
+	}
+	h.serveEntry(w, r, p)
+}
+
+// serveEntry serves the recipe at path p.
+func (h *Handler) serveEntry(w http.ResponseWriter, r *http.Request, p string) {
+	snap, err := h.snapshot()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := snap.Errors()[p]; err != nil {
+		msg := p + ": " + err.Error()
+		if wantsJSON(r) {
+			writeJSONError(w, http.StatusInternalServerError, msg)
+		} else {
+			http.Error(w, msg, http.StatusInternalServerError)
+		}
+		return
+	}
+	e, ok := snap.Get(strings.TrimSuffix(p, path.Ext(p)))
+	if !ok || e.Path != p && snap.Aliases()[p] != e.Path {
+		notFound(w, r)
+		return
+	}
+	asJSON := wantsJSON(r)
+	if notModified(w, r, e, asJSON) {
+		return
+	}
+	if asJSON {
+		rec := e.Recipe
+		if r.URL.Query().Has("steps") {
+			rec = rec.Clone()
+			rec.InstructionSteps = rec.Steps()
+		}
+		writeJSON(w, rec)
+		return
+	}
+	var b bytes.Buffer
+	pc := parser.NewContext()
+	extension.SetDocumentPath(pc, p)
+	if err := h.md.Convert(e.Source, &b, parser.WithContext(pc)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.render(w, recipeTemplate, page{Title: e.Title, Description: e.Summary(summaryLength), Recipe: e, HTML: template.HTML(b.String())})
+}
+
+// notModified sets the ETag header for the JSON or HTML representation
+// of e and, if the request's If-None-Match header lists it, responds with
+// 304 Not Modified and reports true. The tags are weak because the HTML
+// is rendered from the source, which may change in layout without
+// changing the fingerprint.
+func notModified(w http.ResponseWriter, r *http.Request, e *collection.Recipe, asJSON bool) bool {
+	kind := "html"
+	if asJSON {
+		kind = "json"
+		if r.URL.Query().Has("steps") {
+			kind = "json-steps"
+		}
+	}
+	etag := `W/"` + e.Fingerprint() + "-" + kind + `"`
+	w.Header().Set("ETag", etag)
+	for _, t := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(etag, "W/") {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot returns the snapshot of the collection to answer a request
+// from, refreshing the collection first unless it is watched.
+func (h *Handler) snapshot() (*collection.Snapshot, error) {
+	if !h.watched {
+		if _, err := h.c.Refresh(); err != nil {
+			return nil, err
+		}
+	}
+	return h.c.Snapshot(), nil
+}
+
+// search returns the recipes matching the query q, or all recipes if q is
+// empty. If q is not a valid filter expression only titles are matched and
+// the *filter.SyntaxError is returned along with the result.
+func (h *Handler) search(q string) ([]*collection.Recipe, error) {
+	snap, err := h.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	recipes := snap.Recipes()
+	if q == "" {
+		return recipes, nil
+	}
+	query, err := collection.ParseQuery(q)
+	hits := make(map[*collection.Recipe]bool)
+	if query != nil {
+		for _, e := range snap.Query(query) {
+			hits[e] = true
+		}
+	}
+	var matched []*collection.Recipe
+	for _, e := range recipes {
+		if hits[e] || strings.Contains(strings.ToLower(e.Title), strings.ToLower(q)) {
+			matched = append(matched, e)
+		}
+	}
+	return matched, err
+}
+
+// tags returns the tags of all recipes, sorted and without duplicates.
+func (h *Handler) tags() ([]string, error) {
+	snap, err := h.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	tags := snap.Tags()
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
+
+// tagged returns the tag with the slug and the recipes with the tag.
+func (h *Handler) tagged(s string) (string, []*collection.Recipe, error) {
+	snap, err := h.snapshot()
+	if err != nil {
+		return "", nil, err
+	}
+	for tag, ts := range slug.Unique(snap.Tags()) {
+		if ts == s {
+			return tag, snap.Tagged(tag), nil
+		}
+	}
+	return s, nil, nil
+}
+
+// tagSlugs returns the slugs of the tags of the collection, distinct for
+// all of them, or nil if it cannot be read.
+func (h *Handler) tagSlugs() map[string]string {
+	snap, err := h.snapshot()
+	if err != nil {
+		return nil
+	}
+	return slug.Unique(snap.Tags())
+}
+
+// page is the data of a page template.
+type page struct {
+	Title       string
+	Description string // summary for meta descriptions
+	Query       string
+	Error       string
+	Recipes     []*collection.Recipe
+	Tags        []string
+	Recipe      *collection.Recipe
+	HTML        template.HTML
+	Compare     *comparison
+
+	tagSlugs map[string]string // by tag
+}
+
+// TagSlug returns the slug of the page of tag.
+func (p page) TagSlug(tag string) string {
+	if s, ok := p.tagSlugs[tag]; ok {
+		return s
+	}
+	return slug.Make(tag)
+}
+
+func (h *Handler) render(w http.ResponseWriter, t *template.Template, data page) {
+	data.tagSlugs = h.tagSlugs()
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	out, err := recipemd.PostProcess(b.Bytes(), h.post...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(out)
+}
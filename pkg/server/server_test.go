@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+)
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	c, err := collection.Load(fstest.MapFS{
+		"tea.md": {Data: []byte("# Tea\n\n*hot*\n\n---\n\n- *1* tea bag\n\n---\n\nSteep.\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHandler(c)
+}
+
+func TestVary(t *testing.T) {
+	h := newTestHandler(t)
+	tests := []struct {
+		path, accept string
+		code         int
+	}{
+		{"/", "text/html", http.StatusOK},
+		{"/", "application/json", http.StatusOK},
+		{"/r/tea.md", "text/html", http.StatusOK},
+		{"/r/tea.md", "application/json", http.StatusOK},
+		{"/api/recipes/tea", "", http.StatusOK},
+		{"/tags/hot", "application/json", http.StatusOK},
+		{"/r/missing.md", "application/json", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("Accept", tt.accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("GET %s (%s): status %d, want %d", tt.path, tt.accept, rec.Code, tt.code)
+		}
+		if vary := rec.Header().Values("Vary"); len(vary) != 1 || vary[0] != "Accept" {
+			t.Errorf("GET %s (%s): Vary = %q, want [Accept]", tt.path, tt.accept, vary)
+		}
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	h := newTestHandler(t)
+	for _, accept := range []string{"text/html", "application/json"} {
+		req := httptest.NewRequest("GET", "/r/tea.md", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		etag := rec.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("%s: no ETag", accept)
+		}
+
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("%s: status %d with matching ETag, want 304", accept, rec.Code)
+		}
+		if vary := rec.Header().Values("Vary"); len(vary) != 1 || vary[0] != "Accept" {
+			t.Errorf("%s: 304 has Vary %q, want [Accept]", accept, vary)
+		}
+	}
+}
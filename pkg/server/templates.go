@@ -0,0 +1,101 @@
+package server
+
+import "html/template"
+
+const layout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+{{with .Description}}<meta name="description" content="{{.}}">
+<meta property="og:description" content="{{.}}">
+{{end}}<meta property="og:title" content="{{.Title}}">
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 0 auto; padding: 1em; line-height: 1.4; }
+nav { display: flex; gap: 1em; align-items: center; border-bottom: 1px solid #ccc; padding-bottom: .5em; }
+nav form { margin-left: auto; }
+.tags a { margin-right: .5em; }
+.error { color: #a00; }
+.amount { font-style: italic; }
+table.compare { width: 100%; border-collapse: collapse; table-layout: fixed; }
+table.compare th, table.compare td { text-align: left; vertical-align: top; padding: .2em .4em; white-space: pre-wrap; overflow-wrap: anywhere; }
+table.compare th:first-child { width: 7em; }
+.changed { background: #fff5cc; }
+.added { background: #e3f7e3; }
+.removed { background: #fde4e4; }
+</style>
+</head>
+<body>
+<nav>
+<a href="/">Recipes</a>
+<a href="/tags/">Tags</a>
+<a href="/compare">Compare</a>
+<form action="/" method="get"><input type="search" name="q" value="{{.Query}}" placeholder="tag:vegan or title:soup"></form>
+</nav>
+{{block "content" .}}{{end}}
+</body>
+</html>
+`
+
+var indexTemplate = newTemplate("index", `
+{{define "content"}}
+<h1 dir="auto">{{.Title}}</h1>
+{{with .Error}}<p class="error">{{.}}</p>{{end}}
+<ul>
+{{range .Recipes}}<li dir="auto"><a href="/r/{{.Path}}">{{.Recipe.Title}}</a>{{with .Recipe.Tags}} <small class="tags">{{range .}}<a href="/tags/{{$.TagSlug .}}">{{.}}</a>{{end}}</small>{{end}}</li>
+{{else}}<li>No recipes found.</li>
+{{end}}</ul>
+{{end}}`)
+
+var tagsTemplate = newTemplate("tags", `
+{{define "content"}}
+<h1 dir="auto">{{.Title}}</h1>
+<ul>
+{{range .Tags}}<li dir="auto"><a href="/tags/{{$.TagSlug .}}">{{.}}</a></li>
+{{end}}</ul>
+{{end}}`)
+
+var compareTemplate = newTemplate("compare", `
+{{define "content"}}
+<h1 dir="auto">Compare</h1>
+{{with .Compare}}
+<form action="/compare" method="get">
+<p><select name="old">{{$old := .Old.Path}}{{range .Paths}}<option{{if eq . $old}} selected{{end}}>{{.}}</option>{{end}}</select>
+{{if .Revisions}}<input name="oldrev" value="{{.Old.Rev}}" placeholder="revision" size="10">{{end}}
+with
+<select name="new">{{$new := .New.Path}}{{range .Paths}}<option{{if eq . $new}} selected{{end}}>{{.}}</option>{{end}}</select>
+{{if .Revisions}}<input name="newrev" value="{{.New.Rev}}" placeholder="revision" size="10">{{end}}
+<button>Compare</button></p>
+</form>
+{{end}}
+{{with .Error}}<p class="error">{{.}}</p>{{end}}
+{{with .Compare}}{{if .Fields}}
+{{with .Factor}}<p>Rescaled by {{.}}.</p>{{end}}
+{{if .Changes}}<ul>{{range .Changes}}<li dir="auto">{{.}}</li>{{end}}</ul>{{else}}<p>No changes.</p>{{end}}
+<table class="compare">
+<tr><th></th><th dir="auto">{{.Old.Label}}</th><th dir="auto">{{.New.Label}}</th></tr>
+{{range .Fields}}<tr class="{{.Class}}"><th>{{.Label}}</th><td dir="auto">{{.Old}}</td><td dir="auto">{{.New}}</td></tr>
+{{end}}<tr><th colspan="3">Ingredients</th></tr>
+{{range .Ingredients}}<tr class="{{.Class}}"><th></th><td dir="auto">{{.Old}}</td><td dir="auto">{{.New}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+{{end}}`)
+
+var recipeTemplate = newTemplate("recipe", `
+{{define "content"}}{{.HTML}}{{end}}`)
+
+var decodeTemplate = newTemplate("decode", `
+{{define "content"}}
+<h1>Shared recipe</h1>
+<p>Links of the form /decode#… carry a whole recipe. This one has none.</p>
+<script>
+if (location.hash.length > 1) location.replace("/decode?r=" + encodeURIComponent(location.hash.slice(1)));
+</script>
+{{end}}`)
+
+// newTemplate returns the page template with the content template.
+func newTemplate(name, content string) *template.Template {
+	t := template.New(name)
+	return template.Must(template.Must(t.Parse(layout)).Parse(content))
+}
@@ -0,0 +1,365 @@
+// Package site generates a static website from a recipe collection.
+//
+// The site consists of an index of all recipes, a page per recipe at the
+// recipe's path with the extension .html, a tag index at tags/index.html
+// with a page per tag at tags/{slug}.html, a style sheet, and copies of the
+// images found next to the recipes and of the files the recipes show as
+// images. Links between recipes are rewritten to point to the generated
+// pages, including the links of ingredients to recipes in other
+// directories, at the root of the collection or without an extension, as
+// resolved by recipemd.ResolveLink. Image paths starting with a slash are taken relative to the
+// collection root, and all links are relative so the site can be served
+// from any path.
+package site
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	gext "github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+	"github.com/xcapaldi/recipemd-go/pkg/extension"
+	"github.com/xcapaldi/recipemd-go/pkg/recipemd"
+	"github.com/xcapaldi/recipemd-go/pkg/slug"
+)
+
+// imageExts are the extensions of the files copied to the site.
+var imageExts = map[string]bool{
+	".avif": true,
+	".gif":  true,
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+	".svg":  true,
+	".webp": true,
+}
+
+// summaryLength is the maximum length of the recipe summaries in meta
+// descriptions and the index.
+const summaryLength = 160
+
+// Option configures Build and Rebuild.
+type Option func(*builder)
+
+// WithPostProcessors passes every HTML page through fns before it is
+// written. The style sheet and images are written as they are.
+func WithPostProcessors(fns ...recipemd.PostProcessor) Option {
+	return func(b *builder) {
+		b.post = append(b.post, fns...)
+	}
+}
+
+// WithHTMLOptions renders the recipes with opts, options of the
+// extension's HTMLRenderer such as extension.WithLang and goldmark's html
+// options.
+func WithHTMLOptions(opts ...html.Option) Option {
+	return func(b *builder) {
+		b.html = append(b.html, opts...)
+	}
+}
+
+// Build writes the site for c to the directory dir, creating it if
+// necessary. Existing files in dir are overwritten but not removed.
+func Build(c *collection.Collection, dir string, opts ...Option) error {
+	if _, err := c.Refresh(); err != nil {
+		return err
+	}
+	b := newBuilder(c, dir, opts)
+	if err := b.write("style.css", []byte(style)); err != nil {
+		return err
+	}
+	if err := b.indexes(); err != nil {
+		return err
+	}
+	for _, r := range c.Recipes() {
+		if err := b.recipe(r); err != nil {
+			return err
+		}
+	}
+	return b.copyImages()
+}
+
+// Rebuild updates a site written by Build after c was refreshed and the
+// recipe files at the paths changed. It writes the pages of the changed
+// recipes and of the recipes linking to them, removes the pages of
+// removed recipes and writes the index and tag pages again.
+func Rebuild(c *collection.Collection, dir string, changed []string, opts ...Option) error {
+	b := newBuilder(c, dir, opts)
+	if err := b.indexes(); err != nil {
+		return err
+	}
+	for _, p := range changed {
+		name := strings.TrimSuffix(p, path.Ext(p))
+		if r, ok := c.Get(name); ok && r.Path == p {
+			if err := b.recipe(r); err != nil {
+				return err
+			}
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, filepath.FromSlash(name+".html")))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	for _, r := range c.Dependents(changed...) {
+		if err := b.recipe(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newBuilder(c *collection.Collection, dir string, opts []Option) *builder {
+	aliases := c.Aliases()
+	pageURL := func(p string) string {
+		if canonical, ok := aliases[p]; ok {
+			p = canonical
+		}
+		if !strings.EqualFold(path.Ext(p), ".md") {
+			return p
+		}
+		return strings.TrimSuffix(p, path.Ext(p)) + ".html"
+	}
+	b := &builder{c: c, dir: dir}
+	for _, opt := range opts {
+		opt(b)
+	}
+	recipeMD := extension.New(
+		extension.WithLinkResolver(recipemd.WebLinks(c.FS(), pageURL)),
+		extension.WithHTMLOptions(b.html...),
+	)
+	b.md = goldmark.New(
+		goldmark.WithExtensions(gext.Table, gext.Footnote, recipeMD),
+		goldmark.WithParserOptions(parser.WithASTTransformers(
+			util.Prioritized(linkTransformer{}, 1000),
+		)),
+	)
+	return b
+}
+
+type builder struct {
+	c        *collection.Collection
+	dir      string
+	md       goldmark.Markdown
+	post     []recipemd.PostProcessor
+	html     []html.Option
+	tagSlugs map[string]string
+}
+
+// indexes writes the index of all recipes and the tag pages.
+func (b *builder) indexes() error {
+	if err := b.page("index.html", indexTemplate, page{Title: "Recipes", Recipes: b.c.Recipes()}); err != nil {
+		return err
+	}
+	tags := b.c.Tags()
+	b.tagSlugs = slug.Unique(tags)
+	if err := b.page("tags/index.html", tagsTemplate, page{Title: "Tags", Tags: tags}); err != nil {
+		return err
+	}
+	for _, t := range tags {
+		p := page{Title: "Tag: " + t, Recipes: b.c.Tagged(t)}
+		if err := b.page("tags/"+b.tagSlugs[t]+".html", indexTemplate, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rootKey is the parser context key of the relative path from the page
+// being converted to the site root.
+var rootKey = parser.NewContextKey()
+
+// recipe writes the page of r and copies the files of its images.
+func (b *builder) recipe(r *collection.Recipe) error {
+	pc := parser.NewContext()
+	pc.Set(rootKey, strings.Repeat("../", strings.Count(r.Slug, "/")))
+	extension.SetDocumentPath(pc, r.Path)
+	html := buffers.Get().(*bytes.Buffer)
+	defer putBuffer(html)
+	if err := b.md.Convert(r.Source, html, parser.WithContext(pc)); err != nil {
+		return err
+	}
+	p := page{Title: r.Title, Description: r.Summary(summaryLength), Recipe: r, HTML: template.HTML(html.String())}
+	if err := b.page(r.Slug+".html", recipeTemplate, p); err != nil {
+		return err
+	}
+	for _, img := range slices.Concat(r.Images, r.IngredientImages()) {
+		p, ok := assetPath(r.Path, img.URL)
+		if !ok {
+			continue
+		}
+		data, err := fs.ReadFile(b.c.FS(), p)
+		if errors.Is(err, fs.ErrNotExist) {
+			// a broken link is for the link checker to report
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := b.write(p, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assetPath returns the path in the collection of the file at url, linked
+// from the recipe at the slash-separated path from. Paths starting with a
+// slash are relative to the root of the collection. It reports false for
+// links to other sites and files outside the collection.
+func assetPath(from, url string) (string, bool) {
+	if url == "" || strings.Contains(url, ":") || strings.HasPrefix(url, "//") || strings.HasPrefix(url, "#") {
+		return "", false
+	}
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	p, err := neturl.PathUnescape(url)
+	if err != nil {
+		return "", false
+	}
+	if strings.HasPrefix(p, "/") {
+		p = path.Clean(p[1:])
+	} else {
+		p = path.Join(path.Dir(from), p)
+	}
+	return p, fs.ValidPath(p) && p != "."
+}
+
+// page is the data of a page template.
+type page struct {
+	Title       string
+	Description string // summary for meta descriptions
+	Root        string // relative path from the page to the site root
+	Recipes     []*collection.Recipe
+	Tags        []string
+	Recipe      *collection.Recipe
+	HTML        template.HTML
+
+	tagSlugs map[string]string // by tag, distinct for all tags of the collection
+}
+
+// TagSlug returns the slug of the page of tag.
+func (p page) TagSlug(tag string) string {
+	if s, ok := p.tagSlugs[tag]; ok {
+		return s
+	}
+	return slug.Make(tag)
+}
+
+// buffers holds the buffers pages are rendered into, so a build of many
+// pages reuses a few grown buffers instead of growing one per page.
+var buffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// putBuffer returns b to buffers, unless it grew too large to keep.
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > 1<<20 {
+		return
+	}
+	b.Reset()
+	buffers.Put(b)
+}
+
+// page renders t with data to the slash-separated path name.
+func (b *builder) page(name string, t *template.Template, data page) error {
+	data.Root = strings.Repeat("../", strings.Count(name, "/"))
+	data.tagSlugs = b.tagSlugs
+	buf := buffers.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+	if err := t.Execute(buf, data); err != nil {
+		return err
+	}
+	out, err := recipemd.PostProcess(buf.Bytes(), b.post...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return b.write(name, out)
+}
+
+// write writes data to the slash-separated path name in the site.
+func (b *builder) write(name string, data []byte) error {
+	p := filepath.Join(b.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+// copyImages copies the images of the collection's file system.
+func (b *builder) copyImages() error {
+	fsys := b.c.FS()
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !imageExts[strings.ToLower(path.Ext(p))] {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return b.write(p, data)
+	})
+}
+
+// linkTransformer points relative links to markdown files at the pages
+// generated for them and makes image paths relative to the collection
+// root relative to the page.
+type linkTransformer struct{}
+
+func (linkTransformer) Transform(doc *gast.Document, reader text.Reader, pc parser.Context) {
+	root, _ := pc.Get(rootKey).(string)
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *gast.Link:
+			n.Destination = []byte(pageLink(string(n.Destination)))
+		case *gast.Image:
+			if d := string(n.Destination); strings.HasPrefix(d, "/") && !strings.HasPrefix(d, "//") {
+				n.Destination = []byte(root + d[1:])
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+// pageLink replaces the .md extension of a relative link with .html,
+// keeping any query or fragment.
+func pageLink(link string) string {
+	if strings.Contains(link, "://") || strings.HasPrefix(link, "/") || strings.HasPrefix(link, "#") {
+		return link
+	}
+	p, rest := link, ""
+	if i := strings.IndexAny(link, "?#"); i >= 0 {
+		p, rest = link[:i], link[i:]
+	}
+	if !strings.EqualFold(path.Ext(p), ".md") {
+		return link
+	}
+	return strings.TrimSuffix(p, path.Ext(p)) + ".html" + rest
+}
@@ -0,0 +1,81 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xcapaldi/recipemd-go/pkg/collection"
+)
+
+func TestBuildTagPages(t *testing.T) {
+	c, err := collection.Load(fstest.MapFS{
+		"tacos.md":   {Data: []byte("# Tacos\n\n*Taco Tuesday*\n\n---\n\n- *2* tortillas\n")},
+		"burrito.md": {Data: []byte("# Burrito\n\n*🌮 Taco Tuesday, 🌯*\n\n---\n\n- *1* tortilla\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := Build(c, dir); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		tag, file string
+		recipe    string
+	}{
+		{"Taco Tuesday", "tags/taco-tuesday.html", "Tacos"},
+		{"🌮 Taco Tuesday", "tags/taco-tuesday-2.html", "Burrito"},
+		{"🌯", "tags/u1f32f.html", "Burrito"},
+	}
+	index, err := os.ReadFile(filepath.Join(dir, "tags", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		page, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(tt.file)))
+		if err != nil {
+			t.Errorf("tag %q: %v", tt.tag, err)
+			continue
+		}
+		if !strings.Contains(string(page), "<title>Tag: "+tt.tag) || !strings.Contains(string(page), ">"+tt.recipe+"</a>") {
+			t.Errorf("%s is not the page of %q listing %s:\n%s", tt.file, tt.tag, tt.recipe, page)
+		}
+		link := `href="` + strings.TrimPrefix(tt.file, "tags/") + `">` + tt.tag + "<"
+		if !strings.Contains(string(index), link) {
+			t.Errorf("tag index does not link %s:\n%s", link, index)
+		}
+	}
+}
+
+// BenchmarkSiteBuild builds a site of 100 recipes, each with 40
+// ingredients and 200 instruction steps.
+func BenchmarkSiteBuild(b *testing.B) {
+	fsys := fstest.MapFS{}
+	for i := range 100 {
+		var r strings.Builder
+		fmt.Fprintf(&r, "# Recipe %d\n\nA *large* recipe.\n\n*batch, tag %d*\n\n**4 servings**\n\n---\n\n", i, i%10)
+		for j := range 40 {
+			fmt.Fprintf(&r, "- *%d g* ingredient %d, chopped\n", j+1, j)
+		}
+		r.WriteString("\n---\n\n")
+		for j := range 200 {
+			fmt.Fprintf(&r, "%d. Stir for *%d minutes* and taste.\n", j+1, j)
+		}
+		fsys[fmt.Sprintf("recipe-%d.md", i)] = &fstest.MapFile{Data: []byte(r.String())}
+	}
+	c, err := collection.Load(fsys)
+	if err != nil {
+		b.Fatal(err)
+	}
+	dir := b.TempDir()
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := Build(c, dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
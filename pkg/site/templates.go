@@ -0,0 +1,79 @@
+package site
+
+import "html/template"
+
+const style = `body {
+  font-family: Georgia, serif;
+  max-width: 42em;
+  margin: 0 auto;
+  padding: 1em;
+  line-height: 1.5;
+  color: #222;
+  background: #fffdf8;
+}
+nav {
+  display: flex;
+  gap: 1em;
+  border-bottom: 1px solid #ddd;
+  padding-bottom: .5em;
+  font-family: sans-serif;
+}
+a { color: #a33; }
+ul.recipes { list-style: none; padding: 0; }
+ul.recipes li { margin: .5em 0; }
+ul.recipes .summary { margin: .2em 0 0; color: #555; font-size: .9em; }
+.tags a, ul.tags li { margin-right: .5em; font-size: .85em; }
+ul.tags, ul.yields { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: .5em; }
+.amount { font-style: italic; }
+.ingredients { background: #f6f1e7; padding: .5em 1em; border-radius: 4px; }
+.ingredient-group { display: flow-root; }
+.group-image { float: right; margin: 0 0 .5em 1em; }
+.group-image img, .ingredient-image img { max-width: 8em; border-radius: 4px; }
+`
+
+const layout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+{{with .Description}}<meta name="description" content="{{.}}">
+<meta property="og:description" content="{{.}}">
+{{end}}<meta property="og:title" content="{{.Title}}">
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<nav>
+<a href="{{.Root}}index.html">Recipes</a>
+<a href="{{.Root}}tags/index.html">Tags</a>
+</nav>
+{{block "content" .}}{{end}}
+</body>
+</html>
+`
+
+var indexTemplate = newTemplate("index", `
+{{define "content"}}
+<h1 dir="auto">{{.Title}}</h1>
+<ul class="recipes">
+{{range .Recipes}}<li dir="auto"><a href="{{$.Root}}{{.Slug}}.html">{{.Title}}</a>{{with .Tags}} <small class="tags">{{range .}}<a href="{{$.Root}}tags/{{$.TagSlug .}}.html">{{.}}</a>{{end}}</small>{{end}}{{with .Summary 160}}<p class="summary">{{.}}</p>{{end}}</li>
+{{else}}<li>No recipes.</li>
+{{end}}</ul>
+{{end}}`)
+
+var tagsTemplate = newTemplate("tags", `
+{{define "content"}}
+<h1 dir="auto">{{.Title}}</h1>
+<ul>
+{{range .Tags}}<li dir="auto"><a href="{{$.TagSlug .}}.html">{{.}}</a></li>
+{{end}}</ul>
+{{end}}`)
+
+var recipeTemplate = newTemplate("recipe", `
+{{define "content"}}{{.HTML}}{{end}}`)
+
+// newTemplate returns the page template with the content template.
+func newTemplate(name, content string) *template.Template {
+	t := template.New(name)
+	return template.Must(template.Must(t.Parse(layout)).Parse(content))
+}
@@ -0,0 +1,97 @@
+// Package slug turns titles and tags into strings usable as URL path
+// segments, file names and HTML ids.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Make returns the slug of s: its letters and digits in lower case, in any
+// script, with runs of other characters replaced by a single hyphen.
+// Emoji and other symbols are dropped, so "🌮 Taco Tuesday" becomes
+// "taco-tuesday", unless s consists of nothing else; then they are spelled
+// out as code points, as in "u1f32e" for "🌮", so that no non-blank s has an
+// empty slug. Different strings can have the same slug; use a Set or
+// Unique where slugs must be distinct.
+func Make(s string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || (unicode.IsMark(r) && b.Len() > 0 && !sep) {
+			if sep && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			sep = false
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		if r == '\'' || r == '’' {
+			// "chef's" rather than "chef-s"
+			continue
+		}
+		sep = true
+	}
+	if b.Len() > 0 {
+		return b.String()
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || isJoiner(r) {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString("u" + strconv.FormatInt(int64(r), 16))
+	}
+	return b.String()
+}
+
+// isJoiner reports whether r only modifies the rendering of the characters
+// around it, like the zero width joiner and variation selectors in emoji
+// sequences.
+func isJoiner(r rune) bool {
+	return r == '\u200d' || unicode.Is(unicode.Variation_Selector, r)
+}
+
+// A Set tells apart slugs that collide, such as those of "🌮 Taco Tuesday"
+// and "Taco Tuesday". The zero value is an empty set.
+type Set struct {
+	used map[string]bool
+}
+
+// Add returns the slug of s and adds it to the set. If the slug is taken,
+// "-2", "-3" and so on is appended to it until it is not, as in
+// "taco-tuesday-2". Blank strings have the empty slug, which is returned
+// as is.
+func (set *Set) Add(s string) string {
+	base := Make(s)
+	if base == "" {
+		return ""
+	}
+	if set.used == nil {
+		set.used = make(map[string]bool)
+	}
+	slug := base
+	for n := 2; set.used[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	set.used[slug] = true
+	return slug
+}
+
+// Unique returns distinct slugs for the distinct strings of ss, by string.
+// Of strings whose slugs collide, the first in ss keeps the slug and the
+// others get a number appended, as Set.Add does, so the slugs depend on
+// the order of ss.
+func Unique(ss []string) map[string]string {
+	var set Set
+	slugs := make(map[string]string, len(ss))
+	for _, s := range ss {
+		if _, ok := slugs[s]; !ok {
+			slugs[s] = set.Add(s)
+		}
+	}
+	return slugs
+}
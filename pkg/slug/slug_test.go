@@ -0,0 +1,77 @@
+package slug
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"Taco Tuesday", "taco-tuesday"},
+		{"🌮 Taco Tuesday", "taco-tuesday"},
+		{"Taco 🌮 Tuesday!", "taco-tuesday"},
+		{"Chef's Special", "chefs-special"},
+		{"Chef’s Special", "chefs-special"},
+		{"Crème brûlée", "crème-brûlée"},
+		{"Crème", "crème"},
+		{"Grüner Veltliner Risotto", "grüner-veltliner-risotto"},
+		{"麻婆豆腐", "麻婆豆腐"},
+		{"Борщ", "борщ"},
+		{"كبسة", "كبسة"},
+		{"Top 10 (best)", "top-10-best"},
+		{"🌮", "u1f32e"},
+		{"🌮 🌯", "u1f32e-u1f32f"},
+		{"❤️", "u2764"},
+		{"👨‍🍳", "u1f468-u1f373"},
+	}
+	for _, tt := range tests {
+		if got := Make(tt.in); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSetAdd(t *testing.T) {
+	var set Set
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"Taco Tuesday", "taco-tuesday"},
+		{"🌮 Taco Tuesday", "taco-tuesday-2"},
+		{"Taco Tuesday", "taco-tuesday-3"},
+		{"taco tuesday 2", "taco-tuesday-2-2"},
+		{"", ""},
+		{"", ""},
+		{"Sauce", "sauce"},
+	} {
+		if got := set.Add(tt.in); got != tt.want {
+			t.Errorf("Add(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want map[string]string
+	}{
+		{nil, map[string]string{}},
+		{
+			[]string{"taco", "🌮 taco", "taco", "Taco!"},
+			map[string]string{"taco": "taco", "🌮 taco": "taco-2", "Taco!": "taco-3"},
+		},
+		{
+			[]string{"🌮", "vegan"},
+			map[string]string{"🌮": "u1f32e", "vegan": "vegan"},
+		},
+	}
+	for _, tt := range tests {
+		if got := Unique(tt.in); !maps.Equal(got, tt.want) {
+			t.Errorf("Unique(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// sqlStore stores documents in the rows of a database table.
+type sqlStore struct {
+	db    *sql.DB
+	table string
+}
+
+// tableRe matches the table names SQL accepts, which are written into
+// statements unquoted.
+var tableRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQL returns a Store keeping documents in the table of db, which is
+// created if it does not exist with a text column key and a blob column
+// data. The statements are written for SQLite; db can use any SQLite
+// driver, such as modernc.org/sqlite or github.com/mattn/go-sqlite3, and
+// other databases that accept SQLite's syntax for placeholders and
+// upserts.
+func SQL(db *sql.DB, table string) (Store, error) {
+	if !tableRe.MatchString(table) {
+		return nil, fmt.Errorf("store: invalid table name %q", table)
+	}
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + table + ` (key TEXT PRIMARY KEY, data BLOB NOT NULL)`)
+	if err != nil {
+		return nil, fmt.Errorf("store: %w", err)
+	}
+	return &sqlStore{db: db, table: table}, nil
+}
+
+func (s *sqlStore) Load(key string) ([]byte, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	var data []byte
+	err := s.db.QueryRow(`SELECT data FROM `+s.table+` WHERE key = ?`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *sqlStore) Save(key string, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	_, err := s.db.Exec(`INSERT INTO `+s.table+` (key, data) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET data = excluded.data`, key, data)
+	return err
+}
+
+func (s *sqlStore) Delete(key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	res, err := s.db.Exec(`DELETE FROM `+s.table+` WHERE key = ?`, key)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlStore) Keys(prefix string) ([]string, error) {
+	// a range instead of LIKE, whose wildcards would need escaping
+	rows, err := s.db.Query(`SELECT key FROM `+s.table+` WHERE key >= ? ORDER BY key`, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(key, prefix) {
+			break
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,37 @@
+// Package store keeps keyed documents, such as meal plans, journals and
+// price lists, in a backend the user chooses: a directory with Dir or a
+// database with SQL.
+//
+// Keys are slash-separated paths like "plans/2026-w42.md", valid as
+// defined by fs.ValidPath, so the same keys work with every backend.
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// ErrNotFound is returned by Load and Delete for keys without a document.
+var ErrNotFound = errors.New("store: document not found")
+
+// Store loads and saves documents by key. Implementations are safe for
+// concurrent use.
+type Store interface {
+	// Load returns the document stored under key.
+	Load(key string) ([]byte, error)
+	// Save stores data under key, replacing any document stored there.
+	Save(key string, data []byte) error
+	// Delete removes the document stored under key.
+	Delete(key string) error
+	// Keys returns the keys starting with prefix in lexical order.
+	Keys(prefix string) ([]string, error)
+}
+
+// checkKey returns an error if key is not a valid key.
+func checkKey(key string) error {
+	if !fs.ValidPath(key) || key == "." {
+		return fmt.Errorf("store: invalid key %q", key)
+	}
+	return nil
+}
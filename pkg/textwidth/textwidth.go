@@ -0,0 +1,220 @@
+// Package textwidth measures and wraps text by the number of terminal
+// columns it occupies. East Asian wide characters, such as CJK ideographs,
+// kana, hangul and most emoji, take two columns; combining marks and
+// joiners take none. An emoji sequence is as wide as its first emoji:
+// skin tone modifiers and emoji joined by a zero width joiner, as in
+// "👍🏽" and "👨‍👩‍👧", add no width.
+package textwidth
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wide are the ranges of East Asian Wide and Fullwidth characters.
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1},
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x23f0, 0x23f0, 1},
+		{0x23f3, 0x23f3, 1},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x267f, 0x267f, 1},
+		{0x2693, 0x2693, 1},
+		{0x26a1, 0x26a1, 1},
+		{0x26aa, 0x26ab, 1},
+		{0x26bd, 0x26be, 1},
+		{0x26c4, 0x26c5, 1},
+		{0x26ce, 0x26ce, 1},
+		{0x26d4, 0x26d4, 1},
+		{0x26ea, 0x26ea, 1},
+		{0x26f2, 0x26f3, 1},
+		{0x26f5, 0x26f5, 1},
+		{0x26fa, 0x26fa, 1},
+		{0x26fd, 0x26fd, 1},
+		{0x2705, 0x2705, 1},
+		{0x270a, 0x270b, 1},
+		{0x2728, 0x2728, 1},
+		{0x274c, 0x274c, 1},
+		{0x274e, 0x274e, 1},
+		{0x2753, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1},
+		{0x27b0, 0x27b0, 1},
+		{0x27bf, 0x27bf, 1},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b50, 1},
+		{0x2b55, 0x2b55, 1},
+		{0x2e80, 0x303e, 1},
+		{0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0x9fff, 1},
+		{0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1},
+		{0xfe30, 0xfe6f, 1},
+		{0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x16fe4, 1},
+		{0x17000, 0x18cff, 1},
+		{0x1b000, 0x1b2ff, 1},
+		{0x1f004, 0x1f004, 1},
+		{0x1f0cf, 0x1f0cf, 1},
+		{0x1f18e, 0x1f18e, 1},
+		{0x1f191, 0x1f19a, 1},
+		{0x1f200, 0x1f251, 1},
+		{0x1f300, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f7e0, 0x1f7eb, 1},
+		{0x1f90c, 0x1f9ff, 1},
+		{0x1fa70, 0x1faff, 1},
+		{0x20000, 0x2fffd, 1},
+		{0x30000, 0x3fffd, 1},
+	},
+}
+
+// RuneWidth returns the number of columns r occupies.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || r == '\u200b' || r == '\u200c' || r == zwj || r == '\u2060' || r == '\ufeff':
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cc, unicode.Variation_Selector):
+		return 0
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// skin tone modifiers, drawn as part of the emoji before them
+		return 0
+	case r >= 0x1100 && unicode.Is(wide, r):
+		return 2
+	}
+	return 1
+}
+
+// Width returns the number of columns s occupies.
+func Width(s string) int {
+	n := 0
+	var prev rune
+	for _, r := range s {
+		if prev != zwj {
+			n += RuneWidth(r)
+		}
+		prev = r
+	}
+	return n
+}
+
+// zwj is the zero width joiner, which joins emoji into one, as in "👨‍🍳".
+const zwj = '\u200d'
+
+// extends reports whether r is drawn as part of the character before it,
+// prev, so text must not be broken between them.
+func extends(prev, r rune) bool {
+	return prev == zwj || RuneWidth(r) == 0
+}
+
+// Pad appends spaces to s until it is width columns wide.
+func Pad(s string, width int) string {
+	if n := Width(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// Wrap breaks s into lines of at most width columns. Lines are broken at
+// spaces and, in text without spaces between words such as Chinese and
+// Japanese, between wide characters, but never before closing punctuation
+// like "。" or after opening punctuation like "「". A word wider than width
+// is broken between characters. Runs of white space are collapsed.
+func Wrap(s string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, word := range strings.Fields(s) {
+		for i, seg := range segments(word) {
+			w := Width(seg)
+			sep := 0
+			if lineWidth > 0 && i == 0 {
+				sep = 1
+			}
+			if lineWidth > 0 && lineWidth+sep+w > width {
+				lines = append(lines, line.String())
+				line.Reset()
+				lineWidth, sep = 0, 0
+			}
+			for lineWidth == 0 && w > width {
+				var head string
+				head, seg = cut(seg, width)
+				lines = append(lines, head)
+				w = Width(seg)
+			}
+			if seg == "" {
+				continue
+			}
+			if sep > 0 {
+				line.WriteByte(' ')
+			}
+			line.WriteString(seg)
+			lineWidth += sep + w
+		}
+	}
+	if lineWidth > 0 || len(lines) == 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// segments splits a word into the pieces a line may be broken between:
+// the word itself, or each wide character with its attached punctuation.
+func segments(word string) []string {
+	var segs []string
+	start := 0
+	for i, r := range word {
+		if i == start {
+			continue
+		}
+		prev, _ := utf8.DecodeLastRuneInString(word[:i])
+		if extends(prev, r) {
+			continue
+		}
+		if (RuneWidth(prev) == 2 || RuneWidth(r) == 2) &&
+			!strings.ContainsRune(noLineStart, r) && !strings.ContainsRune(noLineEnd, prev) {
+			segs = append(segs, word[start:i])
+			start = i
+		}
+	}
+	return append(segs, word[start:])
+}
+
+// Characters lines must not start or end with (kinsoku shori).
+const (
+	noLineStart = ")]}」』】〕〉》）］｝、。，．・：；！？ーぁぃぅぇぉっゃゅょゎァィゥェォッャュョヮヵヶ々〻,.:;!?"
+	noLineEnd   = "([{「『【〔〈《（［｛"
+)
+
+// cut returns the longest prefix of s at most width columns wide, with at
+// least one character, and the rest of s.
+func cut(s string, width int) (string, string) {
+	w := 0
+	var prev rune
+	for i, r := range s {
+		if i > 0 && extends(prev, r) {
+			prev = r
+			continue
+		}
+		rw := RuneWidth(r)
+		if w+rw > width && i > 0 {
+			return s[:i], s[i:]
+		}
+		w += rw
+		prev = r
+	}
+	return s, ""
+}
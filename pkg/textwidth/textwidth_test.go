@@ -0,0 +1,75 @@
+package textwidth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"Pancakes", 8},
+		{"Crème brûlée", 12},
+		{"é", 1},
+		{"麻婆豆腐", 8},
+		{"ｶﾚｰ", 3},
+		{"カレー", 6},
+		{"김치", 4},
+		{"🌮 Tacos", 8},
+		{"❤️", 1},
+		{"👍", 2},
+		{"👍🏽", 2},
+		{"👨‍👩‍👧", 2},
+		{"👩🏽‍🍳 Chef", 7},
+		{"a​b", 2},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.in); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 3, "abcdef"},
+		{"麻婆", 6, "麻婆  "},
+		{"👍🏽", 4, "👍🏽  "},
+		{"👨‍👩‍👧", 3, "👨‍👩‍👧 "},
+	}
+	for _, tt := range tests {
+		if got := Pad(tt.in, tt.width); got != tt.want {
+			t.Errorf("Pad(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{""}},
+		{"mix the flour and the sugar", 10, []string{"mix the", "flour and", "the sugar"}},
+		{"  spaced   out  ", 20, []string{"spaced out"}},
+		{"supercalifragilistic", 8, []string{"supercal", "ifragili", "stic"}},
+		{"豆腐を切る。", 6, []string{"豆腐を", "切る。"}},
+		{"「豆腐」を切る", 4, []string{"「豆", "腐」", "を切", "る"}},
+		{"👨‍👩‍👧👨‍👩‍👧👨‍👩‍👧", 4, []string{"👨‍👩‍👧👨‍👩‍👧", "👨‍👩‍👧"}},
+		{"👍🏽👍🏽👍🏽", 3, []string{"👍🏽", "👍🏽", "👍🏽"}},
+	}
+	for _, tt := range tests {
+		if got := Wrap(tt.in, tt.width); !slices.Equal(got, tt.want) {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+// Package units recognizes the units of cooking amounts, picks their
+// singular or plural form and converts amounts between metric and US
+// customary units. The package level functions use the built-in units;
+// a Registry from NewRegistry can learn further units and spellings.
+package units
+
+import (
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xcapaldi/recipemd-go/pkg/amount"
+)
+
+// Class is the physical quantity a unit measures.
+type Class string
+
+// The unit classes.
+const (
+	Volume Class = "volume"
+	Mass   Class = "mass"
+	Count  Class = "count" // units counting things, such as "clove", which are never converted
+)
+
+// Classes are the unit classes amounts are converted between.
+var Classes = []Class{Volume, Mass}
+
+// System is a system of units.
+type System int
+
+// The unit systems.
+const (
+	Metric System = iota
+	US
+)
+
+// Unit is a unit of measurement.
+type Unit struct {
+	Symbol string // used when formatting converted amounts
+	Plural string // Symbol for amounts greater than one, if different
+	Class  Class
+	System System
+	base   *big.Rat // size in milliliters or grams; nil for counts
+}
+
+// NewUnit returns a unit of the class whose size is base milliliters for
+// volumes and base grams for masses. Units of the class Count have no
+// size and base is ignored.
+func NewUnit(symbol, plural string, class Class, system System, base string) *Unit {
+	u := &Unit{Symbol: symbol, Plural: plural, Class: class, System: system}
+	if class != Count {
+		u.base, _ = new(big.Rat).SetString(base)
+	}
+	return u
+}
+
+// Name returns the symbol to use for the factor f.
+func (u *Unit) Name(f *big.Rat) string {
+	if u.Plural != "" && plural(f) {
+		return u.Plural
+	}
+	return u.Symbol
+}
+
+// plural reports whether an amount of f takes the plural of a unit: "1/2
+// cup" and "1 cup", but "1 1/2 cups" and "0 cups".
+func plural(f *big.Rat) bool {
+	return f.Sign() <= 0 || f.Cmp(big.NewRat(1, 1)) > 0
+}
+
+var (
+	milliliter = NewUnit("ml", "", Volume, Metric, "1")
+	centiliter = NewUnit("cl", "", Volume, Metric, "10")
+	deciliter  = NewUnit("dl", "", Volume, Metric, "100")
+	liter      = NewUnit("l", "", Volume, Metric, "1000")
+	teaspoon   = NewUnit("tsp", "", Volume, US, "4.92892159375")
+	tablespoon = NewUnit("tbsp", "", Volume, US, "14.78676478125")
+	fluidOunce = NewUnit("fl oz", "", Volume, US, "29.5735295625")
+	cup        = NewUnit("cup", "cups", Volume, US, "236.5882365")
+	pint       = NewUnit("pint", "pints", Volume, US, "473.176473")
+	quart      = NewUnit("quart", "quarts", Volume, US, "946.352946")
+	gallon     = NewUnit("gallon", "gallons", Volume, US, "3785.411784")
+	milligram  = NewUnit("mg", "", Mass, Metric, "0.001")
+	gram       = NewUnit("g", "", Mass, Metric, "1")
+	kilogram   = NewUnit("kg", "", Mass, Metric, "1000")
+	ounce      = NewUnit("oz", "", Mass, US, "28.349523125")
+	pound      = NewUnit("lb", "", Mass, US, "453.59237")
+)
+
+// A Registry knows the spellings of units. A spelling is either used for
+// any amount, like "g", or has a singular and a plural form, like
+// "cup/cups", which Inflect picks from by amount.
+type Registry struct {
+	spellings map[string]spelling
+}
+
+type spelling struct {
+	unit       *Unit
+	one, other string
+}
+
+// NewRegistry returns a registry of the built-in units: metric and US
+// volumes and masses with their common English spellings, the German "EL"
+// and "TL", and counting units such as "clove/cloves" and
+// "pinch/pinches".
+func NewRegistry() *Registry {
+	r := &Registry{spellings: make(map[string]spelling)}
+	r.Add(milliliter, "ml", "milliliter/milliliters", "millilitre/millilitres")
+	r.Add(centiliter, "cl", "centiliter/centiliters", "centilitre/centilitres")
+	r.Add(deciliter, "dl", "deciliter/deciliters", "decilitre/decilitres")
+	r.Add(liter, "l", "liter/liters", "litre/litres")
+	r.Add(teaspoon, "tsp", "tsp.", "teaspoon/teaspoons", "TL")
+	r.Add(tablespoon, "tbsp", "tbsp.", "tablespoon/tablespoons", "EL")
+	r.Add(fluidOunce, "fl oz", "fl. oz.", "fluid ounce/fluid ounces")
+	r.Add(cup, "cup/cups")
+	r.Add(pint, "pint/pints", "pt")
+	r.Add(quart, "quart/quarts", "qt")
+	r.Add(gallon, "gallon/gallons", "gal")
+	r.Add(milligram, "mg", "milligram/milligrams")
+	r.Add(gram, "g", "gram/grams", "gramme/grammes")
+	r.Add(kilogram, "kg", "kilogram/kilograms")
+	r.Add(ounce, "oz", "oz.", "ounce/ounces")
+	r.Add(pound, "lb/lbs", "lb./lbs.", "pound/pounds")
+	for _, name := range []string{
+		"bunch/bunches", "can/cans", "clove/cloves", "dash/dashes",
+		"handful/handfuls", "loaf/loaves", "piece/pieces", "pinch/pinches",
+		"portion/portions", "serving/servings", "slice/slices",
+		"sprig/sprigs", "stick/sticks",
+	} {
+		one, other, _ := strings.Cut(name, "/")
+		r.Add(NewUnit(one, other, Count, Metric, ""), name)
+	}
+	return r
+}
+
+// builtin is the registry of the package level functions.
+var builtin = NewRegistry()
+
+func init() {
+	amount.RegisterMeasure(func(s string) bool {
+		u, ok := Lookup(s)
+		return ok && u.Class != Count
+	})
+}
+
+// Add registers the spellings of u, each either a single form such as
+// "g" or a singular and a plural form separated by a slash, such as
+// "gram/grams". Spellings match ignoring case and replace earlier
+// registrations.
+func (r *Registry) Add(u *Unit, spellings ...string) {
+	for _, s := range spellings {
+		one, other, ok := strings.Cut(s, "/")
+		if !ok {
+			other = one
+		}
+		sp := spelling{unit: u, one: one, other: other}
+		r.spellings[key(one)] = sp
+		r.spellings[key(other)] = sp
+	}
+}
+
+// Alias registers further spellings of the unit spelled unit, such as
+// regional names. It reports false if unit is unknown.
+func (r *Registry) Alias(unit string, spellings ...string) bool {
+	u, ok := r.Lookup(unit)
+	if ok {
+		r.Add(u, spellings...)
+	}
+	return ok
+}
+
+func key(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Lookup returns the unit spelled s, ignoring case and surrounding white
+// space.
+func (r *Registry) Lookup(s string) (*Unit, bool) {
+	sp, ok := r.spellings[key(s)]
+	return sp.unit, ok
+}
+
+// Lookup returns the built-in unit spelled s, ignoring case and
+// surrounding white space.
+func Lookup(s string) (*Unit, bool) {
+	return builtin.Lookup(s)
+}
+
+// Inflect returns the form of the unit spelling s that fits an amount of
+// f: "cups" for 1 becomes "cup" and "clove" for 2 becomes "cloves".
+// Unknown spellings and those without a plural are returned unchanged; an
+// upper case first letter is kept.
+func (r *Registry) Inflect(s string, f *big.Rat) string {
+	sp, ok := r.spellings[key(s)]
+	if !ok || f == nil || sp.one == sp.other {
+		return s
+	}
+	form := sp.one
+	if plural(f) {
+		form = sp.other
+	}
+	if key(form) == key(s) {
+		return s
+	}
+	if first, _ := utf8.DecodeRuneInString(s); unicode.IsUpper(first) {
+		r, size := utf8.DecodeRuneInString(form)
+		form = string(unicode.ToUpper(r)) + form[size:]
+	}
+	return form
+}
+
+// Inflect returns the form of the built-in unit spelling s that fits an
+// amount of f.
+func Inflect(s string, f *big.Rat) string {
+	return builtin.Inflect(s, f)
+}
+
+// Singular returns the singular form of the unit spelling s, or s if it
+// is unknown.
+func (r *Registry) Singular(s string) string {
+	if sp, ok := r.spellings[key(s)]; ok {
+		return sp.one
+	}
+	return s
+}
+
+// Singular returns the singular form of the built-in unit spelling s.
+func Singular(s string) string {
+	return builtin.Singular(s)
+}
+
+// targets are the units amounts are converted to, by class and system,
+// from the largest to the smallest, each with the step converted factors
+// are rounded to.
+var targets = map[Class]map[System][]target{
+	Volume: {
+		Metric: {{liter, big.NewRat(1, 10), "1000"}, {milliliter, nil, "0"}},
+		US:     {{cup, big.NewRat(1, 4), "59"}, {tablespoon, big.NewRat(1, 2), "14"}, {teaspoon, big.NewRat(1, 4), "0"}},
+	},
+	Mass: {
+		Metric: {{kilogram, big.NewRat(1, 10), "1000"}, {gram, nil, "0"}},
+		US:     {{pound, big.NewRat(1, 4), "450"}, {ounce, big.NewRat(1, 4), "0"}},
+	},
+}
+
+// target is a unit to convert to if the size of the amount in milliliters
+// or grams is at least min.
+type target struct {
+	unit *Unit
+	step *big.Rat // nil for metric base units, see roundMetric
+	min  string
+}
+
+// Convert converts a to the system using the built-in units. It reports
+// false if a has no factor, its unit is unknown, a count or already of the
+// system.
+func Convert(a amount.Amount, system System) (amount.Amount, bool) {
+	return builtin.Convert(a, system)
+}
+
+// Convert converts a to the system. It reports false if a has no factor,
+// its unit is unknown, a count or already of the system.
+func (r *Registry) Convert(a amount.Amount, system System) (amount.Amount, bool) {
+	if a.Factor == nil {
+		return amount.Amount{}, false
+	}
+	u, ok := r.Lookup(a.Unit)
+	if !ok || u.Class == Count || u.System == system {
+		return amount.Amount{}, false
+	}
+	size := new(big.Rat).Mul(a.Factor, u.base)
+	for _, t := range targets[u.Class][system] {
+		min, _ := new(big.Rat).SetString(t.min)
+		if size.Cmp(min) < 0 {
+			continue
+		}
+		f := t.convert(size)
+		if f.Sign() == 0 {
+			continue
+		}
+		c := amount.Amount{Factor: f, Unit: t.unit.Name(f), Approx: a.Approx}
+		if a.Max != nil {
+			c.Max = t.convert(new(big.Rat).Mul(a.Max, u.base))
+			c.Unit = t.unit.Name(c.Max)
+		}
+		return c, true
+	}
+	return amount.Amount{}, false
+}
+
+// convert returns size, in milliliters or grams, in the unit of t,
+// rounded.
+func (t target) convert(size *big.Rat) *big.Rat {
+	f := new(big.Rat).Quo(size, t.unit.base)
+	if t.step == nil {
+		return roundMetric(f)
+	}
+	return round(f, t.step)
+}
+
+// roundMetric rounds small amounts to halves, amounts below 100 to whole
+// numbers and larger amounts to tens.
+func roundMetric(f *big.Rat) *big.Rat {
+	switch {
+	case f.Cmp(big.NewRat(10, 1)) < 0:
+		return round(f, big.NewRat(1, 2))
+	case f.Cmp(big.NewRat(100, 1)) < 0:
+		return round(f, big.NewRat(1, 1))
+	}
+	return round(f, big.NewRat(10, 1))
+}
+
+// round rounds f to the nearest multiple of step, rounding halves up.
+func round(f, step *big.Rat) *big.Rat {
+	q := new(big.Rat).Quo(f, step)
+	q.Add(q, big.NewRat(1, 2))
+	n := new(big.Int).Quo(q.Num(), q.Denom())
+	return new(big.Rat).Mul(new(big.Rat).SetInt(n), step)
+}
+
+// Ratio returns how many of the built-in unit spelled to make up one of
+// the unit spelled from. It reports false unless both units are known and
+// of the same class, which is not Count.
+func Ratio(from, to string) (*big.Rat, bool) {
+	return builtin.Ratio(from, to)
+}
+
+// Ratio returns how many of the unit spelled to make up one of the unit
+// spelled from. It reports false unless both units are known and of the
+// same class, which is not Count.
+func (r *Registry) Ratio(from, to string) (*big.Rat, bool) {
+	f, ok := r.Lookup(from)
+	if !ok {
+		return nil, false
+	}
+	t, ok := r.Lookup(to)
+	if !ok || f.Class != t.Class || f.Class == Count {
+		return nil, false
+	}
+	return new(big.Rat).Quo(f.base, t.base), true
+}